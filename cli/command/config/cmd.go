@@ -24,6 +24,7 @@ func NewConfigCommand(dockerCli command.Cli) *cobra.Command {
 		newConfigCreateCommand(dockerCli),
 		newConfigInspectCommand(dockerCli),
 		newConfigRemoveCommand(dockerCli),
+		newConfigRotateCommand(dockerCli),
 	)
 	return cmd
 }
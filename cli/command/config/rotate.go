@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// RotateOptions contains options for the docker config rotate command.
+type RotateOptions struct {
+	Config string
+	File   string
+	Name   string
+	DryRun bool
+	Quiet  bool
+}
+
+func newConfigRotateCommand(dockerCli command.Cli) *cobra.Command {
+	rotateOpts := RotateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rotate [OPTIONS] CONFIG file|-",
+		Short: "Replace a config in all the services that use it",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rotateOpts.Config = args[0]
+			rotateOpts.File = args[1]
+			return RunConfigRotate(dockerCli, rotateOpts)
+		},
+		Annotations: map[string]string{"version": "1.29"},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&rotateOpts.Name, "name", "", `Name of the new config (default: the name of the config with a ".v<version>" suffix)`)
+	flags.BoolVar(&rotateOpts.DryRun, "dry-run", false, "Only list the services that use the config")
+	flags.BoolVarP(&rotateOpts.Quiet, "quiet", "q", false, "Suppress progress output")
+
+	return cmd
+}
+
+// RunConfigRotate creates a new version of a config, replaces the config with
+// it in the services that use it, and removes the config once the services
+// are updated.
+func RunConfigRotate(dockerCli command.Cli, options RotateOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	config, _, err := client.ConfigInspectWithRaw(ctx, options.Config)
+	if err != nil {
+		return err
+	}
+	name := options.Name
+	if name == "" {
+		name = service.NextVersion(config.Spec.Name)
+	}
+
+	return service.Rotate(ctx, dockerCli, service.Rotation{
+		Kind:    "config",
+		ID:      config.ID,
+		Name:    config.Spec.Name,
+		NewName: name,
+		DryRun:  options.DryRun,
+		Quiet:   options.Quiet,
+		Uses: func(containerSpec *swarm.ContainerSpec) bool {
+			return usesConfig(containerSpec, config.ID)
+		},
+		Create: func(ctx context.Context) (string, error) {
+			var in io.Reader = dockerCli.In()
+			if options.File != "-" {
+				file, err := system.OpenSequential(options.File)
+				if err != nil {
+					return "", err
+				}
+				in = file
+				defer file.Close()
+			}
+			data, err := ioutil.ReadAll(in)
+			if err != nil {
+				return "", errors.Errorf("Error reading content from %q: %v", options.File, err)
+			}
+
+			spec := config.Spec
+			spec.Name = name
+			spec.Data = data
+			response, err := client.ConfigCreate(ctx, spec)
+			return response.ID, err
+		},
+		Replace: func(containerSpec *swarm.ContainerSpec, newID string) {
+			replaceConfig(containerSpec, config.ID, newID, name)
+		},
+		Remove: func(ctx context.Context) error {
+			return client.ConfigRemove(ctx, config.ID)
+		},
+	})
+}
+
+// usesConfig returns whether the container uses the config
+func usesConfig(containerSpec *swarm.ContainerSpec, configID string) bool {
+	for _, ref := range containerSpec.Configs {
+		if ref.ConfigID == configID {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceConfig replaces the references to a config with references to the
+// new config, keeping their target files, including the reference of the
+// credential spec of the container
+func replaceConfig(containerSpec *swarm.ContainerSpec, oldID, newID, newName string) {
+	refs := make([]*swarm.ConfigReference, 0, len(containerSpec.Configs))
+	for _, ref := range containerSpec.Configs {
+		if ref.ConfigID == oldID {
+			newRef := *ref
+			newRef.ConfigID = newID
+			newRef.ConfigName = newName
+			ref = &newRef
+		}
+		refs = append(refs, ref)
+	}
+	containerSpec.Configs = refs
+
+	if privileges := containerSpec.Privileges; privileges != nil && privileges.CredentialSpec != nil && privileges.CredentialSpec.Config == oldID {
+		credentialSpec := *privileges.CredentialSpec
+		credentialSpec.Config = newID
+		newPrivileges := *privileges
+		newPrivileges.CredentialSpec = &credentialSpec
+		containerSpec.Privileges = &newPrivileges
+	}
+}
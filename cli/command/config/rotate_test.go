@@ -0,0 +1,259 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestConfigRotate(t *testing.T) {
+	file := fs.NewFile(t, "config", fs.WithContent("listen 8080;"))
+	defer file.Remove()
+
+	services := []swarm.Service{
+		{
+			ID: "web-id",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "web"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Configs: []*swarm.ConfigReference{
+						{ConfigID: "nginx-id", ConfigName: "nginx.v2", File: &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx/nginx.conf", UID: "101", GID: "101", Mode: 0440}},
+					},
+				}},
+			},
+		},
+		{
+			ID: "win-id",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "win"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Configs: []*swarm.ConfigReference{
+						{ConfigID: "nginx-id", ConfigName: "nginx.v2", Runtime: &swarm.ConfigReferenceRuntimeTarget{}},
+					},
+					Privileges: &swarm.Privileges{CredentialSpec: &swarm.CredentialSpec{Config: "nginx-id"}},
+				}},
+			},
+		},
+	}
+
+	var (
+		created swarm.ConfigSpec
+		updated = map[string]swarm.ServiceSpec{}
+		removed string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		configInspectFunc: func(name string) (swarm.Config, []byte, error) {
+			return swarm.Config{ID: "nginx-id", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "nginx.v2"}}}, nil, nil
+		},
+		configCreateFunc: func(spec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+			created = spec
+			return types.ConfigCreateResponse{ID: "nginx-v3-id"}, nil
+		},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec) (types.ServiceUpdateResponse, error) {
+			updated[serviceID] = spec
+			return types.ServiceUpdateResponse{}, nil
+		},
+		configRemoveFunc: func(name string) error {
+			removed = name
+			return nil
+		},
+	})
+	cmd := newConfigRotateCommand(cli)
+	cmd.SetArgs([]string{"nginx.v2", file.Path()})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal("nginx.v3", created.Name))
+	assert.Check(t, is.Equal("listen 8080;", string(created.Data)))
+	assert.Check(t, is.DeepEqual([]*swarm.ConfigReference{
+		{ConfigID: "nginx-v3-id", ConfigName: "nginx.v3", File: &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx/nginx.conf", UID: "101", GID: "101", Mode: 0440}},
+	}, updated["web-id"].TaskTemplate.ContainerSpec.Configs))
+	assert.Check(t, is.Equal("nginx-v3-id", updated["win-id"].TaskTemplate.ContainerSpec.Privileges.CredentialSpec.Config))
+	assert.Check(t, is.Equal("nginx-id", removed))
+
+	expected := `Created config nginx.v3
+Updating service web
+Updating service win
+Removed config nginx.v2
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestConfigRotateDryRun(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		configInspectFunc: func(name string) (swarm.Config, []byte, error) {
+			return swarm.Config{ID: "nginx-id", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "nginx"}}}, nil, nil
+		},
+	})
+	cmd := newConfigRotateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "nginx", "nginx.conf"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("No service uses config nginx\n", cli.OutBuffer().String()))
+}
+
+func TestConfigRotateDryRunServices(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		configInspectFunc: func(name string) (swarm.Config, []byte, error) {
+			return swarm.Config{ID: "nginx-id", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "nginx.v4"}}}, nil, nil
+		},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{
+				{Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "web"},
+					TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+						Configs: []*swarm.ConfigReference{{ConfigID: "nginx-id", ConfigName: "nginx.v4"}},
+					}},
+				}},
+				{Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "cache"}}},
+				{Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "proxy"},
+					TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+						Configs: []*swarm.ConfigReference{{ConfigID: "nginx-id", ConfigName: "nginx.v4"}},
+					}},
+				}},
+			}, nil
+		},
+		configCreateFunc: func(spec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+			return types.ConfigCreateResponse{}, errors.New("unexpected create")
+		},
+	})
+	cmd := newConfigRotateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "nginx.v4", "nginx.conf"})
+	assert.NilError(t, cmd.Execute())
+
+	expected := `Config nginx.v4 would be replaced with nginx.v5 in services:
+  proxy
+  web
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestConfigRotateErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"nginx"},
+			expectedError: "requires exactly 2 arguments",
+		},
+		{
+			args:          []string{"nginx", "/no/such/nginx.conf"},
+			expectedError: "no such file or directory",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newConfigRotateCommand(
+			test.NewFakeCli(&fakeClient{
+				configInspectFunc: func(name string) (swarm.Config, []byte, error) {
+					return swarm.Config{ID: "nginx-id", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: name}}}, nil, nil
+				},
+				configCreateFunc: func(spec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+					return types.ConfigCreateResponse{}, errors.New("unexpected create")
+				},
+			}),
+		)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+	}
+}
+
+func TestConfigRotateUpdateError(t *testing.T) {
+	removed := false
+	cli := test.NewFakeCli(&fakeClient{
+		configInspectFunc: func(name string) (swarm.Config, []byte, error) {
+			return swarm.Config{ID: "nginx-id", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "nginx"}}}, nil, nil
+		},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{{
+				ID: "web-id",
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "web"},
+					TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+						Configs: []*swarm.ConfigReference{{ConfigID: "nginx-id", ConfigName: "nginx"}},
+					}},
+				},
+			}}, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec) (types.ServiceUpdateResponse, error) {
+			return types.ServiceUpdateResponse{}, errors.New("update out of sequence")
+		},
+		configRemoveFunc: func(name string) error {
+			removed = true
+			return nil
+		},
+	})
+	cmd := newConfigRotateCommand(cli)
+	cmd.SetArgs([]string{"nginx", os.DevNull})
+	assert.Check(t, is.Error(cmd.Execute(), "failed to update service web: update out of sequence"))
+	assert.Check(t, !removed)
+}
+
+func TestReplaceConfigCredentialSpec(t *testing.T) {
+	testCases := []struct {
+		name             string
+		privileges       *swarm.Privileges
+		expectedCredSpec *swarm.CredentialSpec
+	}{
+		{
+			name: "no-privileges",
+		},
+		{
+			name:       "no-credential-spec",
+			privileges: &swarm.Privileges{},
+		},
+		{
+			name:             "credential-spec-from-file",
+			privileges:       &swarm.Privileges{CredentialSpec: &swarm.CredentialSpec{File: "spec.json"}},
+			expectedCredSpec: &swarm.CredentialSpec{File: "spec.json"},
+		},
+		{
+			name:             "credential-spec-from-other-config",
+			privileges:       &swarm.Privileges{CredentialSpec: &swarm.CredentialSpec{Config: "other-id"}},
+			expectedCredSpec: &swarm.CredentialSpec{Config: "other-id"},
+		},
+		{
+			name:             "credential-spec-from-config",
+			privileges:       &swarm.Privileges{CredentialSpec: &swarm.CredentialSpec{Config: "credspec-id"}},
+			expectedCredSpec: &swarm.CredentialSpec{Config: "credspec-v2-id"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			refs := []*swarm.ConfigReference{
+				{ConfigID: "credspec-id", ConfigName: "credspec", Runtime: &swarm.ConfigReferenceRuntimeTarget{}},
+				{ConfigID: "other-id", ConfigName: "other", Runtime: &swarm.ConfigReferenceRuntimeTarget{}},
+			}
+			containerSpec := &swarm.ContainerSpec{Configs: refs, Privileges: tc.privileges}
+			replaceConfig(containerSpec, "credspec-id", "credspec-v2-id", "credspec.v2")
+
+			assert.Check(t, is.DeepEqual([]*swarm.ConfigReference{
+				{ConfigID: "credspec-v2-id", ConfigName: "credspec.v2", Runtime: &swarm.ConfigReferenceRuntimeTarget{}},
+				{ConfigID: "other-id", ConfigName: "other", Runtime: &swarm.ConfigReferenceRuntimeTarget{}},
+			}, containerSpec.Configs))
+			// the references of the original spec are left unchanged
+			assert.Check(t, is.Equal("credspec-id", refs[0].ConfigID))
+
+			if tc.privileges == nil {
+				assert.Check(t, is.Nil(containerSpec.Privileges))
+				return
+			}
+			assert.Check(t, is.DeepEqual(tc.expectedCredSpec, containerSpec.Privileges.CredentialSpec))
+			if tc.privileges.CredentialSpec != nil {
+				// the privileges of the original spec are left unchanged
+				assert.Check(t, tc.privileges.CredentialSpec.Config != "credspec-v2-id")
+			}
+		})
+	}
+}
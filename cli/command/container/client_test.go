@@ -29,6 +29,7 @@ type fakeClient struct {
 	containerListFunc       func(types.ContainerListOptions) ([]types.Container, error)
 	containerExportFunc     func(string) (io.ReadCloser, error)
 	containerExecResizeFunc func(id string, options types.ResizeOptions) error
+	imageInspectFunc        func(image string) (types.ImageInspect, []byte, error)
 	Version                 string
 }
 
@@ -46,6 +47,13 @@ func (f *fakeClient) ContainerInspect(_ context.Context, containerID string) (ty
 	return types.ContainerJSON{}, nil
 }
 
+func (f *fakeClient) ImageInspectWithRaw(_ context.Context, image string) (types.ImageInspect, []byte, error) {
+	if f.imageInspectFunc != nil {
+		return f.imageInspectFunc(image)
+	}
+	return types.ImageInspect{}, nil, nil
+}
+
 func (f *fakeClient) ContainerExecCreate(_ context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
 	if f.execCreateFunc != nil {
 		return f.execCreateFunc(container, config)
@@ -2,13 +2,19 @@ package container
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/inspect"
+	"github.com/docker/cli/cli/compose/convert"
 	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
 )
 
+// composeFormat is the --format value to generate a Compose file
+const composeFormat = "compose"
+
 type inspectOptions struct {
 	format string
 	size   bool
@@ -30,7 +36,7 @@ func newInspectCommand(dockerCli command.Cli) *cobra.Command {
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&opts.format, "format", "f", "", "Format the output using the given Go template")
+	flags.StringVarP(&opts.format, "format", "f", "", `Format the output using the given Go template, or "compose" to generate a Compose file`)
 	flags.BoolVarP(&opts.size, "size", "s", false, "Display total file sizes")
 
 	return cmd
@@ -40,8 +46,39 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	client := dockerCli.Client()
 	ctx := context.Background()
 
+	if opts.format == composeFormat {
+		return runInspectCompose(ctx, dockerCli, opts.refs)
+	}
+
 	getRefFunc := func(ref string) (interface{}, []byte, error) {
 		return client.ContainerInspectWithRaw(ctx, ref, opts.size)
 	}
 	return inspect.Inspect(dockerCli.Out(), opts.refs, opts.format, getRefFunc)
 }
+
+// runInspectCompose generates a Compose file with a service for each
+// container, and reports what cannot be expressed in it
+func runInspectCompose(ctx context.Context, dockerCli command.Cli, refs []string) error {
+	client := dockerCli.Client()
+	export := convert.NewExport()
+	for _, ref := range refs {
+		container, err := client.ContainerInspect(ctx, ref)
+		if err != nil {
+			return err
+		}
+		image, _, err := client.ImageInspectWithRaw(ctx, container.Image)
+		if err != nil {
+			return err
+		}
+		export.AddContainer(container, image)
+	}
+	for _, warning := range export.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+	out, err := yaml.Marshal(export.Config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(dockerCli.Out(), string(out))
+	return nil
+}
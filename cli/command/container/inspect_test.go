@@ -0,0 +1,42 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestInspectFormatCompose(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(ref string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{
+					ID:         "0123456789abcdef",
+					Name:       "/" + ref,
+					Image:      "sha256:" + ref,
+					HostConfig: &container.HostConfig{Privileged: true},
+				},
+				Config: &container.Config{Image: ref, Env: []string{"PATH=/usr/bin"}},
+			}, nil
+		},
+		imageInspectFunc: func(image string) (types.ImageInspect, []byte, error) {
+			return types.ImageInspect{Config: &container.Config{Env: []string{"PATH=/usr/bin"}}}, nil, nil
+		},
+	})
+	cmd := newInspectCommand(cli)
+	cmd.SetArgs([]string{"--format", "compose", "redis", "nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`version: "3.8"
+services:
+  nginx:
+    image: nginx
+    privileged: true
+  redis:
+    image: redis
+    privileged: true
+`, cli.OutBuffer().String()))
+}
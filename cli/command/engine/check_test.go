@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/assert"
 	"gotest.tools/golden"
 )
@@ -46,6 +47,12 @@ func (c testRegistryClient) GetManifest(ctx context.Context, ref reference.Named
 func (c testRegistryClient) GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
 	return nil, nil
 }
+func (c testRegistryClient) GetManifestListPayload(ctx context.Context, ref reference.Named) (string, []byte, error) {
+	return "", nil, nil
+}
+func (c testRegistryClient) GetManifestDescriptor(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	return ocispec.Descriptor{}, nil
+}
 func (c testRegistryClient) MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/docker/cli/cli"
@@ -76,6 +77,7 @@ type buildOptions struct {
 	secrets        []string
 	ssh            []string
 	outputs        []string
+	printContext   bool
 }
 
 // dockerfileFromStdin returns true when the user specified that the Dockerfile
@@ -101,6 +103,71 @@ func newBuildOptions() buildOptions {
 	}
 }
 
+// BuildImageOptions are the options to build an image with BuildImage
+type BuildImageOptions struct {
+	// Context is the path or URL of the build context
+	Context string
+	// Dockerfile is the path of the Dockerfile, if it is not the Dockerfile
+	// at the root of a local context
+	Dockerfile  string
+	Tags        []string
+	BuildArgs   map[string]*string
+	Labels      map[string]string
+	CacheFrom   []string
+	NetworkMode string
+	Target      string
+	NoCache     bool
+	Pull        bool
+	Quiet       bool
+}
+
+// BuildImage builds an image like `docker build`, using BuildKit if it is
+// enabled
+func BuildImage(dockerCli command.Cli, opts BuildImageOptions) error {
+	options := newBuildOptions()
+	options.context = opts.Context
+	options.dockerfileName = opts.Dockerfile
+	options.cacheFrom = opts.CacheFrom
+	options.networkMode = opts.NetworkMode
+	options.target = opts.Target
+	options.noCache = opts.NoCache
+	options.pull = opts.Pull
+	options.quiet = opts.Quiet
+	options.rm = true
+	options.progress = "auto"
+	options.untrusted = !dockerCli.ContentTrustEnabled()
+
+	for _, tag := range opts.Tags {
+		if err := options.tags.Set(tag); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(opts.BuildArgs) {
+		arg := key
+		if value := opts.BuildArgs[key]; value != nil {
+			arg = key + "=" + *value
+		}
+		if err := options.buildArgs.Set(arg); err != nil {
+			return err
+		}
+	}
+	for key, value := range opts.Labels {
+		if err := options.labels.Set(key + "=" + value); err != nil {
+			return err
+		}
+	}
+	return runBuild(dockerCli, options)
+}
+
+func sortedKeys(m map[string]*string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // NewBuildCommand creates a new `docker build` command
 func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 	options := newBuildOptions()
@@ -147,6 +214,7 @@ func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 	flags.Var(&options.extraHosts, "add-host", "Add a custom host-to-IP mapping (host:ip)")
 	flags.StringVar(&options.target, "target", "", "Set the target build stage to build.")
 	flags.StringVar(&options.imageIDFile, "iidfile", "", "Write the image ID to the file")
+	flags.BoolVar(&options.printContext, "print-context", false, "Print the files sent as build context and its size, without building")
 
 	command.AddTrustVerificationFlags(flags, &options.untrusted, dockerCli.ContentTrustEnabled())
 
@@ -205,6 +273,10 @@ func (out *lastProgressOutput) WriteProgress(prog progress.Progress) error {
 
 // nolint: gocyclo
 func runBuild(dockerCli command.Cli, options buildOptions) error {
+	if options.printContext {
+		return runPrintContext(dockerCli, options)
+	}
+
 	buildkitEnabled, err := command.BuildKitEnabled(dockerCli.ServerInfo())
 	if err != nil {
 		return err
@@ -374,6 +446,11 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 
 	var body io.Reader
 	if buildCtx != nil && !options.stream {
+		warningSize, err := contextWarningSize(dockerCli)
+		if err != nil {
+			return err
+		}
+		buildCtx = newContextSizeWarner(buildCtx, dockerCli.Err(), warningSize)
 		body = progress.NewProgressReader(buildCtx, progressOutput, 0, "", "Sending build context to Docker daemon")
 	}
 
@@ -0,0 +1,205 @@
+package build
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+// ContextReport describes the build context that is sent to the daemon for a
+// local context directory
+type ContextReport struct {
+	// Files lists the files in the context, in the order they are sent
+	Files []ContextEntry
+	// Excludes lists the exclude patterns, in the order they are applied,
+	// along with the number of paths each of them excluded
+	Excludes []ExcludeMatch
+	// Size is the size of the context archive
+	Size int64
+	// CompressedSize is the size of the context archive when compressed
+	// using gzip
+	CompressedSize int64
+}
+
+// ContextEntry is a file or directory in the build context, and its size.
+// The size of a directory is the total size of the files it contains.
+type ContextEntry struct {
+	Path string
+	Size int64
+}
+
+// ExcludeMatch is an exclude pattern, and the number of paths it excluded, or
+// an exception ("!" pattern), and the number of paths it included again. The
+// paths in an excluded directory are not counted separately, as the directory
+// is not walked.
+type ExcludeMatch struct {
+	Pattern string
+	Matches int
+}
+
+// GetContextReport archives the context directory exactly as it would be sent
+// to the daemon, and reports its contents and size
+func GetContextReport(contextDir string, excludes []string) (*ContextReport, error) {
+	contextRoot, err := getContextRoot(contextDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ContextReport{}
+	if err := readContextArchive(contextRoot, excludes, report); err != nil {
+		return nil, err
+	}
+	report.Excludes, err = matchExcludes(contextRoot, excludes)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// LargestDirectories returns up to n directories in the context with the
+// largest total size of the files they contain, largest first
+func (r *ContextReport) LargestDirectories(n int) []ContextEntry {
+	sizes := map[string]int64{}
+	for _, f := range r.Files {
+		for dir := path.Dir(f.Path); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			sizes[dir] += f.Size
+		}
+	}
+	dirs := make([]ContextEntry, 0, len(sizes))
+	for dir, size := range sizes {
+		dirs = append(dirs, ContextEntry{Path: dir, Size: size})
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].Size != dirs[j].Size {
+			return dirs[i].Size > dirs[j].Size
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+	if len(dirs) > n {
+		dirs = dirs[:n]
+	}
+	return dirs
+}
+
+// readContextArchive reads the context archive to list the files it contains,
+// and measures its size, both as is and compressed
+func readContextArchive(contextRoot string, excludes []string, report *ContextReport) error {
+	buildCtx, err := archive.TarWithOptions(contextRoot, &archive.TarOptions{
+		ExcludePatterns: excludes,
+		ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
+	})
+	if err != nil {
+		return err
+	}
+	defer buildCtx.Close()
+
+	compressed := &countingWriter{}
+	gz := gzip.NewWriter(compressed)
+	size := &countingWriter{}
+	tr := tar.NewReader(io.TeeReader(io.TeeReader(buildCtx, size), gz))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read build context")
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		report.Files = append(report.Files, ContextEntry{Path: hdr.Name, Size: hdr.Size})
+	}
+	// Consume the padding at the end of the archive
+	if _, err := io.Copy(gz, io.TeeReader(buildCtx, size)); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	report.Size = size.n
+	report.CompressedSize = compressed.n
+	return nil
+}
+
+// matchExcludes walks the context directory the way it is archived, and
+// counts the paths each exclude pattern excluded, and the paths each exception
+// included again. A path matched by more than one pattern is attributed to the
+// last one, which is the one that applies.
+func matchExcludes(contextRoot string, excludes []string) ([]ExcludeMatch, error) {
+	pm, err := fileutils.NewPatternMatcher(excludes)
+	if err != nil {
+		return nil, err
+	}
+	patterns := pm.Patterns()
+	matchers := make([]*fileutils.PatternMatcher, len(patterns))
+	matches := make([]ExcludeMatch, len(patterns))
+	for i, p := range patterns {
+		matchers[i], err = fileutils.NewPatternMatcher([]string{p.String()})
+		if err != nil {
+			return nil, err
+		}
+		matches[i].Pattern = p.String()
+		if p.Exclusion() {
+			matches[i].Pattern = "!" + p.String()
+		}
+	}
+
+	err = filepath.Walk(contextRoot, func(filePath string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relFilePath, err := filepath.Rel(contextRoot, filePath)
+		if err != nil || relFilePath == "." {
+			return err
+		}
+		skip, err := pm.Matches(relFilePath)
+		if err != nil {
+			return err
+		}
+
+		// the last pattern that matches the path applies: an exclude pattern
+		// if the path is excluded, and an exception if it is included again
+		for i := len(patterns) - 1; i >= 0; i-- {
+			if match, _ := matchers[i].Matches(relFilePath); match {
+				if patterns[i].Exclusion() != skip {
+					matches[i].Matches++
+				}
+				break
+			}
+		}
+
+		if !skip || !f.IsDir() {
+			return nil
+		}
+		// Like the archive, only walk an excluded directory if an exception
+		// may include one of its children
+		dirSlash := relFilePath + string(filepath.Separator)
+		for _, p := range patterns {
+			if p.Exclusion() && strings.HasPrefix(p.String()+string(filepath.Separator), dirSlash) {
+				return nil
+			}
+		}
+		return filepath.SkipDir
+	})
+	return matches, err
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
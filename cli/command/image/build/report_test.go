@@ -0,0 +1,76 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func createTestContext(t *testing.T) (string, func()) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-context-report-test")
+	for _, dir := range []string{"src/lib", "node_modules/left-pad", "logs"} {
+		assert.NilError(t, os.MkdirAll(filepath.Join(contextDir, dir), 0755))
+	}
+	createTestTempFile(t, contextDir, DefaultDockerfileName, dockerfileContents, 0644)
+	createTestTempFile(t, contextDir, ".dockerignore", "node_modules\n*.log\nlogs/*\n!logs/keep.log\n", 0644)
+	createTestTempFile(t, contextDir, "src/main.go", strings.Repeat("a", 100), 0644)
+	createTestTempFile(t, contextDir, "src/lib/lib.go", strings.Repeat("b", 300), 0644)
+	createTestTempFile(t, contextDir, "node_modules/left-pad/index.js", "module.exports = {}", 0644)
+	createTestTempFile(t, contextDir, "build.log", "log", 0644)
+	createTestTempFile(t, contextDir, "logs/debug.log", "log", 0644)
+	createTestTempFile(t, contextDir, "logs/keep.log", "log", 0644)
+	return contextDir, cleanup
+}
+
+func TestGetContextReport(t *testing.T) {
+	contextDir, cleanup := createTestContext(t)
+	defer cleanup()
+
+	excludes, err := ReadDockerignore(contextDir)
+	assert.NilError(t, err)
+	excludes = TrimBuildFilesFromExcludes(excludes, DefaultDockerfileName, false)
+
+	report, err := GetContextReport(contextDir, excludes)
+	assert.NilError(t, err)
+
+	paths := []string{}
+	for _, f := range report.Files {
+		paths = append(paths, f.Path)
+	}
+	assert.Check(t, is.DeepEqual([]string{
+		".dockerignore",
+		"Dockerfile",
+		"logs/keep.log",
+		"src/lib/lib.go",
+		"src/main.go",
+	}, paths))
+
+	assert.Check(t, is.DeepEqual([]ExcludeMatch{
+		{Pattern: "node_modules", Matches: 1},
+		{Pattern: "*.log", Matches: 1},
+		{Pattern: "logs/*", Matches: 1},
+		{Pattern: "!logs/keep.log", Matches: 1},
+	}, report.Excludes))
+
+	assert.Check(t, report.Size > 0)
+	assert.Check(t, report.CompressedSize > 0)
+	assert.Check(t, report.CompressedSize < report.Size)
+}
+
+func TestContextReportLargestDirectories(t *testing.T) {
+	report := &ContextReport{Files: []ContextEntry{
+		{Path: "Dockerfile", Size: 1000},
+		{Path: "logs/keep.log", Size: 3},
+		{Path: "src/lib/lib.go", Size: 300},
+		{Path: "src/main.go", Size: 100},
+	}}
+	assert.Check(t, is.DeepEqual([]ContextEntry{
+		{Path: "src", Size: 400},
+		{Path: "src/lib", Size: 300},
+	}, report.LargestDirectories(2)))
+	assert.Check(t, is.Len(report.LargestDirectories(10), 3))
+}
@@ -59,6 +59,11 @@ func runBuildBuildKit(dockerCli command.Cli, options buildOptions) error {
 		}
 	}
 
+	warningSize, err := contextWarningSize(dockerCli)
+	if err != nil {
+		return err
+	}
+
 	var (
 		remote           string
 		body             io.Reader
@@ -80,7 +85,7 @@ func runBuildBuildKit(dockerCli command.Cli, options buildOptions) error {
 			return err
 		}
 		if isArchive {
-			body = rc
+			body = newContextSizeWarner(rc, dockerCli.Err(), warningSize)
 			remote = uploadRequestRemote
 		} else {
 			if options.dockerfileName != "" {
@@ -160,11 +165,15 @@ func runBuildBuildKit(dockerCli command.Cli, options buildOptions) error {
 	}
 
 	if dockerfileDir != "" {
+		contextSize := newContextSizeTracker(dockerCli.Err(), warningSize)
 		s.Allow(filesync.NewFSSyncProvider([]filesync.SyncedDir{
 			{
 				Name: "context",
 				Dir:  contextDir,
-				Map:  resetUIDAndGID,
+				Map: func(path string, st *fsutiltypes.Stat) bool {
+					contextSize.add(path, st)
+					return resetUIDAndGID(path, st)
+				},
 			},
 			{
 				Name: "dockerfile",
@@ -260,11 +269,13 @@ func doBuild(ctx context.Context, eg *errgroup.Group, dockerCli command.Cli, std
 		return err
 	}
 
-	displayStatus := func(out *os.File, displayCh chan *client.SolveStatus) {
+	displayStatus := func(out io.Writer, displayCh chan *client.SolveStatus) {
 		var c console.Console
 		// TODO: Handle tty output in non-tty environment.
-		if cons, err := console.ConsoleFromFile(out); err == nil && (options.progress == "auto" || options.progress == "tty") {
-			c = cons
+		if f, ok := out.(*os.File); ok {
+			if cons, err := console.ConsoleFromFile(f); err == nil && (options.progress == "auto" || options.progress == "tty") {
+				c = cons
+			}
 		}
 		// not using shared context to not disrupt display but let is finish reporting errors
 		eg.Go(func() error {
@@ -288,12 +299,12 @@ func doBuild(ctx context.Context, eg *errgroup.Group, dockerCli command.Cli, std
 					}
 					close(displayCh)
 				}()
-				displayStatus(os.Stderr, displayCh)
+				displayStatus(dockerCli.Err(), displayCh)
 			}
 			return nil
 		})
 	} else {
-		displayStatus(os.Stderr, t.displayCh)
+		displayStatus(dockerCli.Err(), t.displayCh)
 	}
 	defer close(t.displayCh)
 
@@ -0,0 +1,171 @@
+package image
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/urlutil"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	fsutiltypes "github.com/tonistiigi/fsutil/types"
+)
+
+const (
+	// defaultContextWarningSize is the size of the build context above which
+	// a warning is printed, unless configured otherwise
+	defaultContextWarningSize = 500 * units.MB
+
+	// largestDirectoriesCount is the number of directories listed in the
+	// largest directories of a context report
+	largestDirectoriesCount = 10
+)
+
+// runPrintContext prints the files that are sent as the build context, the
+// exclude patterns that matched, and the size of the context, without building
+func runPrintContext(dockerCli command.Cli, options buildOptions) error {
+	var (
+		contextDir    string
+		relDockerfile string
+		err           error
+	)
+	switch {
+	case isLocalDir(options.context):
+		contextDir, relDockerfile, err = build.GetContextFromLocalDir(options.context, options.dockerfileName)
+	case urlutil.IsGitURL(options.context):
+		contextDir, relDockerfile, err = build.GetContextFromGitURL(options.context, options.dockerfileName)
+		if err == nil {
+			defer os.RemoveAll(contextDir)
+		}
+	default:
+		return errors.New("--print-context requires a local directory or a Git repository as build context")
+	}
+	if err != nil {
+		return errors.Errorf("unable to prepare context: %s", err)
+	}
+
+	excludes, err := build.ReadDockerignore(contextDir)
+	if err != nil {
+		return err
+	}
+	relDockerfile = archive.CanonicalTarNameForPath(relDockerfile)
+	excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, options.dockerfileFromStdin())
+
+	report, err := build.GetContextReport(contextDir, excludes)
+	if err != nil {
+		return err
+	}
+	printContextReport(dockerCli.Out(), report)
+	return nil
+}
+
+func printContextReport(out io.Writer, report *build.ContextReport) {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "Files:")
+	fmt.Fprintln(w, "SIZE\tPATH")
+	for _, f := range report.Files {
+		fmt.Fprintf(w, "%s\t%s\n", units.HumanSizeWithPrecision(float64(f.Size), 3), f.Path)
+	}
+
+	if len(report.Excludes) > 0 {
+		fmt.Fprintln(w, "\nExcluded by .dockerignore:")
+		fmt.Fprintln(w, "PATTERN\tPATHS")
+		for _, e := range report.Excludes {
+			fmt.Fprintf(w, "%s\t%d\n", e.Pattern, e.Matches)
+		}
+	}
+
+	if dirs := report.LargestDirectories(largestDirectoriesCount); len(dirs) > 0 {
+		fmt.Fprintln(w, "\nLargest directories:")
+		fmt.Fprintln(w, "SIZE\tPATH")
+		for _, d := range dirs {
+			fmt.Fprintf(w, "%s\t%s\n", units.HumanSizeWithPrecision(float64(d.Size), 3), d.Path)
+		}
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nTotal: %d files, %s (%s compressed)\n", len(report.Files),
+		units.HumanSizeWithPrecision(float64(report.Size), 3),
+		units.HumanSizeWithPrecision(float64(report.CompressedSize), 3))
+}
+
+// contextWarningSize returns the size of the build context above which a
+// warning is printed, as configured in the config file. A size of 0 disables
+// the warning.
+func contextWarningSize(dockerCli command.Cli) (int64, error) {
+	configured := dockerCli.ConfigFile().BuildContextWarningSize
+	if configured == "" {
+		return defaultContextWarningSize, nil
+	}
+	size, err := units.FromHumanSize(configured)
+	return size, errors.Wrapf(err, "invalid buildContextWarningSize %q in config file", configured)
+}
+
+// contextSizeWarner prints a warning once the build context read through it
+// exceeds a given size
+type contextSizeWarner struct {
+	io.ReadCloser
+	out    io.Writer
+	limit  int64
+	read   int64
+	warned bool
+}
+
+func newContextSizeWarner(buildCtx io.ReadCloser, out io.Writer, limit int64) io.ReadCloser {
+	if limit <= 0 {
+		return buildCtx
+	}
+	return &contextSizeWarner{ReadCloser: buildCtx, out: out, limit: limit}
+}
+
+func (w *contextSizeWarner) Read(p []byte) (int, error) {
+	n, err := w.ReadCloser.Read(p)
+	w.read += int64(n)
+	if !w.warned && w.read > w.limit {
+		w.warned = true
+		warnContextSize(w.out, w.limit)
+	}
+	return n, err
+}
+
+// contextSizeTracker prints a warning once the files of the build context
+// synced with BuildKit exceed a given size. The context may be synced more
+// than once during a build, so each file is only counted once.
+type contextSizeTracker struct {
+	mu     sync.Mutex
+	out    io.Writer
+	limit  int64
+	files  map[string]int64
+	size   int64
+	warned bool
+}
+
+func newContextSizeTracker(out io.Writer, limit int64) *contextSizeTracker {
+	return &contextSizeTracker{out: out, limit: limit, files: map[string]int64{}}
+}
+
+// add counts the size of a file of the build context, as passed to the map
+// function of the synced directory
+func (t *contextSizeTracker) add(path string, st *fsutiltypes.Stat) {
+	if t.limit <= 0 || os.FileMode(st.Mode).IsDir() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.size += st.Size_ - t.files[path]
+	t.files[path] = st.Size_
+	if !t.warned && t.size > t.limit {
+		t.warned = true
+		warnContextSize(t.out, t.limit)
+	}
+}
+
+func warnContextSize(out io.Writer, limit int64) {
+	fmt.Fprintf(out, "WARNING: the build context is larger than %s. Use \"docker build --print-context\" to list the files that are sent, and a .dockerignore file to exclude files not needed by the build.\n",
+		units.HumanSize(float64(limit)))
+}
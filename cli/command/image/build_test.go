@@ -11,15 +11,20 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/google/go-cmp/cmp"
 	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	"github.com/pkg/errors"
+	fsutiltypes "github.com/tonistiigi/fsutil/types"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/fs"
 	"gotest.tools/skip"
 )
@@ -116,11 +121,83 @@ COPY data /data
 	assert.DeepEqual(t, expected, fakeBuild.filenames(t))
 }
 
+func TestRunBuildPrintContext(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM busybox"),
+		fs.WithFile(".dockerignore", "*.log\nnode_modules\n"),
+		fs.WithFile("build.log", "log"),
+		fs.WithDir("src", fs.WithFile("main.go", "package main")),
+	)
+	defer dir.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{
+		imageBuildFunc: func(context.Context, io.Reader, types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			return types.ImageBuildResponse{}, errors.New("unexpected build")
+		},
+	})
+	options := newBuildOptions()
+	options.context = dir.Path()
+	options.printContext = true
+	assert.NilError(t, runBuild(cli, options))
+
+	out := cli.OutBuffer().String()
+	for _, expected := range []string{
+		"12B       src/main.go\n",
+		"19B       .dockerignore\n",
+		"*.log          1\n",
+		"node_modules   0\n",
+		"Largest directories:\nSIZE      PATH\n12B       src\n",
+		"Total: 3 files, ",
+	} {
+		assert.Check(t, is.Contains(out, expected))
+	}
+	assert.Check(t, !strings.Contains(out, "build.log"))
+}
+
+func TestRunBuildWarnsOnLargeContext(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM busybox"),
+		fs.WithFile("foo", "some content"),
+	)
+	defer dir.Remove()
+
+	fakeBuild := newFakeBuild()
+	cli := test.NewFakeCli(&fakeClient{imageBuildFunc: fakeBuild.build})
+	cli.SetConfigFile(&configfile.ConfigFile{BuildContextWarningSize: "1kB"})
+
+	options := newBuildOptions()
+	options.context = dir.Path()
+	options.untrusted = true
+	assert.NilError(t, runBuild(cli, options))
+	fakeBuild.headers(t)
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "WARNING: the build context is larger than 1kB."))
+
+	cli.ErrBuffer().Reset()
+	cli.SetConfigFile(&configfile.ConfigFile{BuildContextWarningSize: "0"})
+	assert.NilError(t, runBuild(cli, options))
+	fakeBuild.headers(t)
+	assert.Check(t, is.Equal("", cli.ErrBuffer().String()))
+}
+
 // TestRunBuildFromLocalGitHubDirNonExistingRepo tests that build contexts
 // starting with `github.com/` are special-cased, and the build command attempts
 // to clone the remote repo.
 // TODO: test "context selection" logic directly when runBuild is refactored
 // to support testing (ex: docker/cli#294)
+func TestContextSizeTracker(t *testing.T) {
+	out := new(bytes.Buffer)
+	tracker := newContextSizeTracker(out, 100)
+	tracker.add("src", &fsutiltypes.Stat{Mode: uint32(os.ModeDir | 0755), Size_: 4096})
+	tracker.add("src/main.go", &fsutiltypes.Stat{Mode: 0644, Size_: 60})
+	// the context is synced again
+	tracker.add("src/main.go", &fsutiltypes.Stat{Mode: 0644, Size_: 60})
+	assert.Check(t, is.Equal("", out.String()))
+
+	tracker.add("data.bin", &fsutiltypes.Stat{Mode: 0644, Size_: 60})
+	tracker.add("other.bin", &fsutiltypes.Stat{Mode: 0644, Size_: 60})
+	assert.Check(t, is.Equal(1, strings.Count(out.String(), "WARNING: the build context is larger than 100B.")))
+}
+
 func TestRunBuildFromGitHubSpecialCase(t *testing.T) {
 	cmd := NewBuildCommand(test.NewFakeCli(nil))
 	// Clone a small repo that exists so git doesn't prompt for credentials
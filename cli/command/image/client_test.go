@@ -7,10 +7,14 @@ import (
 	"strings"
 	"time"
 
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type fakeClient struct {
@@ -122,3 +126,35 @@ func (cli *fakeClient) ImageBuild(ctx context.Context, context io.Reader, option
 	}
 	return types.ImageBuildResponse{Body: ioutil.NopCloser(strings.NewReader(""))}, nil
 }
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	getManifestDescriptorFunc func(ref reference.Named) (ocispec.Descriptor, error)
+	getManifestFunc           func(ref reference.Named) (manifesttypes.ImageManifest, error)
+	getManifestListFunc       func(ref reference.Named) ([]manifesttypes.ImageManifest, error)
+}
+
+func (c *fakeRegistryClient) GetManifestDescriptor(_ context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	if c.getManifestDescriptorFunc != nil {
+		return c.getManifestDescriptorFunc(ref)
+	}
+	return ocispec.Descriptor{}, nil
+}
+
+func (c *fakeRegistryClient) GetManifest(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+	if c.getManifestFunc != nil {
+		return c.getManifestFunc(ref)
+	}
+	return manifesttypes.ImageManifest{}, nil
+}
+
+func (c *fakeRegistryClient) GetManifestList(_ context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+	if c.getManifestListFunc != nil {
+		return c.getManifestListFunc(ref)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) GetManifestListPayload(_ context.Context, ref reference.Named) (string, []byte, error) {
+	return "", nil, nil
+}
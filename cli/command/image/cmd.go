@@ -22,6 +22,7 @@ func NewImageCommand(dockerCli command.Cli) *cobra.Command {
 		NewLoadCommand(dockerCli),
 		NewPullCommand(dockerCli),
 		NewPushCommand(dockerCli),
+		NewResolveCommand(dockerCli),
 		NewSaveCommand(dockerCli),
 		NewTagCommand(dockerCli),
 		newListCommand(dockerCli),
@@ -0,0 +1,89 @@
+package image
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/image/lockfile"
+)
+
+const (
+	defaultResolveTableFormat = "table {{.Image}}\t{{.Digest}}\t{{.Platform}}\t{{.PlatformDigest}}"
+
+	resolveImageHeader          = "IMAGE"
+	resolveReferenceHeader      = "REFERENCE"
+	resolveDigestHeader         = "DIGEST"
+	resolvePlatformHeader       = "PLATFORM"
+	resolvePlatformDigestHeader = "PLATFORM DIGEST"
+)
+
+// resolvedImage is an image reference as passed by the user, and the digests
+// it resolved to
+type resolvedImage struct {
+	Name  string
+	Image lockfile.Image
+}
+
+func newResolveFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultResolveTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// resolveFormatWrite writes a row for each platform of the resolved images
+func resolveFormatWrite(ctx formatter.Context, images []resolvedImage) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, img := range images {
+			platforms := img.Image.Platforms
+			if len(platforms) == 0 {
+				platforms = []lockfile.Platform{{}}
+			}
+			for _, p := range platforms {
+				if err := format(&resolveContext{name: img.Name, i: img.Image, p: p}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	resolveCtx := &resolveContext{}
+	resolveCtx.Header = formatter.SubHeaderContext{
+		"Image":          resolveImageHeader,
+		"Reference":      resolveReferenceHeader,
+		"Digest":         resolveDigestHeader,
+		"Platform":       resolvePlatformHeader,
+		"PlatformDigest": resolvePlatformDigestHeader,
+	}
+	return ctx.Write(resolveCtx, render)
+}
+
+type resolveContext struct {
+	formatter.HeaderContext
+	name string
+	i    lockfile.Image
+	p    lockfile.Platform
+}
+
+func (c *resolveContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *resolveContext) Image() string {
+	return c.name
+}
+
+func (c *resolveContext) Reference() string {
+	return c.i.Reference
+}
+
+func (c *resolveContext) Digest() string {
+	return c.i.Digest.String()
+}
+
+func (c *resolveContext) Platform() string {
+	return c.p.Platform
+}
+
+func (c *resolveContext) PlatformDigest() string {
+	return c.p.Digest.String()
+}
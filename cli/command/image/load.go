@@ -3,9 +3,11 @@ package image
 import (
 	"context"
 	"io"
+	"os"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/ocilayout"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
@@ -15,6 +17,7 @@ import (
 type loadOptions struct {
 	input string
 	quiet bool
+	oci   bool
 }
 
 // NewLoadCommand creates a new `docker load` command
@@ -34,6 +37,7 @@ func NewLoadCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, instead of STDIN")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the load output")
+	flags.BoolVar(&opts.oci, "oci", false, "Load from an OCI image layout archive; implied if the input is a directory")
 
 	return cmd
 }
@@ -42,6 +46,15 @@ func runLoad(dockerCli command.Cli, opts loadOptions) error {
 
 	var input io.Reader = dockerCli.In()
 	if opts.input != "" {
+		if fileInfo, err := os.Stat(opts.input); err == nil && fileInfo.IsDir() {
+			layout, err := ocilayout.ReadDir(opts.input)
+			if err != nil {
+				return err
+			}
+			defer layout.Close()
+			return loadImage(dockerCli, layout, opts.quiet)
+		}
+
 		// We use system.OpenSequential to use sequential file access on Windows, avoiding
 		// depleting the standby list un-necessarily. On Linux, this equates to a regular os.Open.
 		file, err := system.OpenSequential(opts.input)
@@ -58,10 +71,22 @@ func runLoad(dockerCli command.Cli, opts loadOptions) error {
 		return errors.Errorf("requested load from stdin, but stdin is empty")
 	}
 
+	if opts.oci {
+		layout, err := ocilayout.ReadTar(input)
+		if err != nil {
+			return err
+		}
+		defer layout.Close()
+		input = layout
+	}
+	return loadImage(dockerCli, input, opts.quiet)
+}
+
+func loadImage(dockerCli command.Cli, input io.Reader, quiet bool) error {
 	if !dockerCli.Out().IsTerminal() {
-		opts.quiet = true
+		quiet = true
 	}
-	response, err := dockerCli.Client().ImageLoad(context.Background(), input, opts.quiet)
+	response, err := dockerCli.Client().ImageLoad(context.Background(), input, quiet)
 	if err != nil {
 		return err
 	}
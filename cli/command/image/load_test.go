@@ -1,16 +1,20 @@
 package image
 
 import (
+	"archive/tar"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/command/image/ocilayout"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/golden"
 )
 
@@ -99,3 +103,37 @@ func TestNewLoadCommandSuccess(t *testing.T) {
 		golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("load-command-success.%s.golden", tc.name))
 	}
 }
+
+func TestNewLoadCommandOCILayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker-load-oci-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NilError(t, ocilayout.WriteDir(dockerArchive(t), dir))
+
+	var loaded []string
+	cli := test.NewFakeCli(&fakeClient{
+		imageLoadFunc: func(input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+			tr := tar.NewReader(input)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				assert.NilError(t, err)
+				loaded = append(loaded, hdr.Name)
+			}
+			return types.ImageLoadResponse{Body: ioutil.NopCloser(strings.NewReader("Success"))}, nil
+		},
+	})
+	cmd := NewLoadCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--input", dir})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(loaded, 3))
+	assert.Check(t, is.Contains(loaded, "manifest.json"))
+
+	cmd = NewLoadCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--oci", "--input", "testdata/load-command-success.input.txt"})
+	assert.ErrorContains(t, cmd.Execute(), "failed to read OCI image layout archive")
+}
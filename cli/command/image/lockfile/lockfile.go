@@ -0,0 +1,216 @@
+// Package lockfile resolves image references to the digests a registry
+// currently serves for them, and records the results in a lockfile that can
+// be committed alongside a Compose file and deployed from later.
+package lockfile
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Version is the version of the lockfile format written by this package.
+const Version = "1"
+
+// Lockfile maps image references, as written in a Compose file or passed on
+// the command line, to the digests they resolved to.
+type Lockfile struct {
+	Version string           `json:"version"`
+	Images  map[string]Image `json:"images"`
+}
+
+// Image is an image reference resolved to a digest.
+type Image struct {
+	// Reference is the image reference pinned to Digest, in its familiar
+	// form (for example "nginx:1.15@sha256:...").
+	Reference string `json:"reference"`
+	// Digest is the digest of the manifest or manifest list the reference
+	// resolved to.
+	Digest digest.Digest `json:"digest"`
+	// MediaType is the media type of the manifest or manifest list.
+	MediaType string `json:"mediaType"`
+	// Platforms lists the digest of the image manifest for each platform.
+	Platforms []Platform `json:"platforms,omitempty"`
+}
+
+// Platform is the image manifest of an image for a single platform.
+type Platform struct {
+	Platform string        `json:"platform"`
+	Digest   digest.Digest `json:"digest"`
+}
+
+// New returns an empty lockfile.
+func New() *Lockfile {
+	return &Lockfile{Version: Version, Images: map[string]Image{}}
+}
+
+// Load reads the lockfile at path.
+func Load(path string) (*Lockfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	l, err := Read(f)
+	return l, errors.Wrapf(err, "failed to read lockfile %s", path)
+}
+
+// Read reads a lockfile from r.
+func Read(r io.Reader) (*Lockfile, error) {
+	l := New()
+	if err := json.NewDecoder(r).Decode(l); err != nil {
+		return nil, err
+	}
+	if l.Version != Version {
+		return nil, errors.Errorf("unsupported lockfile version %q", l.Version)
+	}
+	if l.Images == nil {
+		l.Images = map[string]Image{}
+	}
+	return l, nil
+}
+
+// Write writes the lockfile to w. Images are sorted by reference, so that the
+// output is stable and diffs well.
+func (l *Lockfile) Write(w io.Writer) error {
+	out, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
+
+// Save writes the lockfile to path, replacing any existing file.
+func (l *Lockfile) Save(path string) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".lockfile-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := l.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Pin returns the pinned reference recorded for image, if any.
+func (l *Lockfile) Pin(image string) (string, bool) {
+	resolved, ok := l.Images[image]
+	if !ok {
+		return "", false
+	}
+	return resolved.Reference, true
+}
+
+// Resolve resolves image to the digest the registry currently serves for it,
+// along with the digest of the image manifest for each platform. If image is
+// pinned to a digest as well as tagged, the tag is resolved so that the
+// caller can compare the two.
+func Resolve(ctx context.Context, client registryclient.RegistryClient, image string) (Image, error) {
+	ref, err := unpinnedReference(image)
+	if err != nil {
+		return Image{}, err
+	}
+
+	desc, err := client.GetManifestDescriptor(ctx, ref)
+	if err != nil {
+		return Image{}, errors.Wrapf(err, "failed to resolve %s", image)
+	}
+	if desc.Digest == "" {
+		return Image{}, errors.Errorf("failed to resolve %s: no such manifest", image)
+	}
+	pinned, err := reference.WithDigest(ref, desc.Digest)
+	if err != nil {
+		return Image{}, err
+	}
+	canonical, err := reference.WithDigest(reference.TrimNamed(ref), desc.Digest)
+	if err != nil {
+		return Image{}, err
+	}
+
+	resolved := Image{
+		Reference: reference.FamiliarString(pinned),
+		Digest:    desc.Digest,
+		MediaType: desc.MediaType,
+	}
+	switch desc.MediaType {
+	case manifestlist.MediaTypeManifestList, ocispec.MediaTypeImageIndex:
+		manifests, err := client.GetManifestList(ctx, canonical)
+		if err != nil {
+			return Image{}, errors.Wrapf(err, "failed to resolve %s", image)
+		}
+		for _, m := range manifests {
+			resolved.Platforms = append(resolved.Platforms, Platform{
+				Platform: platformString(m.Descriptor.Platform),
+				Digest:   m.Descriptor.Digest,
+			})
+		}
+	default:
+		m, err := client.GetManifest(ctx, canonical)
+		if err != nil {
+			return Image{}, errors.Wrapf(err, "failed to resolve %s", image)
+		}
+		resolved.Platforms = []Platform{{
+			Platform: platformString(m.Descriptor.Platform),
+			Digest:   desc.Digest,
+		}}
+	}
+	return resolved, nil
+}
+
+// PinnedDigest returns the digest image is pinned to, or an empty digest if
+// image is not pinned.
+func PinnedDigest(image string) (digest.Digest, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", err
+	}
+	if canonical, ok := named.(reference.Canonical); ok {
+		return canonical.Digest(), nil
+	}
+	return "", nil
+}
+
+// unpinnedReference returns the reference to resolve for image: its tag,
+// "latest" if it has neither a tag nor a digest, or its digest if it is not
+// tagged.
+func unpinnedReference(image string) (reference.Named, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid reference %s", image)
+	}
+	if tagged, ok := named.(reference.NamedTagged); ok {
+		return reference.WithTag(reference.TrimNamed(named), tagged.Tag())
+	}
+	if _, ok := named.(reference.Canonical); ok {
+		return named, nil
+	}
+	return reference.TagNameOnly(named), nil
+}
+
+// platformString returns the platform formatted as os/arch[/variant]
+func platformString(p *ocispec.Platform) string {
+	if p == nil || p.OS == "" && p.Architecture == "" {
+		return ""
+	}
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
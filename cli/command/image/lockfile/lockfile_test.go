@@ -0,0 +1,148 @@
+package lockfile
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+var (
+	listDigest  = digest.FromString("list")
+	amd64Digest = digest.FromString("amd64")
+	arm64Digest = digest.FromString("arm64")
+)
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	descriptors map[string]ocispec.Descriptor
+	lists       map[string][]manifesttypes.ImageManifest
+	manifests   map[string]manifesttypes.ImageManifest
+}
+
+func (c *fakeRegistryClient) GetManifestDescriptor(_ context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	return c.descriptors[ref.String()], nil
+}
+
+func (c *fakeRegistryClient) GetManifestList(_ context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+	return c.lists[ref.String()], nil
+}
+
+func (c *fakeRegistryClient) GetManifestListPayload(_ context.Context, ref reference.Named) (string, []byte, error) {
+	return "", nil, nil
+}
+
+func (c *fakeRegistryClient) GetManifest(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+	return c.manifests[ref.String()], nil
+}
+
+func newFakeRegistryClient() *fakeRegistryClient {
+	platformManifest := func(dgst digest.Digest, arch string) manifesttypes.ImageManifest {
+		return manifesttypes.ImageManifest{Descriptor: ocispec.Descriptor{
+			Digest:   dgst,
+			Platform: &ocispec.Platform{OS: "linux", Architecture: arch},
+		}}
+	}
+	return &fakeRegistryClient{
+		descriptors: map[string]ocispec.Descriptor{
+			"docker.io/library/nginx:1.15":   {MediaType: manifestlist.MediaTypeManifestList, Digest: listDigest},
+			"docker.io/library/redis:latest": {MediaType: schema2.MediaTypeManifest, Digest: amd64Digest},
+		},
+		lists: map[string][]manifesttypes.ImageManifest{
+			"docker.io/library/nginx@" + listDigest.String(): {
+				platformManifest(amd64Digest, "amd64"),
+				platformManifest(arm64Digest, "arm64"),
+			},
+		},
+		manifests: map[string]manifesttypes.ImageManifest{
+			"docker.io/library/redis@" + amd64Digest.String(): platformManifest(amd64Digest, "amd64"),
+		},
+	}
+}
+
+func TestResolveManifestList(t *testing.T) {
+	img, err := Resolve(context.Background(), newFakeRegistryClient(), "nginx:1.15")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(Image{
+		Reference: "nginx:1.15@" + listDigest.String(),
+		Digest:    listDigest,
+		MediaType: manifestlist.MediaTypeManifestList,
+		Platforms: []Platform{
+			{Platform: "linux/amd64", Digest: amd64Digest},
+			{Platform: "linux/arm64", Digest: arm64Digest},
+		},
+	}, img))
+}
+
+func TestResolveManifest(t *testing.T) {
+	img, err := Resolve(context.Background(), newFakeRegistryClient(), "redis")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(Image{
+		Reference: "redis:latest@" + amd64Digest.String(),
+		Digest:    amd64Digest,
+		MediaType: schema2.MediaTypeManifest,
+		Platforms: []Platform{{Platform: "linux/amd64", Digest: amd64Digest}},
+	}, img))
+}
+
+func TestResolvePinnedResolvesTag(t *testing.T) {
+	img, err := Resolve(context.Background(), newFakeRegistryClient(), "nginx:1.15@"+arm64Digest.String())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(listDigest, img.Digest))
+	assert.Check(t, is.Equal("nginx:1.15@"+listDigest.String(), img.Reference))
+}
+
+func TestResolveNotFound(t *testing.T) {
+	_, err := Resolve(context.Background(), newFakeRegistryClient(), "busybox")
+	assert.Check(t, is.Error(err, "failed to resolve busybox: no such manifest"))
+}
+
+func TestPinnedDigest(t *testing.T) {
+	dgst, err := PinnedDigest("nginx:1.15@" + listDigest.String())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(listDigest, dgst))
+
+	dgst, err = PinnedDigest("nginx:1.15")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(digest.Digest(""), dgst))
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lockfile-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	img, err := Resolve(context.Background(), newFakeRegistryClient(), "nginx:1.15")
+	assert.NilError(t, err)
+	l := New()
+	l.Images["nginx:1.15"] = img
+	path := filepath.Join(dir, "stack.lock")
+	assert.NilError(t, l.Save(path))
+
+	loaded, err := Load(path)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(l, loaded))
+
+	pinned, ok := loaded.Pin("nginx:1.15")
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal("nginx:1.15@"+listDigest.String(), pinned))
+	_, ok = loaded.Pin("redis")
+	assert.Check(t, !ok)
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	_, err := Read(bytes.NewBufferString(`{"version": "2", "images": {}}`))
+	assert.Check(t, is.Error(err, `unsupported lockfile version "2"`))
+}
@@ -0,0 +1,420 @@
+// Package ocilayout transcodes between the docker-archive format produced and
+// consumed by the daemon's image save and load endpoints, and the OCI image
+// layout (https://github.com/opencontainers/image-spec/blob/master/image-layout.md).
+package ocilayout
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/pkg/archive"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	indexFile    = "index.json"
+	manifestFile = "manifest.json"
+
+	// mediaTypeDockerManifest is accepted in addition to the OCI manifest
+	// media type when reading a layout, as some tools write it.
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// archiveManifestItem is an entry in the manifest.json of a docker-archive.
+type archiveManifestItem struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// WriteDir transcodes the docker-archive read from r into an OCI image layout
+// in dir. The directory is created if it does not exist. If dir already holds
+// an image layout, the images are added to its index, replacing any entries
+// with the same reference name. Untagged images are only added if the index
+// does not hold them yet.
+func WriteDir(r io.Reader, dir string) error {
+	tmpDir, err := ioutil.TempDir("", "docker-archive-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := archive.Untar(r, tmpDir, &archive.TarOptions{NoLchown: true}); err != nil {
+		return errors.Wrap(err, "failed to read image archive")
+	}
+	var items []archiveManifestItem
+	if err := readJSON(filepath.Join(tmpDir, manifestFile), &items); err != nil {
+		return errors.Wrap(err, "failed to read image archive")
+	}
+
+	if err := os.MkdirAll(blobsDir(dir), 0755); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(dir, ocispec.ImageLayoutFile), ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion}); err != nil {
+		return err
+	}
+	index, err := readIndex(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		desc, err := writeImage(tmpDir, dir, item)
+		if err != nil {
+			return err
+		}
+		if len(item.RepoTags) == 0 {
+			if !hasDigest(index.Manifests, desc.Digest) {
+				index.Manifests = append(index.Manifests, desc)
+			}
+			continue
+		}
+		for _, tag := range item.RepoTags {
+			tagged := desc
+			tagged.Annotations = map[string]string{ocispec.AnnotationRefName: refName(tag)}
+			index.Manifests = append(removeTag(index.Manifests, tag), tagged)
+		}
+	}
+	return writeJSON(filepath.Join(dir, indexFile), index)
+}
+
+// WriteTar transcodes the docker-archive read from r into a tar archive of an
+// OCI image layout, written to w.
+func WriteTar(r io.Reader, w io.Writer) error {
+	tmpDir, err := ioutil.TempDir("", "oci-layout-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := WriteDir(r, tmpDir); err != nil {
+		return err
+	}
+	rc, err := archive.Tar(tmpDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+func writeImage(srcDir, dir string, item archiveManifestItem) (ocispec.Descriptor, error) {
+	config, err := copyBlob(dir, srcDir, item.Config, ocispec.MediaTypeImageConfig)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "failed to write image config %s", item.Config)
+	}
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    config,
+		Layers:    []ocispec.Descriptor{},
+	}
+	for _, layer := range item.Layers {
+		desc, err := copyBlob(dir, srcDir, layer, ocispec.MediaTypeImageLayer)
+		if err != nil {
+			return ocispec.Descriptor{}, errors.Wrapf(err, "failed to write layer %s", layer)
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dgst := digest.FromBytes(raw)
+	if err := ioutil.WriteFile(blobPath(dir, dgst), raw, 0644); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    dgst,
+		Size:      int64(len(raw)),
+	}, nil
+}
+
+// copyBlob copies the file at name in srcDir into the blobs of the layout in
+// dir, and returns its descriptor.
+func copyBlob(dir, srcDir, name, mediaType string) (ocispec.Descriptor, error) {
+	path, err := archivePath(srcDir, name)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer src.Close()
+
+	tmp, err := ioutil.TempFile(blobsDir(dir), ".tmp-")
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(io.MultiWriter(tmp, digester.Hash()), src)
+	tmp.Close()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dgst := digester.Digest()
+	if err := os.Rename(tmp.Name(), blobPath(dir, dgst)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: size}, nil
+}
+
+// ReadDir transcodes the OCI image layout in dir into a docker-archive that
+// can be loaded by the daemon. Reference names annotated on the index that are
+// valid image references are preserved as tags; others are ignored.
+func ReadDir(dir string) (io.ReadCloser, error) {
+	var layout ocispec.ImageLayout
+	if err := readJSON(filepath.Join(dir, ocispec.ImageLayoutFile), &layout); err != nil {
+		return nil, errors.Errorf("%s is not an OCI image layout: %v", dir, err)
+	}
+	if layout.Version != ocispec.ImageLayoutVersion {
+		return nil, errors.Errorf("unsupported OCI image layout version %q", layout.Version)
+	}
+	var index ocispec.Index
+	if err := readJSON(filepath.Join(dir, indexFile), &index); err != nil {
+		return nil, err
+	}
+
+	var (
+		items []archiveManifestItem
+		blobs []ocispec.Descriptor
+	)
+	byManifest := map[digest.Digest]int{}
+	for _, desc := range index.Manifests {
+		switch desc.MediaType {
+		case ocispec.MediaTypeImageManifest, mediaTypeDockerManifest:
+		case ocispec.MediaTypeImageIndex:
+			return nil, errors.Errorf("%s: nested image indexes are not supported", desc.Digest)
+		default:
+			return nil, errors.Errorf("%s: unsupported media type %q", desc.Digest, desc.MediaType)
+		}
+		i, ok := byManifest[desc.Digest]
+		if !ok {
+			var manifest ocispec.Manifest
+			if err := readBlobJSON(dir, desc, &manifest); err != nil {
+				return nil, err
+			}
+			item := archiveManifestItem{Config: blobName(manifest.Config.Digest)}
+			blobs = append(blobs, manifest.Config)
+			for _, layer := range manifest.Layers {
+				item.Layers = append(item.Layers, blobName(layer.Digest))
+				blobs = append(blobs, layer)
+			}
+			i = len(items)
+			items = append(items, item)
+			byManifest[desc.Digest] = i
+		}
+		if tag, ok := repoTag(desc.Annotations[ocispec.AnnotationRefName]); ok {
+			items[i].RepoTags = append(items[i].RepoTags, tag)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeArchive(pw, dir, items, blobs))
+	}()
+	return pr, nil
+}
+
+// ReadTar transcodes a tar archive of an OCI image layout read from r into a
+// docker-archive that can be loaded by the daemon.
+func ReadTar(r io.Reader) (io.ReadCloser, error) {
+	tmpDir, err := ioutil.TempDir("", "oci-layout-")
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.Untar(r, tmpDir, &archive.TarOptions{NoLchown: true}); err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, errors.Wrap(err, "failed to read OCI image layout archive")
+	}
+	rc, err := ReadDir(tmpDir)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, err
+	}
+	return &cleanupReadCloser{ReadCloser: rc, dir: tmpDir}, nil
+}
+
+type cleanupReadCloser struct {
+	io.ReadCloser
+	dir string
+}
+
+func (c *cleanupReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	os.RemoveAll(c.dir)
+	return err
+}
+
+func writeArchive(w io.Writer, dir string, items []archiveManifestItem, blobs []ocispec.Descriptor) error {
+	tw := tar.NewWriter(w)
+	written := map[digest.Digest]bool{}
+	for _, desc := range blobs {
+		if written[desc.Digest] {
+			continue
+		}
+		if err := writeArchiveBlob(tw, dir, desc); err != nil {
+			return err
+		}
+		written[desc.Digest] = true
+	}
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestFile, Mode: 0644, Size: int64(len(raw))}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(raw); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeArchiveBlob(tw *tar.Writer, dir string, desc ocispec.Descriptor) error {
+	if err := desc.Digest.Validate(); err != nil {
+		return err
+	}
+	f, err := os.Open(blobPath(dir, desc.Digest))
+	if err != nil {
+		return errors.Wrapf(err, "missing blob %s", desc.Digest)
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(&tar.Header{Name: blobName(desc.Digest), Mode: 0644, Size: desc.Size}); err != nil {
+		return err
+	}
+	verifier := desc.Digest.Verifier()
+	n, err := io.Copy(tw, io.TeeReader(io.LimitReader(f, desc.Size), verifier))
+	if err != nil {
+		return err
+	}
+	if n != desc.Size || !verifier.Verified() {
+		return errors.Errorf("blob %s does not match its descriptor", desc.Digest)
+	}
+	return nil
+}
+
+// repoTag returns the familiar form of refName, if it is a tagged image
+// reference.
+func repoTag(refName string) (string, bool) {
+	if refName == "" {
+		return "", false
+	}
+	named, err := reference.ParseNormalizedNamed(refName)
+	if err != nil {
+		return "", false
+	}
+	tagged, ok := reference.TagNameOnly(named).(reference.NamedTagged)
+	if !ok {
+		return "", false
+	}
+	return reference.FamiliarString(tagged), true
+}
+
+// refName returns the fully qualified reference of a repository tag, such as
+// "docker.io/library/busybox:latest" for "busybox:latest"
+func refName(repoTag string) string {
+	named, err := reference.ParseNormalizedNamed(repoTag)
+	if err != nil {
+		return repoTag
+	}
+	return named.String()
+}
+
+// removeTag removes the descriptors of a repository tag, whether their
+// reference is fully qualified or familiar
+func removeTag(descs []ocispec.Descriptor, tag string) []ocispec.Descriptor {
+	var result []ocispec.Descriptor
+	for _, desc := range descs {
+		if t, ok := repoTag(desc.Annotations[ocispec.AnnotationRefName]); !ok || t != tag {
+			result = append(result, desc)
+		}
+	}
+	return result
+}
+
+func hasDigest(descs []ocispec.Descriptor, dgst digest.Digest) bool {
+	for _, desc := range descs {
+		if desc.Digest == dgst {
+			return true
+		}
+	}
+	return false
+}
+
+func readIndex(dir string) (ocispec.Index, error) {
+	index := ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}}
+	err := readJSON(filepath.Join(dir, indexFile), &index)
+	if os.IsNotExist(errors.Cause(err)) {
+		return index, nil
+	}
+	return index, err
+}
+
+func readBlobJSON(dir string, desc ocispec.Descriptor, v interface{}) error {
+	if err := desc.Digest.Validate(); err != nil {
+		return err
+	}
+	raw, err := ioutil.ReadFile(blobPath(dir, desc.Digest))
+	if err != nil {
+		return errors.Wrapf(err, "missing blob %s", desc.Digest)
+	}
+	if digest.FromBytes(raw) != desc.Digest {
+		return errors.Errorf("blob %s does not match its descriptor", desc.Digest)
+	}
+	return errors.Wrapf(json.Unmarshal(raw, v), "invalid blob %s", desc.Digest)
+}
+
+func readJSON(path string, v interface{}) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(raw, v), "invalid %s", filepath.Base(path))
+}
+
+func writeJSON(path string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, raw, 0644)
+}
+
+// archivePath resolves name, as found in a docker-archive manifest, within
+// dir, refusing paths that escape it.
+func archivePath(dir, name string) (string, error) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("invalid path %q in image archive", name)
+	}
+	return path, nil
+}
+
+func blobsDir(dir string) string {
+	return filepath.Join(dir, "blobs", string(digest.Canonical))
+}
+
+func blobName(dgst digest.Digest) string {
+	return "blobs/" + dgst.Algorithm().String() + "/" + dgst.Encoded()
+}
+
+func blobPath(dir string, dgst digest.Digest) string {
+	return filepath.Join(dir, filepath.FromSlash(blobName(dgst)))
+}
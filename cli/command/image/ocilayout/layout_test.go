@@ -0,0 +1,224 @@
+package ocilayout
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+var (
+	testConfig = []byte(`{"architecture":"amd64","os":"linux"}`)
+	testLayer  = []byte("not really a layer")
+)
+
+func dockerArchive(t *testing.T, repoTags ...string) *bytes.Buffer {
+	items := []archiveManifestItem{{
+		Config:   "0123.json",
+		RepoTags: repoTags,
+		Layers:   []string{"abcd/layer.tar"},
+	}}
+	manifest, err := json.Marshal(items)
+	assert.NilError(t, err)
+	return tarFiles(t, map[string][]byte{
+		"0123.json":      testConfig,
+		"abcd/layer.tar": testLayer,
+		manifestFile:     manifest,
+	})
+}
+
+func tarFiles(t *testing.T, files map[string][]byte) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write(content)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf
+}
+
+func readTarFiles(t *testing.T, r io.Reader) map[string][]byte {
+	files := map[string][]byte{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NilError(t, err)
+		content, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = content
+	}
+}
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "oci-layout-test-")
+	assert.NilError(t, err)
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestWriteDir(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	assert.NilError(t, WriteDir(dockerArchive(t, "foo:latest", "foo:1.0"), dir))
+
+	var layout ocispec.ImageLayout
+	assert.NilError(t, readJSON(filepath.Join(dir, ocispec.ImageLayoutFile), &layout))
+	assert.Check(t, is.Equal(ocispec.ImageLayoutVersion, layout.Version))
+
+	var index ocispec.Index
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	assert.Assert(t, is.Len(index.Manifests, 2))
+	assert.Check(t, is.Equal("docker.io/library/foo:latest", index.Manifests[0].Annotations[ocispec.AnnotationRefName]))
+	assert.Check(t, is.Equal("docker.io/library/foo:1.0", index.Manifests[1].Annotations[ocispec.AnnotationRefName]))
+	assert.Check(t, is.Equal(index.Manifests[0].Digest, index.Manifests[1].Digest))
+
+	var manifest ocispec.Manifest
+	assert.NilError(t, readBlobJSON(dir, index.Manifests[0], &manifest))
+	assert.Check(t, is.Equal(digest.FromBytes(testConfig), manifest.Config.Digest))
+	assert.Check(t, is.Equal(ocispec.MediaTypeImageConfig, manifest.Config.MediaType))
+	assert.Assert(t, is.Len(manifest.Layers, 1))
+	assert.Check(t, is.Equal(digest.FromBytes(testLayer), manifest.Layers[0].Digest))
+	assert.Check(t, is.Equal(int64(len(testLayer)), manifest.Layers[0].Size))
+
+	// Saving again with an existing tag replaces the index entry.
+	assert.NilError(t, WriteDir(dockerArchive(t, "foo:latest"), dir))
+	index = ocispec.Index{}
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	assert.Check(t, is.Len(index.Manifests, 2))
+}
+
+func TestWriteDirUntagged(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	// Saving the same untagged image again does not add it to the index again.
+	assert.NilError(t, WriteDir(dockerArchive(t), dir))
+	assert.NilError(t, WriteDir(dockerArchive(t), dir))
+
+	var index ocispec.Index
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	assert.Assert(t, is.Len(index.Manifests, 1))
+	assert.Check(t, is.Len(index.Manifests[0].Annotations, 0))
+}
+
+func TestWriteDirInvalidArchive(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	err := WriteDir(tarFiles(t, map[string][]byte{"foo": []byte("bar")}), dir)
+	assert.ErrorContains(t, err, "failed to read image archive")
+}
+
+func TestRoundTrip(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NilError(t, WriteTar(dockerArchive(t, "foo:latest", "example.com/bar:1.0"), buf))
+
+	rc, err := ReadTar(buf)
+	assert.NilError(t, err)
+	defer rc.Close()
+	files := readTarFiles(t, rc)
+
+	var items []archiveManifestItem
+	assert.NilError(t, json.Unmarshal(files[manifestFile], &items))
+	assert.Assert(t, is.Len(items, 1))
+	assert.Check(t, is.DeepEqual([]string{"foo:latest", "example.com/bar:1.0"}, items[0].RepoTags))
+	assert.Check(t, is.DeepEqual(testConfig, files[items[0].Config]))
+	assert.Assert(t, is.Len(items[0].Layers, 1))
+	assert.Check(t, is.DeepEqual(testLayer, files[items[0].Layers[0]]))
+}
+
+func TestWriteDirReplacesFamiliarRefNames(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	assert.NilError(t, WriteDir(dockerArchive(t, "foo:latest"), dir))
+
+	// a layout written with the familiar reference of the tag
+	var index ocispec.Index
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	index.Manifests[0].Annotations[ocispec.AnnotationRefName] = "foo:latest"
+	assert.NilError(t, writeJSON(filepath.Join(dir, indexFile), index))
+
+	assert.NilError(t, WriteDir(dockerArchive(t, "foo:latest"), dir))
+	index = ocispec.Index{}
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	assert.Assert(t, is.Len(index.Manifests, 1))
+	assert.Check(t, is.Equal("docker.io/library/foo:latest", index.Manifests[0].Annotations[ocispec.AnnotationRefName]))
+}
+
+func TestReadDirFamiliarRefNames(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	assert.NilError(t, WriteDir(dockerArchive(t, "foo:latest", "example.com/bar:1.0"), dir))
+
+	var index ocispec.Index
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	index.Manifests[0].Annotations[ocispec.AnnotationRefName] = "foo:latest"
+	assert.NilError(t, writeJSON(filepath.Join(dir, indexFile), index))
+
+	rc, err := ReadDir(dir)
+	assert.NilError(t, err)
+	defer rc.Close()
+	var items []archiveManifestItem
+	assert.NilError(t, json.Unmarshal(readTarFiles(t, rc)[manifestFile], &items))
+	assert.Assert(t, is.Len(items, 1))
+	assert.Check(t, is.DeepEqual([]string{"foo:latest", "example.com/bar:1.0"}, items[0].RepoTags))
+}
+
+func TestReadDirIgnoresPlainTagRefNames(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	assert.NilError(t, WriteDir(dockerArchive(t, "foo:latest"), dir))
+
+	var index ocispec.Index
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	index.Manifests[0].Annotations[ocispec.AnnotationRefName] = "Not A Reference"
+	assert.NilError(t, writeJSON(filepath.Join(dir, indexFile), index))
+
+	rc, err := ReadDir(dir)
+	assert.NilError(t, err)
+	defer rc.Close()
+	var items []archiveManifestItem
+	assert.NilError(t, json.Unmarshal(readTarFiles(t, rc)[manifestFile], &items))
+	assert.Assert(t, is.Len(items, 1))
+	assert.Check(t, is.Len(items[0].RepoTags, 0))
+}
+
+func TestReadDirErrors(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	_, err := ReadDir(dir)
+	assert.ErrorContains(t, err, "is not an OCI image layout")
+
+	assert.NilError(t, WriteDir(dockerArchive(t, "foo:latest"), dir))
+	var index ocispec.Index
+	assert.NilError(t, readJSON(filepath.Join(dir, indexFile), &index))
+	var manifest ocispec.Manifest
+	assert.NilError(t, readBlobJSON(dir, index.Manifests[0], &manifest))
+
+	// Corrupt the layer blob; the error surfaces while reading the archive.
+	assert.NilError(t, ioutil.WriteFile(blobPath(dir, manifest.Layers[0].Digest), []byte("corrupted layer!!!"), 0644))
+	rc, err := ReadDir(dir)
+	assert.NilError(t, err)
+	defer rc.Close()
+	_, err = io.Copy(ioutil.Discard, rc)
+	assert.ErrorContains(t, err, "does not match its descriptor")
+
+	assert.NilError(t, os.Remove(blobPath(dir, index.Manifests[0].Digest)))
+	_, err = ReadDir(dir)
+	assert.ErrorContains(t, err, "missing blob")
+}
@@ -37,6 +37,12 @@ func NewPushCommand(dockerCli command.Cli) *cobra.Command {
 	return cmd
 }
 
+// PushImage pushes an image like `docker push`, signing it if content trust
+// is enabled
+func PushImage(dockerCli command.Cli, remote string) error {
+	return RunPush(dockerCli, pushOptions{remote: remote, untrusted: !dockerCli.ContentTrustEnabled()})
+}
+
 // RunPush performs a push against the engine based on the specified options
 func RunPush(dockerCli command.Cli, opts pushOptions) error {
 	ref, err := reference.ParseNormalizedNamed(opts.remote)
@@ -0,0 +1,78 @@
+package image
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/image/lockfile"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type resolveOptions struct {
+	images   []string
+	format   string
+	lockfile string
+}
+
+// NewResolveCommand creates a new `docker image resolve` command
+func NewResolveCommand(dockerCli command.Cli) *cobra.Command {
+	var opts resolveOptions
+
+	cmd := &cobra.Command{
+		Use:   "resolve [OPTIONS] IMAGE [IMAGE...]",
+		Short: "Resolve one or more image references to their digests in the registry",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.images = args
+			return runResolve(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "Pretty-print resolved images using a Go template")
+	flags.StringVar(&opts.lockfile, "lockfile", "", "Write the resolved digests to a lockfile")
+
+	return cmd
+}
+
+func runResolve(dockerCli command.Cli, opts resolveOptions) error {
+	ctx := context.Background()
+	client := dockerCli.RegistryClient(false)
+
+	lock := lockfile.New()
+	resolved := []resolvedImage{}
+	var errs []string
+	for _, image := range opts.images {
+		img, err := lockfile.Resolve(ctx, client, image)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		lock.Images[image] = img
+		resolved = append(resolved, resolvedImage{Name: image, Image: img})
+	}
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	resolveCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newResolveFormat(format),
+	}
+	if err := resolveFormatWrite(resolveCtx, resolved); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	if opts.lockfile != "" {
+		return lock.Save(opts.lockfile)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package image
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/command/image/lockfile"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func newResolveRegistryClient() *fakeRegistryClient {
+	platformManifest := func(arch string) manifesttypes.ImageManifest {
+		return manifesttypes.ImageManifest{Descriptor: ocispec.Descriptor{
+			Digest:   digest.FromString(arch),
+			Platform: &ocispec.Platform{OS: "linux", Architecture: arch},
+		}}
+	}
+	return &fakeRegistryClient{
+		getManifestDescriptorFunc: func(ref reference.Named) (ocispec.Descriptor, error) {
+			switch reference.FamiliarString(ref) {
+			case "nginx:1.15":
+				return ocispec.Descriptor{MediaType: manifestlist.MediaTypeManifestList, Digest: digest.FromString("nginx")}, nil
+			case "redis:latest":
+				return ocispec.Descriptor{MediaType: schema2.MediaTypeManifest, Digest: digest.FromString("amd64")}, nil
+			}
+			return ocispec.Descriptor{}, errors.Errorf("manifest unknown")
+		},
+		getManifestListFunc: func(ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+			return []manifesttypes.ImageManifest{platformManifest("amd64"), platformManifest("arm64")}, nil
+		},
+		getManifestFunc: func(ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return platformManifest("amd64"), nil
+		},
+	}
+}
+
+func TestNewResolveCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{},
+			expectedError: "requires at least 1 argument.",
+		},
+		{
+			name:          "registry-error",
+			args:          []string{"busybox"},
+			expectedError: "failed to resolve busybox: manifest unknown",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetRegistryClient(newResolveRegistryClient())
+		cmd := NewResolveCommand(cli)
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs(tc.args)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestNewResolveCommandSuccess(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "simple",
+			args: []string{"nginx:1.15", "redis"},
+		},
+		{
+			name: "format",
+			args: []string{"--format", "{{.Reference}} {{.Platform}}", "nginx:1.15"},
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetRegistryClient(newResolveRegistryClient())
+		cmd := NewResolveCommand(cli)
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs(tc.args)
+		assert.NilError(t, cmd.Execute())
+		golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("resolve-command-success.%s.golden", tc.name))
+	}
+}
+
+func TestNewResolveCommandLockfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resolve-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "images.lock")
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(newResolveRegistryClient())
+	cmd := NewResolveCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--lockfile", path, "nginx:1.15", "redis"})
+	assert.NilError(t, cmd.Execute())
+
+	lock, err := lockfile.Load(path)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(lock.Images, 2))
+	pinned, ok := lock.Pin("redis")
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal("redis:latest@"+digest.FromString("amd64").String(), pinned))
+}
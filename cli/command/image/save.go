@@ -3,9 +3,12 @@ package image
 import (
 	"context"
 	"io"
+	"os"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/ocilayout"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +16,7 @@ import (
 type saveOptions struct {
 	images []string
 	output string
+	oci    bool
 }
 
 // NewSaveCommand creates a new `docker save` command
@@ -32,6 +36,7 @@ func NewSaveCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.BoolVar(&opts.oci, "oci", false, "Save in OCI image layout format; writes a layout directory if the output is a directory")
 
 	return cmd
 }
@@ -52,6 +57,10 @@ func RunSave(dockerCli command.Cli, opts saveOptions) error {
 	}
 	defer responseBody.Close()
 
+	if opts.oci {
+		return saveOCILayout(dockerCli, opts.output, responseBody)
+	}
+
 	if opts.output == "" {
 		_, err := io.Copy(dockerCli.Out(), responseBody)
 		return err
@@ -59,3 +68,30 @@ func RunSave(dockerCli command.Cli, opts saveOptions) error {
 
 	return command.CopyToFile(opts.output, responseBody)
 }
+
+// saveOCILayout transcodes the image archive returned by the daemon into an
+// OCI image layout. The layout is written as a directory if output is an
+// existing directory or ends with a path separator, and as a tar archive
+// otherwise.
+func saveOCILayout(dockerCli command.Cli, output string, archive io.Reader) error {
+	switch {
+	case output == "":
+		return ocilayout.WriteTar(archive, dockerCli.Out())
+	case isDirOutput(output):
+		return ocilayout.WriteDir(archive, output)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(ocilayout.WriteTar(archive, pw))
+	}()
+	defer pr.Close()
+	return command.CopyToFile(output, pr)
+}
+
+func isDirOutput(output string) bool {
+	if strings.HasSuffix(output, "/") || strings.HasSuffix(output, string(os.PathSeparator)) {
+		return true
+	}
+	fileInfo, err := os.Stat(output)
+	return err == nil && fileInfo.IsDir()
+}
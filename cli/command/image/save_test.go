@@ -1,9 +1,12 @@
 package image
 
 import (
+	"archive/tar"
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -106,3 +109,64 @@ func TestNewSaveCommandSuccess(t *testing.T) {
 		}
 	}
 }
+
+func dockerArchive(t *testing.T) io.ReadCloser {
+	files := map[string]string{
+		"0123.json":      `{"architecture":"amd64","os":"linux"}`,
+		"abcd/layer.tar": "layer",
+		"manifest.json":  `[{"Config":"0123.json","RepoTags":["foo:latest"],"Layers":["abcd/layer.tar"]}]`,
+	}
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return ioutil.NopCloser(buf)
+}
+
+func TestNewSaveCommandOCILayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker-save-oci-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	cli := test.NewFakeCli(&fakeClient{
+		imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			return dockerArchive(t), nil
+		},
+	})
+	cmd := NewSaveCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--oci", "-o", dir, "foo"})
+	assert.NilError(t, cmd.Execute())
+
+	index, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(index), `"org.opencontainers.image.ref.name":"docker.io/library/foo:latest"`))
+	_, err = os.Stat(filepath.Join(dir, "oci-layout"))
+	assert.Check(t, err)
+
+	archive := filepath.Join(dir, "foo.tar")
+	cmd = NewSaveCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--oci", "-o", archive, "foo"})
+	assert.NilError(t, cmd.Execute())
+
+	f, err := os.Open(archive)
+	assert.NilError(t, err)
+	defer f.Close()
+	var names []string
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		names = append(names, hdr.Name)
+	}
+	assert.Check(t, is.Contains(names, "index.json"))
+	assert.Check(t, is.Contains(names, "oci-layout"))
+}
@@ -6,23 +6,25 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/opts"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type annotateOptions struct {
-	target     string // the target manifest list name (also transaction ID)
-	image      string // the manifest to annotate within the list
-	variant    string // an architecture variant
-	os         string
-	arch       string
-	osFeatures []string
+	target      string // the target manifest list name (also transaction ID)
+	image       string // the manifest to annotate within the list
+	variant     string // an architecture variant
+	os          string
+	arch        string
+	osFeatures  []string
+	annotations opts.ListOpts
 }
 
 // NewAnnotateCommand creates a new `docker manifest annotate` command
 func newAnnotateCommand(dockerCli command.Cli) *cobra.Command {
-	var opts annotateOptions
+	opts := annotateOptions{annotations: opts.NewListOpts(opts.ValidateLabel)}
 
 	cmd := &cobra.Command{
 		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
@@ -41,6 +43,7 @@ func newAnnotateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.StringVar(&opts.arch, "arch", "", "Set architecture")
 	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system feature")
 	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	flags.Var(&opts.annotations, "annotation", "Add an annotation to the manifest descriptor in an OCI image index (key=value)")
 
 	return cmd
 }
@@ -84,6 +87,16 @@ func runManifestAnnotate(dockerCli command.Cli, opts annotateOptions) error {
 	if !isValidOSArch(imageManifest.Descriptor.Platform.OS, imageManifest.Descriptor.Platform.Architecture) {
 		return errors.Errorf("manifest entry for image has unsupported os/arch combination: %s/%s", opts.os, opts.arch)
 	}
+	if annotations := opts.annotations.GetAll(); len(annotations) > 0 {
+		metadata, err := manifestStore.GetListMetadata(targetRef)
+		if err != nil {
+			return err
+		}
+		if !metadata.OCI {
+			return errors.Errorf("annotations are only supported on OCI image indexes, create %s with --oci", opts.target)
+		}
+		imageManifest.Descriptor.Annotations = mergeAnnotations(imageManifest.Descriptor.Annotations, annotations)
+	}
 	return manifestStore.Save(targetRef, imgRef, imageManifest)
 }
 
@@ -95,3 +108,16 @@ func appendIfUnique(list []string, str string) []string {
 	}
 	return append(list, str)
 }
+
+// mergeAnnotations adds key=value annotations to existing ones, replacing
+// those with the same key.
+func mergeAnnotations(existing map[string]string, annotations []string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range opts.ConvertKVStringsToMap(annotations) {
+		merged[k] = v
+	}
+	return merged
+}
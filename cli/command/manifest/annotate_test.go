@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -75,3 +76,28 @@ func TestManifestAnnotate(t *testing.T) {
 	expected := golden.Get(t, "inspect-annotate.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestManifestAnnotateAnnotations(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, store.Save(ref(t, "list:v1"), namedRef, fullImageManifest(t, namedRef)))
+
+	cmd := newAnnotateCommand(cli)
+	cmd.SetArgs([]string{"--annotation", "com.example.key=value", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "annotations are only supported on OCI image indexes")
+
+	assert.NilError(t, store.SaveListMetadata(ref(t, "list:v1"), manifesttypes.ManifestListMetadata{OCI: true}))
+	cmd = newAnnotateCommand(cli)
+	cmd.SetArgs([]string{"--annotation", "com.example.key=value", "--annotation", "com.example.other=", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	imageManifest, err := store.Get(ref(t, "list:v1"), namedRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]string{"com.example.key": "value", "com.example.other": ""}, imageManifest.Descriptor.Annotations))
+}
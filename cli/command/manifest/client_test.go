@@ -8,11 +8,14 @@ import (
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/reference"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type fakeRegistryClient struct {
 	getManifestFunc     func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	getManifestListFunc func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	getListPayloadFunc  func(ctx context.Context, ref reference.Named) (string, []byte, error)
+	getDescriptorFunc   func(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error)
 	mountBlobFunc       func(ctx context.Context, source reference.Canonical, target reference.Named) error
 	putManifestFunc     func(ctx context.Context, source reference.Named, mf distribution.Manifest) (digest.Digest, error)
 	getTagsFunc         func(ctx context.Context, ref reference.Named) ([]string, error)
@@ -32,6 +35,20 @@ func (c *fakeRegistryClient) GetManifestList(ctx context.Context, ref reference.
 	return nil, nil
 }
 
+func (c *fakeRegistryClient) GetManifestListPayload(ctx context.Context, ref reference.Named) (string, []byte, error) {
+	if c.getListPayloadFunc != nil {
+		return c.getListPayloadFunc(ctx, ref)
+	}
+	return "", nil, nil
+}
+
+func (c *fakeRegistryClient) GetManifestDescriptor(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	if c.getDescriptorFunc != nil {
+		return c.getDescriptorFunc(ctx, ref)
+	}
+	return ocispec.Descriptor{}, nil
+}
+
 func (c *fakeRegistryClient) MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
 	if c.mountBlobFunc != nil {
 		return c.mountBlobFunc(ctx, source, target)
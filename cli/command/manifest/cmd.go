@@ -27,6 +27,8 @@ func NewManifestCommand(dockerCli command.Cli) *cobra.Command {
 		newInspectCommand(dockerCli),
 		newAnnotateCommand(dockerCli),
 		newPushListCommand(dockerCli),
+		newListCommand(dockerCli),
+		newRmManifestListCommand(dockerCli),
 	)
 	return cmd
 }
@@ -2,29 +2,40 @@ package manifest
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/manifest/ocischema"
 	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/opts"
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/registry"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type createOpts struct {
-	amend    bool
-	insecure bool
+	amend       bool
+	insecure    bool
+	oci         bool
+	annotations opts.ListOpts
+	ociChanged  bool
 }
 
 func newCreateListCommand(dockerCli command.Cli) *cobra.Command {
-	opts := createOpts{}
+	opts := createOpts{annotations: opts.NewListOpts(opts.ValidateLabel)}
 
 	cmd := &cobra.Command{
 		Use:   "create MANIFEST_LIST MANIFEST [MANIFEST...]",
 		Short: "Create a local manifest list for annotating and pushing to a registry",
 		Args:  cli.RequiresMinArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ociChanged = cmd.Flags().Changed("oci")
 			return createManifestList(dockerCli, args, opts)
 		},
 	}
@@ -32,6 +43,8 @@ func newCreateListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
 	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.oci, "oci", false, "Create an OCI image index instead of a Docker manifest list")
+	flags.Var(&opts.annotations, "annotation", "Add an annotation to the OCI image index (key=value)")
 	return cmd
 }
 
@@ -49,6 +62,7 @@ func createManifestList(dockerCli command.Cli, args []string, opts createOpts) e
 
 	manifestStore := dockerCli.ManifestStore()
 	_, err = manifestStore.GetList(targetRef)
+	exists := err == nil
 	switch {
 	case store.IsNotFound(err):
 		// New manifest list
@@ -59,9 +73,49 @@ func createManifestList(dockerCli command.Cli, args []string, opts createOpts) e
 	}
 
 	ctx := context.Background()
+	// When amending, the list in the registry is the one to compare with, and
+	// the one to start from if there is no local list yet
+	var (
+		remote    []types.ImageManifest
+		remoteErr error
+	)
+	if opts.amend {
+		remote, remoteErr = dockerCli.RegistryClient(opts.insecure).GetManifestList(ctx, targetRef)
+	}
+	seed := opts.amend && !exists && remoteErr == nil
+
+	metadata, err := manifestStore.GetListMetadata(targetRef)
+	if err != nil {
+		return err
+	}
+	if seed {
+		mediaType, payload, err := dockerCli.RegistryClient(opts.insecure).GetManifestListPayload(ctx, targetRef)
+		if err != nil {
+			return err
+		}
+		if metadata, err = remoteListMetadata(mediaType, payload); err != nil {
+			return err
+		}
+	}
+	if opts.ociChanged {
+		metadata.OCI = opts.oci
+		if !metadata.OCI {
+			// only OCI image indexes carry annotations
+			metadata.Annotations = nil
+		}
+	}
+	if annotations := opts.annotations.GetAll(); len(annotations) > 0 {
+		if !metadata.OCI {
+			return errors.New("annotations are only supported on OCI image indexes, use --oci")
+		}
+		metadata.Annotations = mergeAnnotations(metadata.Annotations, annotations)
+	}
+
 	// Now create the local manifest list transaction by looking up the manifest schemas
 	// for the constituent images:
 	manifests := args[1:]
+	namedRefs := make([]reference.Named, 0, len(manifests))
+	added := make([]types.ImageManifest, 0, len(manifests))
 	for _, manifestRef := range manifests {
 		namedRef, err := normalizeReference(manifestRef)
 		if err != nil {
@@ -73,10 +127,129 @@ func createManifestList(dockerCli command.Cli, args []string, opts createOpts) e
 		if err != nil {
 			return err
 		}
-		if err := manifestStore.Save(targetRef, namedRef, manifest); err != nil {
+		namedRefs = append(namedRefs, namedRef)
+		added = append(added, manifest)
+	}
+	if seed {
+		if err := seedManifestList(manifestStore, targetRef, remote, added); err != nil {
 			return err
 		}
 	}
+	for i, manifest := range added {
+		if err := manifestStore.Save(targetRef, namedRefs[i], manifest); err != nil {
+			return err
+		}
+	}
+	if err := manifestStore.SaveListMetadata(targetRef, metadata); err != nil {
+		return err
+	}
 	fmt.Fprintf(dockerCli.Out(), "Created manifest list %s\n", targetRef.String())
+	if !opts.amend {
+		return nil
+	}
+	if remoteErr != nil {
+		fmt.Fprintf(dockerCli.Err(), "Unable to compare with manifest list %s in the registry: %v\n", targetRef, remoteErr)
+		return nil
+	}
+	return printRegistryDiff(dockerCli, targetRef, remote)
+}
+
+// seedManifestList saves the manifests of a list fetched from the registry in
+// the local list, except those replaced by the manifests being added.
+func seedManifestList(manifestStore store.Store, targetRef reference.Named, remote, added []types.ImageManifest) error {
+	replaced := map[digest.Digest]bool{}
+	for _, m := range added {
+		replaced[m.Descriptor.Digest] = true
+	}
+	for _, m := range remote {
+		if replaced[m.Descriptor.Digest] {
+			continue
+		}
+		var manifestRef reference.Named
+		if m.Ref != nil {
+			manifestRef = m.Ref.Named
+		} else {
+			var err error
+			manifestRef, err = reference.WithDigest(reference.TrimNamed(targetRef), m.Descriptor.Digest)
+			if err != nil {
+				return err
+			}
+		}
+		if err := manifestStore.Save(targetRef, manifestRef, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remoteListMetadata returns the metadata of a manifest list fetched from a
+// registry, given its media type and content. OCI image indexes keep their
+// annotations.
+func remoteListMetadata(mediaType string, payload []byte) (types.ManifestListMetadata, error) {
+	if mediaType != ocispec.MediaTypeImageIndex {
+		return types.ManifestListMetadata{}, nil
+	}
+	var index ocischema.ImageIndex
+	if err := json.Unmarshal(payload, &index); err != nil {
+		return types.ManifestListMetadata{}, err
+	}
+	return types.ManifestListMetadata{OCI: true, Annotations: index.Annotations}, nil
+}
+
+// printRegistryDiff reports how the local manifest list differs from the one
+// with the same name in the registry.
+func printRegistryDiff(dockerCli command.Cli, targetRef reference.Named, remote []types.ImageManifest) error {
+	local, err := dockerCli.ManifestStore().GetList(targetRef)
+	if err != nil {
+		return err
+	}
+
+	changes := diffManifestLists(remote, local)
+	if len(changes) == 0 {
+		fmt.Fprintf(dockerCli.Out(), "No changes compared to %s in the registry\n", targetRef)
+		return nil
+	}
+	fmt.Fprintf(dockerCli.Out(), "Changes compared to %s in the registry:\n", targetRef)
+	for _, change := range changes {
+		fmt.Fprintln(dockerCli.Out(), change)
+	}
 	return nil
 }
+
+// diffManifestLists compares manifests by digest, returning a line for each
+// manifest only in from ("-"), only in to ("+"), or whose platform changed
+// ("~").
+func diffManifestLists(from, to []types.ImageManifest) []string {
+	fromByDigest := map[digest.Digest]types.ImageManifest{}
+	for _, m := range from {
+		fromByDigest[m.Descriptor.Digest] = m
+	}
+	toByDigest := map[digest.Digest]types.ImageManifest{}
+	for _, m := range to {
+		toByDigest[m.Descriptor.Digest] = m
+	}
+
+	var changes []string
+	for _, m := range to {
+		old, ok := fromByDigest[m.Descriptor.Digest]
+		switch {
+		case !ok:
+			changes = append(changes, diffLine("+", m, platformString(m.Descriptor.Platform)))
+		case platformString(old.Descriptor.Platform) != platformString(m.Descriptor.Platform):
+			changes = append(changes, diffLine("~", m, platformString(old.Descriptor.Platform)+" -> "+platformString(m.Descriptor.Platform)))
+		}
+	}
+	for _, m := range from {
+		if _, ok := toByDigest[m.Descriptor.Digest]; !ok {
+			changes = append(changes, diffLine("-", m, platformString(m.Descriptor.Platform)))
+		}
+	}
+	return changes
+}
+
+func diffLine(op string, m types.ImageManifest, platform string) string {
+	if m.Ref == nil {
+		return fmt.Sprintf("%s %s %s", op, m.Descriptor.Digest, platform)
+	}
+	return fmt.Sprintf("%s %s (%s) %s", op, m.Ref, m.Descriptor.Digest, platform)
+}
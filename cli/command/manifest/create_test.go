@@ -8,6 +8,7 @@ import (
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -45,6 +46,7 @@ func TestManifestCreateAmend(t *testing.T) {
 
 	cli := test.NewFakeCli(nil)
 	cli.SetManifestStore(store)
+	cli.SetRegistryClient(&fakeRegistryClient{})
 
 	namedRef := ref(t, "alpine:3.0")
 	imageManifest := fullImageManifest(t, namedRef)
@@ -114,3 +116,128 @@ func TestManifestCreateNoManifest(t *testing.T) {
 	err := cmd.Execute()
 	assert.Error(t, err, "No such image: example.com/alpine:3.0")
 }
+
+// amend a manifest list and get the changes compared to the registry
+func TestManifestCreateAmendRegistryDiff(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	remote := fullImageManifest(t, ref(t, "list:v1"))
+	remote.Descriptor.Digest = "sha256:4b9d3e7bd4e8b1f4a2f9f8d2a8a3f6d6b0c0f1e2d3c4b5a69788796a5b4c3d2e"
+	remote.Descriptor.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestListFunc: func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+			return []manifesttypes.ImageManifest{remote}, nil
+		},
+	})
+
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, store.Save(ref(t, "list:v1"), namedRef, fullImageManifest(t, namedRef)))
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "create-amend-diff.golden")
+}
+
+// create an OCI image index with annotations, and inspect it
+func TestManifestCreateOCI(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return fullImageManifest(t, ref), nil
+		},
+	})
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--annotation", "com.example.key=value", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Error(t, cmd.Execute(), "annotations are only supported on OCI image indexes, use --oci")
+
+	cmd = newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--oci", "--annotation", "com.example.key=value", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	cli = test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	inspectCmd := newInspectCommand(cli)
+	inspectCmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, inspectCmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-oci-image-index.golden")
+}
+
+// amend a manifest list that only exists in the registry
+func TestManifestCreateAmendFromRegistry(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	remote := fullImageManifest(t, ref(t, "list:v1"))
+	remote.Descriptor.Digest = "sha256:4b9d3e7bd4e8b1f4a2f9f8d2a8a3f6d6b0c0f1e2d3c4b5a69788796a5b4c3d2e"
+	remote.Descriptor.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	remote.Ref = nil
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return fullImageManifest(t, ref), nil
+		},
+		getManifestListFunc: func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+			return []manifesttypes.ImageManifest{remote}, nil
+		},
+	})
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "create-amend-from-registry.golden")
+
+	list, err := store.GetList(ref(t, "list:v1"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(list, 2))
+}
+
+// amend an OCI image index that only exists in the registry, then turn it
+// into a Docker manifest list
+func TestManifestCreateAmendFromRegistryOCI(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return fullImageManifest(t, ref), nil
+		},
+		getManifestListFunc: func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+			return nil, nil
+		},
+		getListPayloadFunc: func(ctx context.Context, ref reference.Named) (string, []byte, error) {
+			return ocispec.MediaTypeImageIndex, []byte(remoteImageIndex), nil
+		},
+	})
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	metadata, err := store.GetListMetadata(ref(t, "list:v1"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(manifesttypes.ManifestListMetadata{OCI: true, Annotations: map[string]string{"com.example.key": "value"}}, metadata))
+
+	cmd = newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "--oci=false", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	metadata, err = store.GetListMetadata(ref(t, "list:v1"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(manifesttypes.ManifestListMetadata{}, metadata))
+}
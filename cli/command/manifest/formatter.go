@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"strings"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/manifest/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	defaultManifestListTableFormat = "table {{.Name}}\t{{.Manifests}}\t{{.Platforms}}"
+
+	manifestListNameHeader = "MANIFEST LIST"
+	manifestsHeader        = "MANIFESTS"
+	platformsHeader        = "PLATFORMS"
+)
+
+// manifestList is a manifest list in local storage
+type manifestList struct {
+	Name      string
+	Manifests []types.ImageManifest
+}
+
+// newManifestListFormat returns a Format for rendering using a manifest list Context
+func newManifestListFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if quiet {
+			return "{{.Name}}"
+		}
+		return defaultManifestListTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// manifestListFormatWrite writes formatted manifest lists using the Context
+func manifestListFormatWrite(ctx formatter.Context, lists []manifestList) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, list := range lists {
+			if err := format(&manifestListContext{l: list}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	mCtx := &manifestListContext{}
+	mCtx.Header = formatter.SubHeaderContext{
+		"Name":      manifestListNameHeader,
+		"Manifests": manifestsHeader,
+		"Platforms": platformsHeader,
+	}
+	return ctx.Write(mCtx, render)
+}
+
+type manifestListContext struct {
+	formatter.HeaderContext
+	l manifestList
+}
+
+func (c *manifestListContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *manifestListContext) Name() string {
+	return c.l.Name
+}
+
+func (c *manifestListContext) Manifests() string {
+	refs := []string{}
+	for _, m := range c.l.Manifests {
+		if m.Ref != nil {
+			refs = append(refs, m.Ref.String())
+		}
+	}
+	return strings.Join(refs, ", ")
+}
+
+func (c *manifestListContext) Platforms() string {
+	platforms := []string{}
+	for _, m := range c.l.Manifests {
+		if p := platformString(m.Descriptor.Platform); p != "" {
+			platforms = append(platforms, p)
+		}
+	}
+	return strings.Join(platforms, ", ")
+}
+
+// platformString returns the platform formatted as os/arch[/variant]
+func platformString(p *ocispec.Platform) string {
+	if p == nil || p.OS == "" && p.Architecture == "" {
+		return ""
+	}
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
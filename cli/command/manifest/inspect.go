@@ -9,6 +9,7 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/registry"
@@ -72,7 +73,11 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	// Try a local manifest list first
 	localManifestList, err := dockerCli.ManifestStore().GetList(namedRef)
 	if err == nil {
-		return printManifestList(dockerCli, namedRef, localManifestList, opts)
+		metadata, err := dockerCli.ManifestStore().GetListMetadata(namedRef)
+		if err != nil {
+			return err
+		}
+		return printManifestList(dockerCli, namedRef, localManifestList, metadata, opts)
 	}
 
 	// Next try a remote manifest
@@ -83,26 +88,29 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 		return printManifest(dockerCli, imageManifest, opts)
 	}
 
-	// Finally try a remote manifest list
-	manifestList, err := registryClient.GetManifestList(ctx, namedRef)
+	// Finally try a remote manifest list, displayed as fetched unless the
+	// referenced manifests are needed
+	if opts.verbose {
+		manifestList, err := registryClient.GetManifestList(ctx, namedRef)
+		if err != nil {
+			return err
+		}
+		return printManifestList(dockerCli, namedRef, manifestList, types.ManifestListMetadata{}, opts)
+	}
+	_, raw, err := registryClient.GetManifestListPayload(ctx, namedRef)
 	if err != nil {
 		return err
 	}
-	return printManifestList(dockerCli, namedRef, manifestList, opts)
+	return printRaw(dockerCli, raw)
 }
 
 func printManifest(dockerCli command.Cli, manifest types.ImageManifest, opts inspectOptions) error {
-	buffer := new(bytes.Buffer)
 	if !opts.verbose {
 		_, raw, err := manifest.Payload()
 		if err != nil {
 			return err
 		}
-		if err := json.Indent(buffer, raw, "", "\t"); err != nil {
-			return err
-		}
-		fmt.Fprintln(dockerCli.Out(), buffer.String())
-		return nil
+		return printRaw(dockerCli, raw)
 	}
 	jsonBytes, err := json.MarshalIndent(manifest, "", "\t")
 	if err != nil {
@@ -112,7 +120,7 @@ func printManifest(dockerCli command.Cli, manifest types.ImageManifest, opts ins
 	return nil
 }
 
-func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, opts inspectOptions) error {
+func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, metadata types.ManifestListMetadata, opts inspectOptions) error {
 	if !opts.verbose {
 		targetRepo, err := registry.ParseRepositoryInfo(namedRef)
 		if err != nil {
@@ -128,11 +136,16 @@ func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []t
 			}
 			manifests = append(manifests, mfd)
 		}
-		deserializedML, err := manifestlist.FromDescriptors(manifests)
+		var deserialized distribution.Manifest
+		if metadata.OCI {
+			deserialized, err = buildImageIndex(manifests, metadata.Annotations)
+		} else {
+			deserialized, err = manifestlist.FromDescriptors(manifests)
+		}
 		if err != nil {
 			return err
 		}
-		jsonBytes, err := deserializedML.MarshalJSON()
+		_, jsonBytes, err := deserialized.Payload()
 		if err != nil {
 			return err
 		}
@@ -146,3 +159,13 @@ func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []t
 	dockerCli.Out().Write(append(jsonBytes, '\n'))
 	return nil
 }
+
+// printRaw prints the indented JSON content of a manifest or manifest list
+func printRaw(dockerCli command.Cli, raw []byte) error {
+	buffer := new(bytes.Buffer)
+	if err := json.Indent(buffer, raw, "", "\t"); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), buffer.String())
+	return nil
+}
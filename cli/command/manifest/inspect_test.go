@@ -93,8 +93,8 @@ func TestInspectCommandNotFound(t *testing.T) {
 		getManifestFunc: func(_ context.Context, _ reference.Named) (manifesttypes.ImageManifest, error) {
 			return manifesttypes.ImageManifest{}, errors.New("missing")
 		},
-		getManifestListFunc: func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
-			return nil, errors.Errorf("No such manifest: %s", ref)
+		getListPayloadFunc: func(ctx context.Context, ref reference.Named) (string, []byte, error) {
+			return "", nil, errors.Errorf("No such manifest: %s", ref)
 		},
 	})
 
@@ -144,3 +144,30 @@ func TestInspectcommandRemoteManifest(t *testing.T) {
 	expected := golden.Get(t, "inspect-manifest.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+const remoteImageIndex = `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json",` +
+	`"manifests":[{"mediaType":"application/vnd.oci.image.manifest.v1+json","size":528,` +
+	`"digest":"sha256:1072e499f3f655a032e88542330cf75b02e7bdf673278f701d7ba61629ee3ebe",` +
+	`"platform":{"architecture":"amd64","os":"linux"}}],"annotations":{"com.example.key":"value"}}`
+
+func TestInspectCommandRemoteImageIndex(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return manifesttypes.ImageManifest{}, errors.Errorf("%s is a manifest list", ref)
+		},
+		getListPayloadFunc: func(ctx context.Context, ref reference.Named) (string, []byte, error) {
+			return ocispec.MediaTypeImageIndex, []byte(remoteImageIndex), nil
+		},
+	})
+
+	cmd := newInspectCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-remote-oci-image-index.golden")
+}
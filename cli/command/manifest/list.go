@@ -0,0 +1,65 @@
+package manifest
+
+import (
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/spf13/cobra"
+	"vbom.ml/util/sortorder"
+)
+
+type listOptions struct {
+	quiet  bool
+	format string
+}
+
+func newListCommand(dockerCli command.Cli) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List local manifest lists",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display manifest list names")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print manifest lists using a Go template")
+	return cmd
+}
+
+func runList(dockerCli command.Cli, opts listOptions) error {
+	manifestStore := dockerCli.ManifestStore()
+	refs, err := manifestStore.List()
+	if err != nil {
+		return err
+	}
+
+	lists := []manifestList{}
+	for _, ref := range refs {
+		manifests, err := manifestStore.GetList(ref)
+		if err != nil {
+			return err
+		}
+		lists = append(lists, manifestList{Name: ref.String(), Manifests: manifests})
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return sortorder.NaturalLess(lists[i].Name, lists[j].Name)
+	})
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	listCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newManifestListFormat(format, opts.quiet),
+	}
+	return manifestListFormatWrite(listCtx, lists)
+}
@@ -0,0 +1,43 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	"gotest.tools/golden"
+)
+
+func TestListCommand(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, store.Save(ref(t, "list:v1"), namedRef, fullImageManifest(t, namedRef)))
+	namedRef = ref(t, "alpine:3.0-arm")
+	imageManifest := fullImageManifest(t, namedRef)
+	imageManifest.Descriptor.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	assert.NilError(t, store.Save(ref(t, "list:v1"), namedRef, imageManifest))
+	namedRef = ref(t, "busybox:latest")
+	assert.NilError(t, store.Save(ref(t, "other:latest"), namedRef, fullImageManifest(t, namedRef)))
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "list-command"},
+		{name: "list-command-quiet", args: []string{"--quiet"}},
+		{name: "list-command-format", args: []string{"--format", "{{.Name}}: {{.Platforms}}"}},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(nil)
+		cli.SetManifestStore(store)
+		cmd := newListCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.NilError(t, cmd.Execute())
+		golden.Assert(t, cli.OutBuffer().String(), tc.name+".golden")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/manifest/ocischema"
 	"github.com/docker/cli/cli/manifest/types"
 	registryclient "github.com/docker/cli/cli/registry/client"
 	"github.com/docker/distribution"
@@ -15,6 +16,7 @@ import (
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/registry"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -37,7 +39,7 @@ type manifestBlob struct {
 
 type pushRequest struct {
 	targetRef     reference.Named
-	list          *manifestlist.DeserializedManifestList
+	list          distribution.Manifest
 	mountRequests []mountRequest
 	manifestBlobs []manifestBlob
 	insecure      bool
@@ -77,7 +79,12 @@ func runPush(dockerCli command.Cli, opts pushOpts) error {
 		return errors.Errorf("%s not found", targetRef)
 	}
 
-	pushRequest, err := buildPushRequest(manifests, targetRef, opts.insecure)
+	metadata, err := dockerCli.ManifestStore().GetListMetadata(targetRef)
+	if err != nil {
+		return err
+	}
+
+	pushRequest, err := buildPushRequest(manifests, metadata, targetRef, opts.insecure)
 	if err != nil {
 		return err
 	}
@@ -92,11 +99,11 @@ func runPush(dockerCli command.Cli, opts pushOpts) error {
 	return nil
 }
 
-func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named, insecure bool) (pushRequest, error) {
+func buildPushRequest(manifests []types.ImageManifest, metadata types.ManifestListMetadata, targetRef reference.Named, insecure bool) (pushRequest, error) {
 	req := pushRequest{targetRef: targetRef, insecure: insecure}
 
 	var err error
-	req.list, err = buildManifestList(manifests, targetRef)
+	req.list, err = buildManifestList(manifests, metadata, targetRef)
 	if err != nil {
 		return req, err
 	}
@@ -134,7 +141,9 @@ func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named
 	return req, nil
 }
 
-func buildManifestList(manifests []types.ImageManifest, targetRef reference.Named) (*manifestlist.DeserializedManifestList, error) {
+// buildManifestList builds a Docker manifest list, or an OCI image index if
+// set in the list's metadata.
+func buildManifestList(manifests []types.ImageManifest, metadata types.ManifestListMetadata, targetRef reference.Named) (distribution.Manifest, error) {
 	targetRepoInfo, err := registry.ParseRepositoryInfo(targetRef)
 	if err != nil {
 		return nil, err
@@ -155,9 +164,22 @@ func buildManifestList(manifests []types.ImageManifest, targetRef reference.Name
 		descriptors = append(descriptors, descriptor)
 	}
 
+	if metadata.OCI {
+		return buildImageIndex(descriptors, metadata.Annotations)
+	}
 	return manifestlist.FromDescriptors(descriptors)
 }
 
+func buildImageIndex(descriptors []manifestlist.ManifestDescriptor, annotations map[string]string) (*ocischema.DeserializedImageIndex, error) {
+	indexDescriptors := []distribution.Descriptor{}
+	for _, descriptor := range descriptors {
+		indexDescriptor := descriptor.Descriptor
+		indexDescriptor.Platform = types.OCIPlatform(&descriptor.Platform)
+		indexDescriptors = append(indexDescriptors, indexDescriptor)
+	}
+	return ocischema.FromDescriptors(indexDescriptors, annotations)
+}
+
 func buildManifestDescriptor(targetRepo *registry.RepositoryInfo, imageManifest types.ImageManifest) (manifestlist.ManifestDescriptor, error) {
 	repoInfo, err := registry.ParseRepositoryInfo(imageManifest.Ref)
 	if err != nil {
@@ -172,9 +194,10 @@ func buildManifestDescriptor(targetRepo *registry.RepositoryInfo, imageManifest
 
 	manifest := manifestlist.ManifestDescriptor{
 		Descriptor: distribution.Descriptor{
-			Digest:    imageManifest.Descriptor.Digest,
-			Size:      imageManifest.Descriptor.Size,
-			MediaType: imageManifest.Descriptor.MediaType,
+			Digest:      imageManifest.Descriptor.Digest,
+			Size:        imageManifest.Descriptor.Size,
+			MediaType:   imageManifest.Descriptor.MediaType,
+			Annotations: imageManifest.Descriptor.Annotations,
 		},
 	}
 
@@ -219,6 +242,15 @@ func buildPutManifestRequest(imageManifest types.ImageManifest, targetRef refere
 		return mountRequest{}, err
 	}
 
+	if imageManifest.OCIManifest != nil {
+		ociManifest, err := canonicalOCIManifest(imageManifest)
+		if err != nil {
+			return mountRequest{}, err
+		}
+		imageManifest.OCIManifest = ociManifest
+		return mountRequest{ref: mountRef, manifest: imageManifest}, nil
+	}
+
 	// This indentation has to be added to ensure sha parity with the registry
 	v2ManifestBytes, err := json.MarshalIndent(imageManifest.SchemaV2Manifest, "", "   ")
 	if err != nil {
@@ -235,6 +267,31 @@ func buildPutManifestRequest(imageManifest types.ImageManifest, targetRef refere
 	return mountRequest{ref: mountRef, manifest: imageManifest}, err
 }
 
+// canonicalOCIManifest restores the representation of an OCI image manifest
+// that matches its digest, as it is not preserved in local storage. Both the
+// indented form used by the registry and the compact form are tried.
+func canonicalOCIManifest(imageManifest types.ImageManifest) (*ocischema.DeserializedManifest, error) {
+	candidates := []func(interface{}) ([]byte, error){
+		func(v interface{}) ([]byte, error) { return json.MarshalIndent(v, "", "   ") },
+		json.Marshal,
+	}
+	for _, marshal := range candidates {
+		raw, err := marshal(imageManifest.OCIManifest.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		if digest.FromBytes(raw) != imageManifest.Descriptor.Digest {
+			continue
+		}
+		var ociManifest ocischema.DeserializedManifest
+		if err := ociManifest.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+		return &ociManifest, nil
+	}
+	return nil, errors.Errorf("unable to reproduce the content of manifest %s; push it to the target repository first", imageManifest.Ref)
+}
+
 func pushList(ctx context.Context, dockerCli command.Cli, req pushRequest) error {
 	rclient := dockerCli.RegistryClient(req.insecure)
 
@@ -7,9 +7,14 @@ import (
 
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
 	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
 )
 
 func newFakeRegistryClient() *fakeRegistryClient {
@@ -67,3 +72,37 @@ func TestManifestPush(t *testing.T) {
 	err = cmd.Execute()
 	assert.NilError(t, err)
 }
+
+func TestManifestPushOCIImageIndex(t *testing.T) {
+	store, sCleanup := newTempManifestStore(t)
+	defer sCleanup()
+
+	var pushed distribution.Manifest
+	registry := newFakeRegistryClient()
+	registry.putManifestFunc = func(_ context.Context, _ reference.Named, mf distribution.Manifest) (digest.Digest, error) {
+		pushed = mf
+		return "", nil
+	}
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(registry)
+
+	namedRef := ref(t, "alpine:3.0")
+	imageManifest := fullImageManifest(t, namedRef)
+	imageManifest.Descriptor.Annotations = map[string]string{"com.example.descriptor": "value"}
+	assert.NilError(t, store.Save(ref(t, "list:v1"), namedRef, imageManifest))
+	assert.NilError(t, store.SaveListMetadata(ref(t, "list:v1"), manifesttypes.ManifestListMetadata{
+		OCI:         true,
+		Annotations: map[string]string{"com.example.index": "value"},
+	}))
+
+	cmd := newPushListCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+
+	mediaType, payload, err := pushed.Payload()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ocispec.MediaTypeImageIndex, mediaType))
+	golden.Assert(t, string(payload), "push-oci-image-index.golden")
+}
@@ -0,0 +1,50 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newRmManifestListCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm MANIFEST_LIST [MANIFEST_LIST...]",
+		Aliases: []string{"remove"},
+		Short:   "Delete one or more manifest lists from local storage",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRm(dockerCli, args)
+		},
+	}
+	return cmd
+}
+
+func runRm(dockerCli command.Cli, targets []string) error {
+	manifestStore := dockerCli.ManifestStore()
+
+	var errs []string
+	for _, target := range targets {
+		targetRef, err := normalizeReference(target)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if _, err := manifestStore.GetList(targetRef); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := manifestStore.Remove(targetRef); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		fmt.Fprintln(dockerCli.Out(), target)
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRmCommand(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, store.Save(ref(t, "list:v1"), namedRef, fullImageManifest(t, namedRef)))
+	assert.NilError(t, store.Save(ref(t, "list:v2"), namedRef, fullImageManifest(t, namedRef)))
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cmd := newRmManifestListCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1", "example.com/list:missing", "example.com/list:v2"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Error(t, cmd.Execute(), "No such manifest: example.com/list:missing")
+	assert.Check(t, is.Equal("example.com/list:v1\nexample.com/list:v2\n", cli.OutBuffer().String()))
+
+	refs, err := store.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(refs, 0))
+}
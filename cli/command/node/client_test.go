@@ -18,6 +18,8 @@ type fakeClient struct {
 	taskInspectFunc    func(taskID string) (swarm.Task, []byte, error)
 	taskListFunc       func(options types.TaskListOptions) ([]swarm.Task, error)
 	serviceInspectFunc func(ctx context.Context, serviceID string, opts types.ServiceInspectOptions) (swarm.Service, []byte, error)
+	serviceListFunc    func(options types.ServiceListOptions) ([]swarm.Service, error)
+	serviceUpdateFunc  func(serviceID string, version swarm.Version, service swarm.ServiceSpec) (types.ServiceUpdateResponse, error)
 }
 
 func (cli *fakeClient) NodeInspectWithRaw(ctx context.Context, ref string) (swarm.Node, []byte, error) {
@@ -75,3 +77,17 @@ func (cli *fakeClient) ServiceInspectWithRaw(ctx context.Context, serviceID stri
 	}
 	return swarm.Service{}, []byte{}, nil
 }
+
+func (cli *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+	if cli.serviceListFunc != nil {
+		return cli.serviceListFunc(options)
+	}
+	return []swarm.Service{}, nil
+}
+
+func (cli *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+	if cli.serviceUpdateFunc != nil {
+		return cli.serviceUpdateFunc(serviceID, version, service)
+	}
+	return types.ServiceUpdateResponse{}, nil
+}
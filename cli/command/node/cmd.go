@@ -2,11 +2,10 @@ package node
 
 import (
 	"context"
-	"errors"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
-	"github.com/docker/docker/api/types"
+	"github.com/docker/cli/cli/command/idresolver"
 	apiclient "github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 )
@@ -39,22 +38,5 @@ func NewNodeCommand(dockerCli command.Cli) *cobra.Command {
 // reference is mapped to the current node, hence the node ID is retrieved using
 // the `/info` endpoint.
 func Reference(ctx context.Context, client apiclient.APIClient, ref string) (string, error) {
-	if ref == "self" {
-		info, err := client.Info(ctx)
-		if err != nil {
-			return "", err
-		}
-		if info.Swarm.NodeID == "" {
-			// If there's no node ID in /info, the node probably
-			// isn't a manager. Call a swarm-specific endpoint to
-			// get a more specific error message.
-			_, err = client.NodeList(ctx, types.NodeListOptions{})
-			if err != nil {
-				return "", err
-			}
-			return "", errors.New("node ID not found in /info")
-		}
-		return info.Swarm.NodeID, nil
-	}
-	return ref, nil
+	return idresolver.NodeReference(ctx, client, ref)
 }
@@ -3,7 +3,10 @@ package node
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli/command"
@@ -78,6 +81,18 @@ TLS Info:
 	managerStatusHeader = "MANAGER STATUS"
 	engineVersionHeader = "ENGINE VERSION"
 	tlsStatusHeader     = "TLS STATUS"
+
+	defaultNodeResourcesTableFormat = "table {{.ID}} {{if .Self}}*{{else}} {{ end }}\t{{.Hostname}}\t{{.CPUs}}\t{{.CPUReserved}}\t{{.CPULimit}}\t{{.CPUFree}}\t{{.Memory}}\t{{.MemoryReserved}}\t{{.MemoryLimit}}\t{{.MemoryFree}}\t{{.GenericResources}}"
+
+	cpusHeader             = "CPUS"
+	cpuReservedHeader      = "CPU RESERVED"
+	cpuLimitHeader         = "CPU LIMIT"
+	cpuFreeHeader          = "CPU FREE"
+	memoryHeader           = "MEMORY"
+	memoryReservedHeader   = "MEMORY RESERVED"
+	memoryLimitHeader      = "MEMORY LIMIT"
+	memoryFreeHeader       = "MEMORY FREE"
+	genericResourcesHeader = "GENERIC RESOURCES"
 )
 
 // NewFormat returns a Format for rendering using a node Context
@@ -180,6 +195,129 @@ func (c *nodeContext) EngineVersion() string {
 	return c.n.Description.Engine.EngineVersion
 }
 
+// resourcesFormatWrite writes the context of the nodes along with the
+// resources used by their tasks
+func resourcesFormatWrite(ctx formatter.Context, nodes []swarm.Node, info types.Info, usage map[string]*nodeUsage) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, node := range nodes {
+			nodeCtx := &nodeResourcesContext{nodeContext: nodeContext{n: node, info: info}}
+			if u, ok := usage[node.ID]; ok {
+				nodeCtx.usage = *u
+			}
+			if err := format(nodeCtx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	nodeCtx := nodeResourcesContext{}
+	nodeCtx.Header = formatter.SubHeaderContext{
+		"ID":               nodeIDHeader,
+		"Self":             selfHeader,
+		"Hostname":         hostnameHeader,
+		"Status":           formatter.StatusHeader,
+		"Availability":     availabilityHeader,
+		"ManagerStatus":    managerStatusHeader,
+		"EngineVersion":    engineVersionHeader,
+		"TLSStatus":        tlsStatusHeader,
+		"CPUs":             cpusHeader,
+		"CPUReserved":      cpuReservedHeader,
+		"CPULimit":         cpuLimitHeader,
+		"CPUFree":          cpuFreeHeader,
+		"Memory":           memoryHeader,
+		"MemoryReserved":   memoryReservedHeader,
+		"MemoryLimit":      memoryLimitHeader,
+		"MemoryFree":       memoryFreeHeader,
+		"GenericResources": genericResourcesHeader,
+	}
+	return ctx.Write(&nodeCtx, render)
+}
+
+// nodeResourcesContext compares the resources of a node with the sum of the
+// reservations and limits of its tasks. Usages above 100% of the resources of
+// the node show that they are overcommitted.
+type nodeResourcesContext struct {
+	nodeContext
+	usage nodeUsage
+}
+
+func (c *nodeResourcesContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *nodeResourcesContext) CPUs() string {
+	return formatNanoCPUs(c.n.Description.Resources.NanoCPUs)
+}
+
+func (c *nodeResourcesContext) CPUReserved() string {
+	return withPercentage(formatNanoCPUs(c.usage.reservedNanoCPUs), c.usage.reservedNanoCPUs, c.n.Description.Resources.NanoCPUs)
+}
+
+func (c *nodeResourcesContext) CPULimit() string {
+	return withPercentage(formatNanoCPUs(c.usage.limitNanoCPUs), c.usage.limitNanoCPUs, c.n.Description.Resources.NanoCPUs)
+}
+
+func (c *nodeResourcesContext) CPUFree() string {
+	return formatNanoCPUs(free(c.n.Description.Resources.NanoCPUs, c.usage.reservedNanoCPUs))
+}
+
+func (c *nodeResourcesContext) Memory() string {
+	return units.BytesSize(float64(c.n.Description.Resources.MemoryBytes))
+}
+
+func (c *nodeResourcesContext) MemoryReserved() string {
+	return withPercentage(units.BytesSize(float64(c.usage.reservedMemory)), c.usage.reservedMemory, c.n.Description.Resources.MemoryBytes)
+}
+
+func (c *nodeResourcesContext) MemoryLimit() string {
+	return withPercentage(units.BytesSize(float64(c.usage.limitMemory)), c.usage.limitMemory, c.n.Description.Resources.MemoryBytes)
+}
+
+func (c *nodeResourcesContext) MemoryFree() string {
+	return units.BytesSize(float64(free(c.n.Description.Resources.MemoryBytes, c.usage.reservedMemory)))
+}
+
+// GenericResources shows the reserved and available generic resources of
+// each kind, such as "gpu=1/2"
+func (c *nodeResourcesContext) GenericResources() string {
+	available := genericResources(c.n.Description.Resources.GenericResources)
+	kinds := []string{}
+	for kind := range available {
+		kinds = append(kinds, kind)
+	}
+	for kind := range c.usage.reservedGeneric {
+		if _, ok := available[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	resources := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		resources = append(resources, fmt.Sprintf("%s=%d/%d", kind, c.usage.reservedGeneric[kind], available[kind]))
+	}
+	return strings.Join(resources, ", ")
+}
+
+func formatNanoCPUs(nanoCPUs int64) string {
+	return strconv.FormatFloat(float64(nanoCPUs)/1e9, 'f', -1, 64)
+}
+
+// withPercentage appends the percentage of the total that is used, if the
+// total is known
+func withPercentage(value string, used, total int64) string {
+	if total == 0 {
+		return value
+	}
+	return fmt.Sprintf("%s (%d%%)", value, int64(math.Round(float64(used)*100/float64(total))))
+}
+
+func free(total, used int64) int64 {
+	if used > total {
+		return 0
+	}
+	return total - used
+}
+
 // InspectFormatWrite renders the context for a list of nodes
 func InspectFormatWrite(ctx formatter.Context, refs []string, getRef inspect.GetRefFunc) error {
 	if ctx.Format != nodeInspectPrettyTemplate {
@@ -9,14 +9,16 @@ import (
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/spf13/cobra"
 	"vbom.ml/util/sortorder"
 )
 
 type listOptions struct {
-	quiet  bool
-	format string
-	filter opts.FilterOpt
+	quiet     bool
+	resources bool
+	format    string
+	filter    opts.FilterOpt
 }
 
 func newListCommand(dockerCli command.Cli) *cobra.Command {
@@ -33,6 +35,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
+	flags.BoolVar(&options.resources, "resources", false, "Display the resources of the nodes, and the reservations and limits of their tasks")
 	flags.StringVar(&options.format, "format", "", "Pretty-print nodes using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
@@ -62,7 +65,9 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	format := options.format
 	if len(format) == 0 {
 		format = formatter.TableFormatKey
-		if len(dockerCli.ConfigFile().NodesFormat) > 0 && !options.quiet {
+		if options.resources && !options.quiet {
+			format = defaultNodeResourcesTableFormat
+		} else if len(dockerCli.ConfigFile().NodesFormat) > 0 && !options.quiet {
 			format = dockerCli.ConfigFile().NodesFormat
 		}
 	}
@@ -74,5 +79,15 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	sort.Slice(nodes, func(i, j int) bool {
 		return sortorder.NaturalLess(nodes[i].Description.Hostname, nodes[j].Description.Hostname)
 	})
+	if options.resources && !options.quiet {
+		// the resources used on a node are the ones of the tasks assigned to it
+		tasks, err := client.TaskList(ctx, types.TaskListOptions{
+			Filters: filters.NewArgs(filters.Arg("desired-state", "running")),
+		})
+		if err != nil {
+			return err
+		}
+		return resourcesFormatWrite(nodesCtx, nodes, info, computeUsage(tasks))
+	}
 	return FormatWrite(nodesCtx, nodes, info)
 }
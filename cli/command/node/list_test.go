@@ -139,3 +139,50 @@ func TestNodeListFormat(t *testing.T) {
 	assert.NilError(t, cmd.Execute())
 	golden.Assert(t, cli.OutBuffer().String(), "node-list-format-flag.golden")
 }
+
+func TestNodeListResources(t *testing.T) {
+	resources := func(nanoCPUs, memoryBytes int64, generic ...swarm.GenericResource) func(*swarm.Node) {
+		return func(node *swarm.Node) {
+			node.Description.Resources = swarm.Resources{NanoCPUs: nanoCPUs, MemoryBytes: memoryBytes, GenericResources: generic}
+		}
+	}
+	task := func(nodeID string, reservations, limits *swarm.Resources) swarm.Task {
+		return swarm.Task{NodeID: nodeID, Spec: swarm.TaskSpec{
+			Resources: &swarm.ResourceRequirements{Reservations: reservations, Limits: limits},
+		}}
+	}
+	gpu := swarm.GenericResource{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "gpu", Value: 2}}
+	ssd := swarm.GenericResource{NamedResourceSpec: &swarm.NamedGenericResource{Kind: "ssd", Value: "ssd0"}}
+
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{
+				*Node(NodeID("nodeID1"), Hostname("node-1"), resources(4e9, 8*1024*1024*1024, gpu, ssd)),
+				*Node(NodeID("nodeID2"), Hostname("node-2"), resources(2e9, 4*1024*1024*1024)),
+				*Node(NodeID("nodeID3"), Hostname("node-3"), resources(1e9, 1024*1024*1024)),
+			}, nil
+		},
+		infoFunc: func() (types.Info, error) {
+			return types.Info{Swarm: swarm.Info{NodeID: "nodeID1"}}, nil
+		},
+		taskListFunc: func(options types.TaskListOptions) ([]swarm.Task, error) {
+			assert.Check(t, options.Filters.ExactMatch("desired-state", "running"))
+			return []swarm.Task{
+				task("nodeID1",
+					&swarm.Resources{NanoCPUs: 1.5e9, MemoryBytes: 2 * 1024 * 1024 * 1024, GenericResources: []swarm.GenericResource{
+						{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "gpu", Value: 1}},
+					}},
+					&swarm.Resources{NanoCPUs: 2e9, MemoryBytes: 4 * 1024 * 1024 * 1024}),
+				task("nodeID1", nil, &swarm.Resources{NanoCPUs: 4e9}),
+				task("nodeID2", &swarm.Resources{NanoCPUs: 0.5e9, MemoryBytes: 512 * 1024 * 1024}, nil),
+				task("nodeID2", nil, nil),
+				// not assigned to a node yet
+				task("", &swarm.Resources{NanoCPUs: 1e9}, nil),
+			}, nil
+		},
+	})
+	cmd := newListCommand(cli)
+	assert.NilError(t, cmd.Flags().Set("resources", "true"))
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "node-list-resources.golden")
+}
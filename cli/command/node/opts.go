@@ -1,6 +1,8 @@
 package node
 
 import (
+	"time"
+
 	"github.com/docker/cli/opts"
 )
 
@@ -8,6 +10,9 @@ type nodeOptions struct {
 	annotations
 	role         string
 	availability string
+	wait         bool
+	waitTimeout  time.Duration
+	rebalance    bool
 }
 
 type annotations struct {
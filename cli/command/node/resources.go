@@ -0,0 +1,62 @@
+package node
+
+import (
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// nodeUsage is the sum of the resources of the tasks assigned to a node
+type nodeUsage struct {
+	reservedNanoCPUs int64
+	limitNanoCPUs    int64
+	reservedMemory   int64
+	limitMemory      int64
+	reservedGeneric  map[string]int64
+}
+
+// computeUsage sums the reservations and limits of the tasks, by node. Tasks
+// that set no limit are not counted in the limits, even though they can use
+// all the resources of their node.
+func computeUsage(tasks []swarm.Task) map[string]*nodeUsage {
+	usage := map[string]*nodeUsage{}
+	for _, task := range tasks {
+		if task.NodeID == "" {
+			continue
+		}
+		u, ok := usage[task.NodeID]
+		if !ok {
+			u = &nodeUsage{reservedGeneric: map[string]int64{}}
+			usage[task.NodeID] = u
+		}
+		resources := task.Spec.Resources
+		if resources == nil {
+			continue
+		}
+		if limits := resources.Limits; limits != nil {
+			u.limitNanoCPUs += limits.NanoCPUs
+			u.limitMemory += limits.MemoryBytes
+		}
+		if reservations := resources.Reservations; reservations != nil {
+			u.reservedNanoCPUs += reservations.NanoCPUs
+			u.reservedMemory += reservations.MemoryBytes
+			for kind, value := range genericResources(reservations.GenericResources) {
+				u.reservedGeneric[kind] += value
+			}
+		}
+	}
+	return usage
+}
+
+// genericResources counts generic resources by kind. Each named resource
+// counts as one.
+func genericResources(resources []swarm.GenericResource) map[string]int64 {
+	count := map[string]int64{}
+	for _, resource := range resources {
+		switch {
+		case resource.DiscreteResourceSpec != nil:
+			count[resource.DiscreteResourceSpec.Kind] += resource.DiscreteResourceSpec.Value
+		case resource.NamedResourceSpec != nil:
+			count[resource.NamedResourceSpec.Kind]++
+		}
+	}
+	return count
+}
@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -25,7 +26,7 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 		Short: "Update a node",
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runUpdate(dockerCli, cmd.Flags(), args[0])
+			return runUpdate(dockerCli, cmd.Flags(), options, args[0])
 		},
 	}
 
@@ -35,14 +36,45 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.Var(&options.annotations.labels, flagLabelAdd, "Add or update a node label (key=value)")
 	labelKeys := opts.NewListOpts(nil)
 	flags.Var(&labelKeys, flagLabelRemove, "Remove a node label if exists")
+	flags.BoolVar(&options.wait, flagWait, false, "Wait for the tasks of a drained node to be rescheduled, or for the services rebalanced with --rebalance to converge")
+	flags.DurationVar(&options.waitTimeout, flagWaitTimeout, 5*time.Minute, "Maximum time to wait, with --wait")
+	flags.BoolVar(&options.rebalance, flagRebalance, false, "Force an update of the replicated services that can run on the activated node to spread their tasks on it")
 	return cmd
 }
 
-func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, nodeID string) error {
+func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *nodeOptions, nodeID string) error {
+	drain := flags.Changed(flagAvailability) && swarm.NodeAvailability(options.availability) == swarm.NodeAvailabilityDrain
+	if options.rebalance && (!flags.Changed(flagAvailability) || swarm.NodeAvailability(options.availability) != swarm.NodeAvailabilityActive) {
+		return errors.Errorf("--%s can only be used with --%s active", flagRebalance, flagAvailability)
+	}
+	if options.wait && !drain && !options.rebalance {
+		return errors.Errorf("--%s can only be used with --%s drain or --%s", flagWait, flagAvailability, flagRebalance)
+	}
+
+	ctx := context.Background()
+	var tasks []swarm.Task
+	if options.wait && drain {
+		// the tasks to wait for are the ones running before the node is drained
+		var err error
+		if tasks, err = replicatedTasks(ctx, dockerCli.Client(), nodeID); err != nil {
+			return err
+		}
+	}
+
 	success := func(_ string) {
 		fmt.Fprintln(dockerCli.Out(), nodeID)
 	}
-	return updateNodes(dockerCli, []string{nodeID}, mergeNodeUpdate(flags), success)
+	if err := updateNodes(dockerCli, []string{nodeID}, mergeNodeUpdate(flags), success); err != nil {
+		return err
+	}
+
+	if options.rebalance {
+		return rebalanceServices(ctx, dockerCli, nodeID, options.wait, options.waitTimeout)
+	}
+	if options.wait {
+		return waitOnDrain(ctx, dockerCli, nodeID, tasks, options.waitTimeout)
+	}
+	return nil
 }
 
 func updateNodes(dockerCli command.Cli, nodes []string, mergeNode func(node *swarm.Node) error, success func(nodeID string)) error {
@@ -117,4 +149,7 @@ const (
 	flagAvailability = "availability"
 	flagLabelAdd     = "label-add"
 	flagLabelRemove  = "label-rm"
+	flagWait         = "wait"
+	flagWaitTimeout  = "wait-timeout"
+	flagRebalance    = "rebalance"
 )
@@ -0,0 +1,212 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	servicecli "github.com/docker/cli/cli/command/service"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// waitInterval is the time between two checks of the tasks of a drained node
+var waitInterval = 500 * time.Millisecond
+
+// replicatedTasks lists the tasks of the replicated services that run on the
+// node. The tasks of global services have no slot, and are not rescheduled
+// when the node is drained.
+func replicatedTasks(ctx context.Context, client client.APIClient, nodeID string) ([]swarm.Task, error) {
+	tasks, err := client.TaskList(ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(filters.Arg("node", nodeID), filters.Arg("desired-state", "running")),
+	})
+	if err != nil {
+		return nil, err
+	}
+	replicated := []swarm.Task{}
+	for _, task := range tasks {
+		if task.Slot != 0 {
+			replicated = append(replicated, task)
+		}
+	}
+	return replicated, nil
+}
+
+// drainProgress is the progress of the rescheduling of the tasks of a service
+type drainProgress struct {
+	name        string
+	tasks       []swarm.Task
+	rescheduled int
+}
+
+// waitOnDrain waits for the tasks that were running on the node to be shut
+// down, and for their replacements to be running on other nodes. The
+// progress of each service is printed when it changes.
+func waitOnDrain(ctx context.Context, dockerCli command.Cli, nodeID string, tasks []swarm.Task, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	client := dockerCli.Client()
+
+	services := map[string]*drainProgress{}
+	for _, task := range tasks {
+		if services[task.ServiceID] == nil {
+			service, _, err := client.ServiceInspectWithRaw(ctx, task.ServiceID, types.ServiceInspectOptions{})
+			if err != nil {
+				return err
+			}
+			services[task.ServiceID] = &drainProgress{name: service.Spec.Name}
+		}
+		services[task.ServiceID].tasks = append(services[task.ServiceID].tasks, task)
+	}
+	serviceIDs := make([]string, 0, len(services))
+	for id := range services {
+		serviceIDs = append(serviceIDs, id)
+	}
+	sort.Slice(serviceIDs, func(i, j int) bool { return services[serviceIDs[i]].name < services[serviceIDs[j]].name })
+
+	timeoutErr := func() error {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Errorf("the tasks of node %s were not rescheduled after %s", nodeID, timeout)
+		}
+		return ctx.Err()
+	}
+	for {
+		remaining := 0
+		for _, id := range serviceIDs {
+			service := services[id]
+			if service.rescheduled == len(service.tasks) {
+				continue
+			}
+			serviceTasks, err := client.TaskList(ctx, types.TaskListOptions{
+				Filters: filters.NewArgs(filters.Arg("service", id)),
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return timeoutErr()
+				}
+				return err
+			}
+			rescheduled := 0
+			for _, task := range service.tasks {
+				if isRescheduled(task, serviceTasks) {
+					rescheduled++
+				}
+			}
+			if rescheduled != service.rescheduled {
+				service.rescheduled = rescheduled
+				fmt.Fprintf(dockerCli.Out(), "%s: %d/%d tasks rescheduled\n", service.name, rescheduled, len(service.tasks))
+			}
+			remaining += len(service.tasks) - rescheduled
+		}
+		if remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return timeoutErr()
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// isRescheduled returns whether the task is shut down, and a task of the same
+// slot is running on another node
+func isRescheduled(task swarm.Task, serviceTasks []swarm.Task) bool {
+	replaced := false
+	for _, t := range serviceTasks {
+		switch {
+		case t.ID == task.ID:
+			if !isTerminated(t.Status.State) {
+				return false
+			}
+		case t.Slot == task.Slot && t.NodeID != task.NodeID:
+			if t.DesiredState == swarm.TaskStateRunning && t.Status.State == swarm.TaskStateRunning {
+				replaced = true
+			}
+		}
+	}
+	return replaced
+}
+
+// isTerminated returns whether a task in the given state has stopped for good
+func isTerminated(state swarm.TaskState) bool {
+	switch state {
+	case swarm.TaskStateComplete, swarm.TaskStateShutdown, swarm.TaskStateFailed,
+		swarm.TaskStateRejected, swarm.TaskStateRemove, swarm.TaskStateOrphaned:
+		return true
+	}
+	return false
+}
+
+// rebalanceServices forces an update of the replicated services that can be
+// placed on a node that was just activated, for the scheduler to spread their
+// tasks again, including on that node. The services of which the placement
+// constraints, platforms or resource reservations exclude the node are left
+// untouched. The tasks are replaced according to the update config of each
+// service.
+func rebalanceServices(ctx context.Context, dockerCli command.Cli, nodeID string, wait bool, timeout time.Duration) error {
+	client := dockerCli.Client()
+
+	node, _, err := client.NodeInspectWithRaw(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+	services, err := client.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Spec.Name < services[j].Spec.Name })
+
+	rebalanced := []swarm.Service{}
+	for _, service := range services {
+		replicated := service.Spec.Mode.Replicated
+		if replicated == nil || replicated.Replicas == nil || *replicated.Replicas == 0 {
+			continue
+		}
+		report, err := servicecli.SimulatePlacement(service.Spec, []swarm.Node{node})
+		if err != nil {
+			return errors.Wrapf(err, "failed to rebalance service %s", service.Spec.Name)
+		}
+		if len(report.Nodes[0].Reasons) > 0 {
+			continue
+		}
+		spec := service.Spec
+		spec.TaskTemplate.ForceUpdate++
+		response, err := client.ServiceUpdate(ctx, service.ID, service.Version, spec, types.ServiceUpdateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to rebalance service %s", service.Spec.Name)
+		}
+		for _, warning := range response.Warnings {
+			fmt.Fprintln(dockerCli.Err(), warning)
+		}
+		fmt.Fprintf(dockerCli.Out(), "Rebalancing service %s\n", service.Spec.Name)
+		rebalanced = append(rebalanced, service)
+	}
+	if !wait {
+		return nil
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	for _, service := range rebalanced {
+		if err := servicecli.WaitOnService(ctx, dockerCli, service.ID, false); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return errors.Errorf("service %s was not rebalanced after %s", service.Spec.Name, timeout)
+			}
+			return errors.Wrapf(err, "failed to rebalance service %s", service.Spec.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package node
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func runningTask(id, serviceID, nodeID string, slot int) swarm.Task {
+	return swarm.Task{
+		ID:           id,
+		ServiceID:    serviceID,
+		NodeID:       nodeID,
+		Slot:         slot,
+		DesiredState: swarm.TaskStateRunning,
+		Status:       swarm.TaskStatus{State: swarm.TaskStateRunning},
+	}
+}
+
+func shutdownTask(task swarm.Task) swarm.Task {
+	task.DesiredState = swarm.TaskStateShutdown
+	task.Status.State = swarm.TaskStateShutdown
+	return task
+}
+
+func TestNodeUpdateWaitErrors(t *testing.T) {
+	testCases := []struct {
+		flags         map[string]string
+		expectedError string
+	}{
+		{
+			flags:         map[string]string{"wait": "true"},
+			expectedError: "--wait can only be used with --availability drain or --rebalance",
+		},
+		{
+			flags:         map[string]string{"wait": "true", "availability": "pause"},
+			expectedError: "--wait can only be used with --availability drain or --rebalance",
+		},
+		{
+			flags:         map[string]string{"rebalance": "true"},
+			expectedError: "--rebalance can only be used with --availability active",
+		},
+		{
+			flags:         map[string]string{"rebalance": "true", "availability": "drain"},
+			expectedError: "--rebalance can only be used with --availability active",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newUpdateCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs([]string{"node1"})
+		for key, value := range tc.flags {
+			assert.NilError(t, cmd.Flags().Set(key, value))
+		}
+		cmd.SetOutput(ioutil.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+	}
+}
+
+func TestNodeUpdateDrainWait(t *testing.T) {
+	defer func(interval time.Duration) { waitInterval = interval }(waitInterval)
+	waitInterval = time.Millisecond
+
+	web1 := runningTask("web1", "web", "node1", 1)
+	web2 := runningTask("web2", "web", "node2", 2)
+	db1 := runningTask("db1", "db", "node1", 1)
+	agent := runningTask("agent", "agent", "node1", 0)
+
+	var (
+		drained bool
+		polls   int
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		nodeUpdateFunc: func(nodeID string, version swarm.Version, node swarm.NodeSpec) error {
+			assert.Check(t, is.Equal(swarm.NodeAvailabilityDrain, node.Availability))
+			drained = true
+			return nil
+		},
+		serviceInspectFunc: func(_ context.Context, serviceID string, _ types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: serviceID, Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: serviceID}}}, nil, nil
+		},
+		taskListFunc: func(options types.TaskListOptions) ([]swarm.Task, error) {
+			if !drained {
+				assert.Check(t, options.Filters.ExactMatch("node", "node1"))
+				return []swarm.Task{web1, db1, agent}, nil
+			}
+			polls++
+			switch options.Filters.Get("service")[0] {
+			case "web":
+				// the replacement of the task starts on the second check
+				if polls < 3 {
+					return []swarm.Task{shutdownTask(web1), web2}, nil
+				}
+				return []swarm.Task{shutdownTask(web1), web2, runningTask("web3", "web", "node2", 1)}, nil
+			default:
+				return []swarm.Task{shutdownTask(db1), runningTask("db2", "db", "node3", 1)}, nil
+			}
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--availability", "drain", "--wait", "node1"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("node1\ndb: 1/1 tasks rescheduled\nweb: 1/1 tasks rescheduled\n", cli.OutBuffer().String()))
+}
+
+func TestNodeUpdateDrainWaitTimeout(t *testing.T) {
+	defer func(interval time.Duration) { waitInterval = interval }(waitInterval)
+	waitInterval = time.Millisecond
+
+	web1 := runningTask("web1", "web", "node1", 1)
+	cli := test.NewFakeCli(&fakeClient{
+		taskListFunc: func(options types.TaskListOptions) ([]swarm.Task, error) {
+			return []swarm.Task{web1}, nil
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--availability", "drain", "--wait", "--wait-timeout", "20ms", "node1"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "the tasks of node node1 were not rescheduled after 20ms"))
+}
+
+func TestNodeUpdateRebalance(t *testing.T) {
+	replicas := uint64(2)
+	none := uint64(0)
+	updated := map[string]uint64{}
+	cli := test.NewFakeCli(&fakeClient{
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return *Node(NodeID("node1"), NodeLabels(map[string]string{"zone": "east"})), []byte{}, nil
+		},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{
+				{ID: "web", Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "web"},
+					Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+				}},
+				{ID: "east", Spec: swarm.ServiceSpec{
+					Annotations:  swarm.Annotations{Name: "east"},
+					Mode:         swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+					TaskTemplate: swarm.TaskSpec{Placement: &swarm.Placement{Constraints: []string{"node.labels.zone==east"}}},
+				}},
+				{ID: "west", Spec: swarm.ServiceSpec{
+					Annotations:  swarm.Annotations{Name: "west"},
+					Mode:         swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+					TaskTemplate: swarm.TaskSpec{Placement: &swarm.Placement{Constraints: []string{"node.labels.zone==west"}}},
+				}},
+				{ID: "agent", Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "agent"},
+					Mode:        swarm.ServiceMode{Global: &swarm.GlobalService{}},
+				}},
+				{ID: "batch", Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "batch"},
+					Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &none}},
+				}},
+			}, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, service swarm.ServiceSpec) (types.ServiceUpdateResponse, error) {
+			updated[serviceID] = service.TaskTemplate.ForceUpdate
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--availability", "active", "--rebalance", "node1"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(map[string]uint64{"web": 1, "east": 1}, updated))
+	assert.Check(t, is.Equal("node1\nRebalancing service east\nRebalancing service web\n", cli.OutBuffer().String()))
+}
@@ -24,6 +24,7 @@ func NewSecretCommand(dockerCli command.Cli) *cobra.Command {
 		newSecretCreateCommand(dockerCli),
 		newSecretInspectCommand(dockerCli),
 		newSecretRemoveCommand(dockerCli),
+		newSecretRotateCommand(dockerCli),
 	)
 	return cmd
 }
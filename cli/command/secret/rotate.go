@@ -0,0 +1,120 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type rotateOptions struct {
+	secret string
+	file   string
+	name   string
+	dryRun bool
+	quiet  bool
+}
+
+func newSecretRotateCommand(dockerCli command.Cli) *cobra.Command {
+	options := rotateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rotate [OPTIONS] SECRET [file|-]",
+		Short: "Replace a secret in all the services that use it",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.secret = args[0]
+			if len(args) == 2 {
+				options.file = args[1]
+			}
+			return runSecretRotate(dockerCli, options)
+		},
+		Annotations: map[string]string{"version": "1.29"},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&options.name, "name", "", `Name of the new secret (default: the name of the secret with a ".v<version>" suffix)`)
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Only list the services that use the secret")
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress progress output")
+
+	return cmd
+}
+
+func runSecretRotate(dockerCli command.Cli, options rotateOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	secret, _, err := client.SecretInspectWithRaw(ctx, options.secret)
+	if err != nil {
+		return err
+	}
+	name := options.name
+	if name == "" {
+		name = service.NextVersion(secret.Spec.Name)
+	}
+
+	return service.Rotate(ctx, dockerCli, service.Rotation{
+		Kind:    "secret",
+		ID:      secret.ID,
+		Name:    secret.Spec.Name,
+		NewName: name,
+		DryRun:  options.dryRun,
+		Quiet:   options.quiet,
+		Uses: func(containerSpec *swarm.ContainerSpec) bool {
+			return usesSecret(containerSpec, secret.ID)
+		},
+		Create: func(ctx context.Context) (string, error) {
+			if secret.Spec.Driver == nil && options.file == "" {
+				return "", errors.Errorf("secret %s is not from a driver: its new content must be read from a file", secret.Spec.Name)
+			}
+			if secret.Spec.Driver != nil && options.file != "" {
+				return "", errors.Errorf("When using secret driver secret data must be empty")
+			}
+			data, err := readSecretData(dockerCli.In(), options.file)
+			if err != nil {
+				return "", errors.Errorf("Error reading content from %q: %v", options.file, err)
+			}
+
+			spec := secret.Spec
+			spec.Name = name
+			spec.Data = data
+			response, err := client.SecretCreate(ctx, spec)
+			return response.ID, err
+		},
+		Replace: func(containerSpec *swarm.ContainerSpec, newID string) {
+			containerSpec.Secrets = replaceSecret(containerSpec.Secrets, secret.ID, newID, name)
+		},
+		Remove: func(ctx context.Context) error {
+			return client.SecretRemove(ctx, secret.ID)
+		},
+	})
+}
+
+// usesSecret returns whether the container uses the secret
+func usesSecret(containerSpec *swarm.ContainerSpec, secretID string) bool {
+	for _, ref := range containerSpec.Secrets {
+		if ref.SecretID == secretID {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceSecret replaces the references to a secret with references to the
+// new secret, keeping their target files
+func replaceSecret(refs []*swarm.SecretReference, oldID, newID, newName string) []*swarm.SecretReference {
+	replaced := make([]*swarm.SecretReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref.SecretID == oldID {
+			newRef := *ref
+			newRef.SecretID = newID
+			newRef.SecretName = newName
+			ref = &newRef
+		}
+		replaced = append(replaced, ref)
+	}
+	return replaced
+}
@@ -0,0 +1,186 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func rotateServices() []swarm.Service {
+	return []swarm.Service{
+		{
+			ID:      "web-id",
+			Version: swarm.Version{Index: 3},
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "web"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Secrets: []*swarm.SecretReference{
+						{SecretID: "tls-id", SecretName: "tls", File: &swarm.SecretReferenceFileTarget{Name: "tls"}},
+						{SecretID: "db-id", SecretName: "db", File: &swarm.SecretReferenceFileTarget{Name: "db_password", UID: "1000", GID: "1001", Mode: 0400}},
+					},
+				}},
+			},
+		},
+		{
+			ID:   "cache-id",
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "cache"}, TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}}},
+		},
+		{
+			ID: "api-id",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "api"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Secrets: []*swarm.SecretReference{
+						{SecretID: "db-id", SecretName: "db", File: &swarm.SecretReferenceFileTarget{Name: "db"}},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func TestSecretRotate(t *testing.T) {
+	file := fs.NewFile(t, "secret", fs.WithContent("s3cr3t"))
+	defer file.Remove()
+
+	var (
+		created swarm.SecretSpec
+		updated = map[string]swarm.ServiceSpec{}
+		removed string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		secretInspectFunc: func(name string) (swarm.Secret, []byte, error) {
+			return swarm.Secret{
+				ID: "db-id",
+				Spec: swarm.SecretSpec{
+					Annotations: swarm.Annotations{Name: "db", Labels: map[string]string{"env": "prod"}},
+					Data:        []byte("password"),
+				},
+			}, nil, nil
+		},
+		secretCreateFunc: func(spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			created = spec
+			return types.SecretCreateResponse{ID: "db-v2-id"}, nil
+		},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return rotateServices(), nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec) (types.ServiceUpdateResponse, error) {
+			updated[serviceID] = spec
+			return types.ServiceUpdateResponse{}, nil
+		},
+		secretRemoveFunc: func(name string) error {
+			removed = name
+			return nil
+		},
+	})
+	cmd := newSecretRotateCommand(cli)
+	cmd.SetArgs([]string{"db", file.Path()})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal("db.v2", created.Name))
+	assert.Check(t, is.DeepEqual(map[string]string{"env": "prod"}, created.Labels))
+	assert.Check(t, is.Equal("s3cr3t", string(created.Data)))
+
+	assert.Check(t, is.Len(updated, 2))
+	assert.Check(t, is.DeepEqual([]*swarm.SecretReference{
+		{SecretID: "tls-id", SecretName: "tls", File: &swarm.SecretReferenceFileTarget{Name: "tls"}},
+		{SecretID: "db-v2-id", SecretName: "db.v2", File: &swarm.SecretReferenceFileTarget{Name: "db_password", UID: "1000", GID: "1001", Mode: 0400}},
+	}, updated["web-id"].TaskTemplate.ContainerSpec.Secrets))
+	assert.Check(t, is.DeepEqual([]*swarm.SecretReference{
+		{SecretID: "db-v2-id", SecretName: "db.v2", File: &swarm.SecretReferenceFileTarget{Name: "db"}},
+	}, updated["api-id"].TaskTemplate.ContainerSpec.Secrets))
+	assert.Check(t, is.Equal("db-id", removed))
+
+	expected := `Created secret db.v2
+Updating service api
+Updating service web
+Removed secret db
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestSecretRotateDryRun(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		secretInspectFunc: func(name string) (swarm.Secret, []byte, error) {
+			return swarm.Secret{ID: "db-id", Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "db.v4"}}}, nil, nil
+		},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return rotateServices(), nil
+		},
+		secretCreateFunc: func(spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			return types.SecretCreateResponse{}, errors.New("unexpected create")
+		},
+	})
+	cmd := newSecretRotateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "db.v4"})
+	assert.NilError(t, cmd.Execute())
+
+	expected := `Secret db.v4 would be replaced with db.v5 in services:
+  api
+  web
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestSecretRotateErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		driver        *swarm.Driver
+		expectedError string
+	}{
+		{
+			args:          []string{"db"},
+			expectedError: "secret db is not from a driver: its new content must be read from a file",
+		},
+		{
+			args:          []string{"db", "-"},
+			driver:        &swarm.Driver{Name: "vault"},
+			expectedError: "When using secret driver secret data must be empty",
+		},
+	}
+	for _, tc := range testCases {
+		driver := tc.driver
+		cmd := newSecretRotateCommand(
+			test.NewFakeCli(&fakeClient{
+				secretInspectFunc: func(name string) (swarm.Secret, []byte, error) {
+					return swarm.Secret{ID: "db-id", Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: name}, Driver: driver}}, nil, nil
+				},
+			}),
+		)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+	}
+}
+
+func TestSecretRotateUpdateError(t *testing.T) {
+	removed := false
+	cli := test.NewFakeCli(&fakeClient{
+		secretInspectFunc: func(name string) (swarm.Secret, []byte, error) {
+			return swarm.Secret{ID: "db-id", Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "db"}}}, nil, nil
+		},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return rotateServices(), nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec) (types.ServiceUpdateResponse, error) {
+			return types.ServiceUpdateResponse{}, errors.New("update out of sequence")
+		},
+		secretRemoveFunc: func(name string) error {
+			removed = true
+			return nil
+		},
+	})
+	cmd := newSecretRotateCommand(cli)
+	cmd.SetArgs([]string{"db", os.DevNull})
+	assert.Check(t, is.Error(cmd.Execute(), "failed to update service api: update out of sequence"))
+	assert.Check(t, !removed)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+const (
+	// canaryLabel is the label of a service in a canary update. It holds
+	// the update config to apply to the other tasks when the update is
+	// promoted.
+	canaryLabel = "com.docker.service.canary.update-config"
+
+	// canaryDelay is the delay between the updates of the tasks during a
+	// canary update. Each of the canary tasks is updated by its own
+	// worker, which then waits for the update to be promoted.
+	canaryDelay = 365 * 24 * time.Hour
+)
+
+// canaryPollInterval is the time between two checks of the tasks of a service
+// in a canary update
+var canaryPollInterval = 200 * time.Millisecond
+
+// parseCanary returns the number of tasks to update first out of the replicas
+// of a service, from a number of tasks or a percentage of the replicas
+func parseCanary(value string, replicas uint64) (uint64, error) {
+	invalid := errors.Errorf("invalid canary value %q: must be a number of tasks or a percentage of the replicas", value)
+	var tasks uint64
+	if strings.HasSuffix(value, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || percentage <= 0 || percentage > 100 {
+			return 0, invalid
+		}
+		tasks = uint64(math.Ceil(percentage * float64(replicas) / 100))
+		if tasks == 0 {
+			tasks = 1
+		}
+	} else {
+		var err error
+		tasks, err = strconv.ParseUint(value, 10, 64)
+		if err != nil || tasks == 0 {
+			return 0, invalid
+		}
+	}
+	if tasks > replicas {
+		return 0, errors.Errorf("cannot update %d tasks of a service with %d replicas", tasks, replicas)
+	}
+	return tasks, nil
+}
+
+// setCanaryUpdate sets the update config of the spec to only update the given
+// number of tasks, and saves the previous update config to be restored when
+// the update is promoted. It returns the number of tasks to update.
+func setCanaryUpdate(spec *swarm.ServiceSpec, value string) (uint64, error) {
+	if spec.Mode.Replicated == nil || spec.Mode.Replicated.Replicas == nil {
+		return 0, errors.Errorf("--%s is only supported for replicated services", flagCanary)
+	}
+	tasks, err := parseCanary(value, *spec.Mode.Replicated.Replicas)
+	if err != nil {
+		return 0, err
+	}
+
+	promoted := spec.UpdateConfig
+	if saved, ok := spec.Labels[canaryLabel]; ok {
+		// the service is already in a canary update, whose update config
+		// is the one to promote
+		promoted = nil
+		if err := json.Unmarshal([]byte(saved), &promoted); err != nil {
+			return 0, errors.Wrapf(err, "invalid %s label", canaryLabel)
+		}
+	}
+	saved, err := json.Marshal(promoted)
+	if err != nil {
+		return 0, err
+	}
+	if spec.Labels == nil {
+		spec.Labels = map[string]string{}
+	}
+	spec.Labels[canaryLabel] = string(saved)
+
+	canary := &swarm.UpdateConfig{
+		Parallelism:   tasks,
+		Delay:         canaryDelay,
+		FailureAction: swarm.UpdateFailureActionPause,
+	}
+	if promoted != nil {
+		canary.Monitor = promoted.Monitor
+		canary.MaxFailureRatio = promoted.MaxFailureRatio
+		canary.Order = promoted.Order
+	}
+	spec.UpdateConfig = canary
+	return tasks, nil
+}
+
+// canaryStatus is the status of the updated tasks of a service
+type canaryStatus struct {
+	running int
+	failed  int
+	errors  []string
+}
+
+func getCanaryStatus(ctx context.Context, apiClient client.APIClient, service swarm.Service) (canaryStatus, error) {
+	tasks, err := apiClient.TaskList(ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(filters.Arg("service", service.ID), filters.Arg("_up-to-date", "true")),
+	})
+	if err != nil {
+		return canaryStatus{}, err
+	}
+	status := canaryStatus{}
+	for _, task := range tasks {
+		switch task.Status.State {
+		case swarm.TaskStateRunning:
+			if task.DesiredState == swarm.TaskStateRunning {
+				status.running++
+			}
+		case swarm.TaskStateFailed, swarm.TaskStateRejected:
+			status.failed++
+			status.errors = append(status.errors, fmt.Sprintf("task %s.%d failed: %s", service.Spec.Name, task.Slot, task.Status.Err))
+		}
+	}
+	return status, nil
+}
+
+func (s canaryStatus) print(out io.Writer, tasks uint64) {
+	fmt.Fprintf(out, "canary: %d/%d tasks running, %d failed\n", s.running, tasks, s.failed)
+	for _, err := range s.errors {
+		fmt.Fprintln(out, err)
+	}
+}
+
+// waitOnCanary waits for the canary tasks of the service to be running, and
+// displays their status when it changes
+func waitOnCanary(ctx context.Context, dockerCli command.Cli, serviceID string, tasks uint64, quiet bool) error {
+	apiClient := dockerCli.Client()
+	var out io.Writer = dockerCli.Out()
+	if quiet {
+		out = ioutil.Discard
+	}
+
+	var last *canaryStatus
+	for {
+		service, _, err := apiClient.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+		if err != nil {
+			return err
+		}
+		status, err := getCanaryStatus(ctx, apiClient, service)
+		if err != nil {
+			return err
+		}
+		if last == nil || status.running != last.running || status.failed != last.failed {
+			status.print(out, tasks)
+			last = &status
+		}
+
+		if service.UpdateStatus != nil {
+			switch service.UpdateStatus.State {
+			case swarm.UpdateStatePaused:
+				return errors.Errorf("canary update of service %s paused: %s", serviceID, service.UpdateStatus.Message)
+			case swarm.UpdateStateRollbackStarted, swarm.UpdateStateRollbackPaused, swarm.UpdateStateRollbackCompleted:
+				return errors.Errorf("canary update of service %s rolled back: %s", serviceID, service.UpdateStatus.Message)
+			}
+		}
+		if uint64(status.running) >= tasks {
+			fmt.Fprintf(out, "canary tasks updated: run `docker service promote %s` to update the other tasks, or `docker service rollback %s` to roll back\n", serviceID, serviceID)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(canaryPollInterval):
+		}
+	}
+}
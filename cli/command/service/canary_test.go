@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParseCanary(t *testing.T) {
+	testCases := []struct {
+		value         string
+		replicas      uint64
+		expected      uint64
+		expectedError string
+	}{
+		{value: "2", replicas: 10, expected: 2},
+		{value: "10", replicas: 10, expected: 10},
+		{value: "25%", replicas: 10, expected: 3},
+		{value: "1%", replicas: 10, expected: 1},
+		{value: "100%", replicas: 4, expected: 4},
+		{value: "11", replicas: 10, expectedError: "cannot update 11 tasks of a service with 10 replicas"},
+		{value: "50%", replicas: 0, expectedError: "cannot update 1 tasks of a service with 0 replicas"},
+		{value: "0", replicas: 10, expectedError: `invalid canary value "0"`},
+		{value: "0%", replicas: 10, expectedError: `invalid canary value "0%"`},
+		{value: "150%", replicas: 10, expectedError: `invalid canary value "150%"`},
+		{value: "half", replicas: 10, expectedError: `invalid canary value "half"`},
+	}
+	for _, tc := range testCases {
+		tasks, err := parseCanary(tc.value, tc.replicas)
+		if tc.expectedError != "" {
+			assert.Check(t, is.ErrorContains(err, tc.expectedError), tc.value)
+			continue
+		}
+		assert.Check(t, err, tc.value)
+		assert.Check(t, is.Equal(tc.expected, tasks), tc.value)
+	}
+}
+
+func canaryService(labels map[string]string, updateConfig *swarm.UpdateConfig) swarm.Service {
+	replicas := uint64(4)
+	return swarm.Service{
+		ID: "service-id",
+		Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: "web", Labels: labels},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.16"},
+			},
+			Mode:         swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+			UpdateConfig: updateConfig,
+		},
+	}
+}
+
+func TestUpdateCanary(t *testing.T) {
+	updateConfig := &swarm.UpdateConfig{
+		Parallelism:   2,
+		Delay:         10 * time.Second,
+		FailureAction: swarm.UpdateFailureActionRollback,
+		Order:         swarm.UpdateOrderStartFirst,
+	}
+	var updated swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return canaryService(nil, updateConfig), nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated = service
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--canary", "25%", "--image", "nginx:1.17", "--detach", "service-id"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal("nginx:1.17", updated.TaskTemplate.ContainerSpec.Image))
+	assert.Check(t, is.DeepEqual(&swarm.UpdateConfig{
+		Parallelism:   1,
+		Delay:         canaryDelay,
+		FailureAction: swarm.UpdateFailureActionPause,
+		Order:         swarm.UpdateOrderStartFirst,
+	}, updated.UpdateConfig))
+	var saved *swarm.UpdateConfig
+	assert.NilError(t, json.Unmarshal([]byte(updated.Labels[canaryLabel]), &saved))
+	assert.Check(t, is.DeepEqual(updateConfig, saved))
+}
+
+func TestUpdateCanaryErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		labels        map[string]string
+		expectedError string
+	}{
+		{
+			name:          "no-task-changes",
+			args:          []string{"--canary", "1", "--replicas", "6", "service-id"},
+			expectedError: "--canary requires changes to the tasks of the service, or --force",
+		},
+		{
+			name:          "too-many-tasks",
+			args:          []string{"--canary", "5", "--force", "service-id"},
+			expectedError: "cannot update 5 tasks of a service with 4 replicas",
+		},
+		{
+			name:          "in-canary-update",
+			args:          []string{"--image", "nginx:1.17", "service-id"},
+			labels:        map[string]string{canaryLabel: "null"},
+			expectedError: "service service-id is in a canary update",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{
+			serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+				return canaryService(tc.labels, nil), nil, nil
+			},
+		})
+		cmd := newUpdateCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError), tc.name)
+	}
+}
+
+func TestWaitOnCanary(t *testing.T) {
+	defer func(interval time.Duration) { canaryPollInterval = interval }(canaryPollInterval)
+	canaryPollInterval = time.Millisecond
+
+	polls := 0
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return canaryService(nil, nil), nil, nil
+		},
+		taskListFunc: func(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+			assert.Check(t, options.Filters.ExactMatch("_up-to-date", "true"))
+			polls++
+			tasks := []swarm.Task{{
+				Slot:         1,
+				DesiredState: swarm.TaskStateRunning,
+				Status:       swarm.TaskStatus{State: swarm.TaskStateFailed, Err: "exit code 1"},
+			}}
+			for i := 1; i < polls && i <= 2; i++ {
+				tasks = append(tasks, swarm.Task{
+					Slot:         i + 1,
+					DesiredState: swarm.TaskStateRunning,
+					Status:       swarm.TaskStatus{State: swarm.TaskStateRunning},
+				})
+			}
+			return tasks, nil
+		},
+	})
+	assert.NilError(t, waitOnCanary(context.Background(), cli, "service-id", 2, false))
+	assert.Check(t, is.Equal(`canary: 0/2 tasks running, 1 failed
+task web.1 failed: exit code 1
+canary: 1/2 tasks running, 1 failed
+task web.1 failed: exit code 1
+canary: 2/2 tasks running, 1 failed
+task web.1 failed: exit code 1
+canary tasks updated: run `+"`docker service promote service-id`"+` to update the other tasks, or `+"`docker service rollback service-id`"+` to roll back
+`, cli.OutBuffer().String()))
+}
+
+func TestWaitOnCanaryPaused(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			service := canaryService(nil, nil)
+			service.UpdateStatus = &swarm.UpdateStatus{State: swarm.UpdateStatePaused, Message: "update paused due to failure"}
+			return service, nil, nil
+		},
+	})
+	err := waitOnCanary(context.Background(), cli, "service-id", 2, true)
+	assert.Check(t, is.Error(err, "canary update of service service-id paused: update paused due to failure"))
+	assert.Check(t, is.Equal("", cli.OutBuffer().String()))
+}
+
+func TestPromote(t *testing.T) {
+	updateConfig := &swarm.UpdateConfig{Parallelism: 2, Delay: 10 * time.Second}
+	saved, err := json.Marshal(updateConfig)
+	assert.NilError(t, err)
+
+	var updated swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			labels := map[string]string{canaryLabel: string(saved), "com.example.tier": "web"}
+			return canaryService(labels, &swarm.UpdateConfig{Parallelism: 1, Delay: canaryDelay}), nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated = service
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newPromoteCommand(cli)
+	cmd.SetArgs([]string{"--detach", "service-id"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(updateConfig, updated.UpdateConfig))
+	assert.Check(t, is.DeepEqual(map[string]string{"com.example.tier": "web"}, updated.Labels))
+	assert.Check(t, is.Equal("canary: 0/1 tasks running, 0 failed\nservice-id\n", cli.OutBuffer().String()))
+}
+
+func TestPromoteWithoutUpdateConfig(t *testing.T) {
+	saved, err := json.Marshal(&swarm.UpdateConfig{Parallelism: 2})
+	assert.NilError(t, err)
+
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return canaryService(map[string]string{canaryLabel: string(saved)}, nil), nil, nil
+		},
+	})
+	cmd := newPromoteCommand(cli)
+	cmd.SetArgs([]string{"--detach", "service-id"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("service-id\n", cli.OutBuffer().String()))
+}
+
+func TestPromoteWithoutCanary(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return canaryService(nil, nil), nil, nil
+		},
+	})
+	cmd := newPromoteCommand(cli)
+	cmd.SetArgs([]string{"service-id"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "service service-id is not in a canary update"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/docker/docker/api/types"
+	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
 	// Import builders to get the builder function as package function
@@ -14,13 +15,26 @@ type fakeClient struct {
 	client.Client
 	serviceInspectWithRawFunc func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error)
 	serviceUpdateFunc         func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error)
+	serviceCreateFunc         func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error)
 	serviceListFunc           func(context.Context, types.ServiceListOptions) ([]swarm.Service, error)
 	taskListFunc              func(context.Context, types.TaskListOptions) ([]swarm.Task, error)
 	infoFunc                  func(ctx context.Context) (types.Info, error)
 	networkInspectFunc        func(ctx context.Context, networkID string, options types.NetworkInspectOptions) (types.NetworkResource, error)
+	nodeListFunc              func(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error)
+	distributionInspectFunc   func(ctx context.Context, image, encodedRegistryAuth string) (registrytypes.DistributionInspect, error)
+}
+
+func (f *fakeClient) DistributionInspect(ctx context.Context, image, encodedRegistryAuth string) (registrytypes.DistributionInspect, error) {
+	if f.distributionInspectFunc != nil {
+		return f.distributionInspectFunc(ctx, image, encodedRegistryAuth)
+	}
+	return registrytypes.DistributionInspect{}, nil
 }
 
 func (f *fakeClient) NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+	if f.nodeListFunc != nil {
+		return f.nodeListFunc(ctx, options)
+	}
 	return nil, nil
 }
 
@@ -55,6 +69,14 @@ func (f *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, versio
 	return types.ServiceUpdateResponse{}, nil
 }
 
+func (f *fakeClient) ServiceCreate(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+	if f.serviceCreateFunc != nil {
+		return f.serviceCreateFunc(ctx, service, options)
+	}
+
+	return types.ServiceCreateResponse{}, nil
+}
+
 func (f *fakeClient) Info(ctx context.Context) (types.Info, error) {
 	if f.infoFunc == nil {
 		return types.Info{}, nil
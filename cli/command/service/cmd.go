@@ -21,9 +21,11 @@ func NewServiceCommand(dockerCli command.Cli) *cobra.Command {
 	}
 	cmd.AddCommand(
 		newCreateCommand(dockerCli),
+		newDiffCommand(dockerCli),
 		newInspectCommand(dockerCli),
 		newPsCommand(dockerCli),
 		newListCommand(dockerCli),
+		newPromoteCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 		newScaleCommand(dockerCli),
 		newUpdateCommand(dockerCli),
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -11,6 +12,7 @@ import (
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -21,9 +23,17 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create a new service",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			// the image can be set in the spec file
+			if opts.specFile != "" {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.image = args[0]
+			if len(args) > 0 {
+				opts.image = args[0]
+			}
 			if len(args) > 1 {
 				opts.args = args[1:]
 			}
@@ -78,7 +88,19 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 
 	ctx := context.Background()
 
-	service, err := opts.ToService(ctx, apiClient, flags)
+	if err := ValidateDryRun(opts.dryRun); err != nil {
+		return err
+	}
+
+	var (
+		service swarm.ServiceSpec
+		err     error
+	)
+	if opts.specFile != "" {
+		service, err = specFromFile(ctx, dockerCli, flags, opts)
+	} else {
+		service, err = opts.ToService(ctx, apiClient, flags)
+	}
 	if err != nil {
 		return err
 	}
@@ -101,6 +123,13 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 		return err
 	}
 
+	if opts.dryRun == DryRunPlacement {
+		if !opts.noResolveImage && versions.GreaterThanOrEqualTo(apiClient.ClientVersion(), "1.30") {
+			ResolvePlatforms(ctx, dockerCli, &service, opts.registryAuth)
+		}
+		return runPlacementDryRun(ctx, dockerCli, service)
+	}
+
 	if err := resolveServiceImageDigestContentTrust(dockerCli, &service); err != nil {
 		return err
 	}
@@ -108,7 +137,7 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 	// only send auth if flag was set
 	if opts.registryAuth {
 		// Retrieve encoded auth token from the image reference
-		encodedAuth, err := command.RetrieveAuthTokenFromImage(ctx, dockerCli, opts.image)
+		encodedAuth, err := command.RetrieveAuthTokenFromImage(ctx, dockerCli, service.TaskTemplate.ContainerSpec.Image)
 		if err != nil {
 			return err
 		}
@@ -135,7 +164,42 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 		return nil
 	}
 
-	return waitOnService(ctx, dockerCli, response.ID, opts.quiet)
+	return WaitOnService(ctx, dockerCli, response.ID, opts.quiet)
+}
+
+// specFromFile reads the spec of a service from the spec file, and overrides
+// its name, image and args with the ones passed on the command line. The other
+// flags that override the spec are the flags of `docker service update`.
+func specFromFile(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions) (swarm.ServiceSpec, error) {
+	updateFlags := pflag.NewFlagSet("update", pflag.ContinueOnError)
+	addServiceFlags(updateFlags, newServiceOptions(), nil)
+	var unsupported []string
+	flags.Visit(func(f *pflag.Flag) {
+		if f.Name != flagName && updateFlags.Lookup(f.Name) == nil {
+			unsupported = append(unsupported, "--"+f.Name)
+		}
+	})
+	if len(unsupported) > 0 {
+		return swarm.ServiceSpec{}, errors.Errorf("%s cannot be used with --%s: set the fields in the spec file instead", strings.Join(unsupported, ", "), flagSpecFile)
+	}
+
+	spec, err := loadSpecFile(dockerCli, opts.specFile)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	if opts.name != "" {
+		spec.Name = opts.name
+	}
+	if opts.image != "" {
+		spec.TaskTemplate.ContainerSpec.Image = opts.image
+	}
+	if len(opts.args) > 0 {
+		spec.TaskTemplate.ContainerSpec.Args = opts.args
+	}
+	if err := updateService(ctx, dockerCli.Client(), flags, &spec); err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	return spec, nil
 }
 
 // setConfigs does double duty: it both sets the ConfigReferences of the
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	service string
+	file    string
+	format  string
+}
+
+func newDiffCommand(dockerCli command.Cli) *cobra.Command {
+	var opts diffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] SERVICE",
+		Short: "Show the changes of the last update of a service",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.service = args[0]
+			return runDiff(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.file, "file", "", "Compare the service with a service spec in a JSON or YAML file")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print the changes using a Go template")
+
+	return cmd
+}
+
+func runDiff(dockerCli command.Cli, opts diffOptions) error {
+	ctx := context.Background()
+
+	service, _, err := dockerCli.Client().ServiceInspectWithRaw(ctx, opts.service, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	var from, to swarm.ServiceSpec
+	if opts.file != "" {
+		from = service.Spec
+		if to, err = loadSpecFile(dockerCli, opts.file); err != nil {
+			return err
+		}
+	} else {
+		if service.PreviousSpec == nil {
+			return errors.Errorf("service %s has not been updated", opts.service)
+		}
+		from, to = *service.PreviousSpec, service.Spec
+	}
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	diffCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: NewDiffFormat(format, false),
+	}
+	return DiffFormatWrite(diffCtx, DiffSpecs(from, to))
+}
+
+// SpecChange is a change of a field of a service spec. The old or new value of
+// a field that is not set is empty, and so are the old values of the items
+// added to lists, and the new values of the items removed from lists.
+type SpecChange struct {
+	// Service is the name of the service, when comparing several services
+	Service string
+	Field   string
+	Old     string
+	New     string
+}
+
+// DiffSpecs returns the changes of the fields of a service spec, in the order
+// of the fields of the spec
+func DiffSpecs(from, to swarm.ServiceSpec) []SpecChange {
+	changes := []SpecChange{}
+	diffValues("", reflect.ValueOf(from), reflect.ValueOf(to), &changes)
+	return changes
+}
+
+// diffValues compares structs field by field, maps key by key, and lists item
+// by item. A nil pointer is compared as the zero value of its type, so that
+// unset and empty fields are equal.
+func diffValues(field string, from, to reflect.Value, changes *[]SpecChange) {
+	from, to = indirect(from), indirect(to)
+	switch {
+	case !from.IsValid() && !to.IsValid():
+		return
+	case !from.IsValid():
+		from = reflect.Zero(to.Type())
+	case !to.IsValid():
+		to = reflect.Zero(from.Type())
+	}
+
+	switch from.Kind() {
+	case reflect.Struct:
+		t := from.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			switch {
+			case f.PkgPath != "":
+				continue
+			case f.Anonymous:
+				diffValues(field, from.Field(i), to.Field(i), changes)
+			default:
+				diffValues(joinField(field, f.Name), from.Field(i), to.Field(i), changes)
+			}
+		}
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, k := range append(from.MapKeys(), to.MapKeys()...) {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			diffValues(field+"["+name+"]", from.MapIndex(keys[name]), to.MapIndex(keys[name]), changes)
+		}
+	case reflect.Slice:
+		diffLists(field, from, to, changes)
+	default:
+		if !reflect.DeepEqual(from.Interface(), to.Interface()) {
+			*changes = append(*changes, SpecChange{Field: field, Old: formatValue(from), New: formatValue(to)})
+		}
+	}
+}
+
+// diffLists reports the items removed from a list and the items added to it.
+// A list whose items are only reordered is reported as a whole.
+func diffLists(field string, from, to reflect.Value, changes *[]SpecChange) {
+	fromItems, toItems := formatItems(from), formatItems(to)
+
+	added := map[string]int{}
+	for _, item := range toItems {
+		added[item]++
+	}
+	var removed []string
+	for _, item := range fromItems {
+		if added[item] > 0 {
+			added[item]--
+			continue
+		}
+		removed = append(removed, item)
+	}
+	for _, item := range removed {
+		*changes = append(*changes, SpecChange{Field: field, Old: item})
+	}
+	reordered := len(removed) == 0 && !reflect.DeepEqual(fromItems, toItems)
+	for _, item := range toItems {
+		if added[item] > 0 {
+			added[item]--
+			*changes = append(*changes, SpecChange{Field: field, New: item})
+			reordered = false
+		}
+	}
+	if reordered {
+		*changes = append(*changes, SpecChange{Field: field, Old: formatValue(from), New: formatValue(to)})
+	}
+}
+
+func formatItems(list reflect.Value) []string {
+	items := []string{}
+	for i := 0; i < list.Len(); i++ {
+		items = append(items, formatValue(list.Index(i)))
+	}
+	return items
+}
+
+// formatValue formats scalars as text, and other values as JSON
+func formatValue(v reflect.Value) string {
+	v = indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(data)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// indirect dereferences pointers and interfaces, and returns an invalid value
+// for nil ones
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func joinField(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+	"gotest.tools/golden"
+)
+
+func diffSpec() swarm.ServiceSpec {
+	replicas := uint64(2)
+	return swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web", Labels: map[string]string{"com.example.tier": "front"}},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image: "nginx:1.16",
+				Env:   []string{"LEVEL=info", "PORT=80"},
+				Mounts: []mount.Mount{
+					{Type: mount.TypeVolume, Source: "data", Target: "/data"},
+				},
+			},
+			Placement: &swarm.Placement{Constraints: []string{"node.role==worker"}},
+		},
+		Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+	}
+}
+
+func updatedDiffSpec() swarm.ServiceSpec {
+	spec := diffSpec()
+	spec.Labels = map[string]string{"com.example.tier": "back", "com.example.team": "web"}
+	spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{
+		Image: "nginx:1.17",
+		Env:   []string{"LEVEL=debug", "PORT=80"},
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Source: "data", Target: "/data"},
+			{Type: mount.TypeBind, Source: "/var/log", Target: "/var/log/nginx"},
+		},
+	}
+	spec.TaskTemplate.Resources = &swarm.ResourceRequirements{
+		Limits: &swarm.Resources{MemoryBytes: 64 * 1024 * 1024},
+	}
+	spec.UpdateConfig = &swarm.UpdateConfig{Delay: 10 * time.Second}
+	return spec
+}
+
+func TestDiffSpecs(t *testing.T) {
+	changes := DiffSpecs(diffSpec(), updatedDiffSpec())
+	assert.Check(t, is.DeepEqual([]SpecChange{
+		{Field: "Labels[com.example.team]", New: "web"},
+		{Field: "Labels[com.example.tier]", Old: "front", New: "back"},
+		{Field: "TaskTemplate.ContainerSpec.Image", Old: "nginx:1.16", New: "nginx:1.17"},
+		{Field: "TaskTemplate.ContainerSpec.Env", Old: "LEVEL=info"},
+		{Field: "TaskTemplate.ContainerSpec.Env", New: "LEVEL=debug"},
+		{Field: "TaskTemplate.ContainerSpec.Mounts", New: `{"Type":"bind","Source":"/var/log","Target":"/var/log/nginx"}`},
+		{Field: "TaskTemplate.Resources.Limits.MemoryBytes", Old: "0", New: "67108864"},
+		{Field: "UpdateConfig.Delay", Old: "0s", New: "10s"},
+	}, changes))
+}
+
+func TestDiffSpecsUnchanged(t *testing.T) {
+	spec := diffSpec()
+	// unset and empty fields are equal
+	spec.TaskTemplate.Resources = &swarm.ResourceRequirements{}
+	spec.Labels["com.example.tier"] = "front"
+	assert.Check(t, is.Len(DiffSpecs(diffSpec(), spec), 0))
+
+	spec.TaskTemplate.ContainerSpec.Env = []string{"PORT=80", "LEVEL=info"}
+	assert.Check(t, is.DeepEqual([]SpecChange{{
+		Field: "TaskTemplate.ContainerSpec.Env",
+		Old:   `["LEVEL=info","PORT=80"]`,
+		New:   `["PORT=80","LEVEL=info"]`,
+	}}, DiffSpecs(diffSpec(), spec)))
+}
+
+func TestDiffPreviousSpec(t *testing.T) {
+	previous := diffSpec()
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "service-id", Spec: updatedDiffSpec(), PreviousSpec: &previous}, nil, nil
+		},
+	})
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "service-diff.golden")
+}
+
+func TestDiffFile(t *testing.T) {
+	file := fs.NewFile(t, "spec", fs.WithContent(`{"Name": "web", "Labels": {"com.example.tier": "front"}, "TaskTemplate": {"ContainerSpec": {"Image": "nginx:1.18", "Env": ["LEVEL=info", "PORT=80"]}}}`))
+	defer file.Remove()
+
+	spec := diffSpec()
+	spec.TaskTemplate.ContainerSpec.Mounts = nil
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "service-id", Spec: spec}, nil, nil
+		},
+	})
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"--file", file.Path(), "--format", "{{json .}}", "web"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`{"Field":"TaskTemplate.ContainerSpec.Image","New":"nginx:1.18","Old":"nginx:1.16","Service":""}
+{"Field":"TaskTemplate.Placement.Constraints","New":"","Old":"node.role==worker","Service":""}
+{"Field":"Mode.Replicated.Replicas","New":"0","Old":"2","Service":""}
+`, cli.OutBuffer().String()))
+}
+
+func TestDiffNotUpdated(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "service web has not been updated"))
+}
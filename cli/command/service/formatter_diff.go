@@ -0,0 +1,73 @@
+package service
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultDiffTableFormat         = "table {{.Field}}\t{{.Old}}\t{{.New}}"
+	defaultServicesDiffTableFormat = "table {{.Service}}\t{{.Field}}\t{{.Old}}\t{{.New}}"
+
+	diffServiceHeader = "SERVICE"
+	diffFieldHeader   = "FIELD"
+	diffOldHeader     = "OLD"
+	diffNewHeader     = "NEW"
+)
+
+// NewDiffFormat returns a Format for rendering the changes of service specs,
+// with the names of the services when comparing several services
+func NewDiffFormat(source string, services bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if services {
+			return defaultServicesDiffTableFormat
+		}
+		return defaultDiffTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// DiffFormatWrite writes a row for each change of a service spec
+func DiffFormatWrite(ctx formatter.Context, changes []SpecChange) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, change := range changes {
+			if err := format(&diffContext{c: change}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	diffCtx := &diffContext{}
+	diffCtx.Header = formatter.SubHeaderContext{
+		"Service": diffServiceHeader,
+		"Field":   diffFieldHeader,
+		"Old":     diffOldHeader,
+		"New":     diffNewHeader,
+	}
+	return ctx.Write(diffCtx, render)
+}
+
+type diffContext struct {
+	formatter.HeaderContext
+	c SpecChange
+}
+
+func (c *diffContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *diffContext) Service() string {
+	return c.c.Service
+}
+
+func (c *diffContext) Field() string {
+	return c.c.Field
+}
+
+func (c *diffContext) Old() string {
+	return c.c.Old
+}
+
+func (c *diffContext) New() string {
+	return c.c.New
+}
@@ -530,6 +530,9 @@ type serviceOptions struct {
 	configs     opts.ConfigOpt
 
 	isolation string
+
+	specFile string
+	dryRun   string
 }
 
 func newServiceOptions() *serviceOptions {
@@ -834,9 +837,13 @@ func addServiceFlags(flags *pflag.FlagSet, opts *serviceOptions, defaultFlagValu
 	flags.SetAnnotation(flagStopSignal, "version", []string{"1.28"})
 	flags.StringVar(&opts.isolation, flagIsolation, "", "Service container isolation mode")
 	flags.SetAnnotation(flagIsolation, "version", []string{"1.35"})
+
+	flags.StringVar(&opts.specFile, flagSpecFile, "", `Read the service spec from a JSON or YAML file, as output by "docker service inspect"`)
+	flags.StringVar(&opts.dryRun, flagDryRun, "", `Only simulate the deployment of the service ("`+DryRunPlacement+`")`)
 }
 
 const (
+	flagCanary                  = "canary"
 	flagCredentialSpec          = "credential-spec"
 	flagPlacementPref           = "placement-pref"
 	flagPlacementPrefAdd        = "placement-pref-add"
@@ -857,6 +864,7 @@ const (
 	flagDNSSearch               = "dns-search"
 	flagDNSSearchRemove         = "dns-search-rm"
 	flagDNSSearchAdd            = "dns-search-add"
+	flagDryRun                  = "dry-run"
 	flagEndpointMode            = "endpoint-mode"
 	flagEntrypoint              = "entrypoint"
 	flagEnv                     = "env"
@@ -909,6 +917,7 @@ const (
 	flagSysCtl                  = "sysctl"
 	flagSysCtlAdd               = "sysctl-add"
 	flagSysCtlRemove            = "sysctl-rm"
+	flagSpecFile                = "spec-file"
 	flagStopGracePeriod         = "stop-grace-period"
 	flagStopSignal              = "stop-signal"
 	flagTTY                     = "tty"
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// DryRunPlacement is the value of --dry-run to simulate the placement of the
+// tasks of a service
+const DryRunPlacement = "placement"
+
+const (
+	nodeLabelPrefix   = "node.labels."
+	engineLabelPrefix = "engine.labels."
+)
+
+// constraintPattern matches a placement constraint, such as
+// "node.labels.zone==east", like the scheduler parses it
+var constraintPattern = regexp.MustCompile(`^\s*([\w.-]+)\s*(==|!=)\s*(.+?)\s*$`)
+
+// ValidateDryRun validates the value of --dry-run
+func ValidateDryRun(dryRun string) error {
+	if dryRun != "" && dryRun != DryRunPlacement {
+		return errors.Errorf("invalid --dry-run value %q: only %q is supported", dryRun, DryRunPlacement)
+	}
+	return nil
+}
+
+// NodePlacement is the simulated placement of the tasks of a service on a node
+type NodePlacement struct {
+	Node swarm.Node
+	// Tasks is the number of tasks of the service placed on the node
+	Tasks uint64
+	// Reasons are why the node is not eligible for the tasks of the service,
+	// or empty if it is eligible
+	Reasons []string
+}
+
+// PlacementReport is the simulated placement of the tasks of a service on the
+// nodes of the swarm
+type PlacementReport struct {
+	// Tasks is the number of tasks of the service
+	Tasks uint64
+	// Unplaced is the number of tasks that no eligible node can run
+	Unplaced uint64
+	Nodes    []NodePlacement
+}
+
+// SimulatePlacement evaluates the placement constraints, platforms and
+// resource reservations of a service against the nodes of the swarm, and
+// distributes the tasks of the service over the eligible nodes, following
+// its spread preferences.
+//
+// The simulation does not know about the other tasks running on the nodes:
+// the resource reservations are compared with the resources of the nodes, and
+// the tasks are placed as if the service had no task yet.
+func SimulatePlacement(spec swarm.ServiceSpec, nodes []swarm.Node) (PlacementReport, error) {
+	placement := spec.TaskTemplate.Placement
+	if placement == nil {
+		placement = &swarm.Placement{}
+	}
+	constraints, err := parseConstraints(placement.Constraints)
+	if err != nil {
+		return PlacementReport{}, err
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Description.Hostname != nodes[j].Description.Hostname {
+			return nodes[i].Description.Hostname < nodes[j].Description.Hostname
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+	report := PlacementReport{Nodes: make([]NodePlacement, 0, len(nodes))}
+	var eligible []*NodePlacement
+	for _, node := range nodes {
+		report.Nodes = append(report.Nodes, NodePlacement{
+			Node:    node,
+			Reasons: excludedBy(spec.TaskTemplate, placement, constraints, node),
+		})
+	}
+	for i := range report.Nodes {
+		if len(report.Nodes[i].Reasons) == 0 {
+			eligible = append(eligible, &report.Nodes[i])
+		}
+	}
+
+	switch {
+	case spec.Mode.Global != nil:
+		// a task on each eligible node
+		for _, node := range eligible {
+			node.Tasks = 1
+		}
+		report.Tasks = uint64(len(eligible))
+	case spec.Mode.Replicated != nil && spec.Mode.Replicated.Replicas != nil:
+		report.Tasks = *spec.Mode.Replicated.Replicas
+		var spreads []string
+		for _, preference := range placement.Preferences {
+			if preference.Spread != nil {
+				spreads = append(spreads, preference.Spread.SpreadDescriptor)
+			}
+		}
+		for i := uint64(0); i < report.Tasks; i++ {
+			if !placeTask(eligible, spreads, placement.MaxReplicas) {
+				report.Unplaced = report.Tasks - i
+				break
+			}
+		}
+	}
+	return report, nil
+}
+
+type constraint struct {
+	expression string
+	key        string
+	equal      bool
+	value      string
+}
+
+func parseConstraints(expressions []string) ([]constraint, error) {
+	constraints := make([]constraint, 0, len(expressions))
+	for _, expression := range expressions {
+		m := constraintPattern.FindStringSubmatch(expression)
+		if m == nil {
+			return nil, errors.Errorf("invalid constraint %q: the operator must be == or !=", expression)
+		}
+		constraints = append(constraints, constraint{
+			expression: expression,
+			key:        m[1],
+			equal:      m[2] == "==",
+			value:      m[3],
+		})
+	}
+	return constraints, nil
+}
+
+// nodeValue returns the value of the attribute of a node that a constraint or
+// a spread preference refers to, and whether the attribute is known. Like the
+// scheduler, the attributes are matched case-insensitively, but not the names
+// of the labels.
+func nodeValue(node swarm.Node, key string) (string, bool) {
+	switch lowerKey := strings.ToLower(key); {
+	case lowerKey == "node.id":
+		return node.ID, true
+	case lowerKey == "node.hostname":
+		return node.Description.Hostname, true
+	case lowerKey == "node.role":
+		return string(node.Spec.Role), true
+	case lowerKey == "node.platform.os":
+		return node.Description.Platform.OS, true
+	case lowerKey == "node.platform.arch":
+		return node.Description.Platform.Architecture, true
+	case strings.HasPrefix(lowerKey, nodeLabelPrefix):
+		return node.Spec.Labels[key[len(nodeLabelPrefix):]], true
+	case strings.HasPrefix(lowerKey, engineLabelPrefix):
+		return node.Description.Engine.Labels[key[len(engineLabelPrefix):]], true
+	}
+	return "", false
+}
+
+// excludedBy returns why a node is not eligible for the tasks of a service, or
+// nil if it is eligible
+func excludedBy(taskTemplate swarm.TaskSpec, placement *swarm.Placement, constraints []constraint, node swarm.Node) []string {
+	var reasons []string
+	if node.Status.State != swarm.NodeStateReady {
+		reasons = append(reasons, fmt.Sprintf("node is %s", node.Status.State))
+	}
+	if node.Spec.Availability != swarm.NodeAvailabilityActive {
+		reasons = append(reasons, fmt.Sprintf("availability is %s", node.Spec.Availability))
+	}
+
+	for _, c := range constraints {
+		value, ok := nodeValue(node, c.key)
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("constraint %s: unknown attribute %s", c.expression, c.key))
+			continue
+		}
+		if strings.EqualFold(value, c.value) != c.equal {
+			reasons = append(reasons, fmt.Sprintf("constraint %s: %s is %q", c.expression, c.key, value))
+		}
+	}
+
+	if len(placement.Platforms) > 0 && !platformMatches(placement.Platforms, node.Description.Platform) {
+		platform := node.Description.Platform
+		reasons = append(reasons, fmt.Sprintf("platform %s/%s is not supported by the image", platform.OS, platform.Architecture))
+	}
+
+	if resources := taskTemplate.Resources; resources != nil && resources.Reservations != nil {
+		reserved, available := resources.Reservations, node.Description.Resources
+		if reserved.NanoCPUs > available.NanoCPUs {
+			reasons = append(reasons, fmt.Sprintf("reserves %g CPUs, node has %g", float64(reserved.NanoCPUs)/1e9, float64(available.NanoCPUs)/1e9))
+		}
+		if reserved.MemoryBytes > available.MemoryBytes {
+			reasons = append(reasons, fmt.Sprintf("reserves %s of memory, node has %s", units.BytesSize(float64(reserved.MemoryBytes)), units.BytesSize(float64(available.MemoryBytes))))
+		}
+	}
+	return reasons
+}
+
+// platformMatches returns whether a node has one of the platforms of an
+// image, normalizing the architectures like the scheduler
+func platformMatches(platforms []swarm.Platform, nodePlatform swarm.Platform) bool {
+	nodeArch := normalizeArch(nodePlatform.Architecture)
+	for _, platform := range platforms {
+		if platform.OS != "" && !strings.EqualFold(platform.OS, nodePlatform.OS) {
+			continue
+		}
+		if platform.Architecture != "" && normalizeArch(platform.Architecture) != nodeArch {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func normalizeArch(arch string) string {
+	switch strings.ToLower(arch) {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	}
+	return strings.ToLower(arch)
+}
+
+// placeTask places a task on one of the nodes. The task goes to the group of
+// nodes, by the value of the first spread descriptor, with the fewest tasks,
+// and so on for the other descriptors, and then to the node of the group with
+// the fewest tasks. It returns false if no node can run one more task.
+func placeTask(nodes []*NodePlacement, spreads []string, maxReplicas uint64) bool {
+	if len(spreads) == 0 {
+		var target *NodePlacement
+		for _, node := range nodes {
+			if maxReplicas > 0 && node.Tasks >= maxReplicas {
+				continue
+			}
+			if target == nil || node.Tasks < target.Tasks {
+				target = node
+			}
+		}
+		if target == nil {
+			return false
+		}
+		target.Tasks++
+		return true
+	}
+
+	// the nodes without the label form their own group
+	groups := map[string][]*NodePlacement{}
+	tasks := map[string]uint64{}
+	var values []string
+	for _, node := range nodes {
+		value, _ := nodeValue(node.Node, spreads[0])
+		if _, ok := groups[value]; !ok {
+			values = append(values, value)
+		}
+		groups[value] = append(groups[value], node)
+		tasks[value] += node.Tasks
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		if tasks[values[i]] != tasks[values[j]] {
+			return tasks[values[i]] < tasks[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	for _, value := range values {
+		if placeTask(groups[value], spreads[1:], maxReplicas) {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintPlacement prints the nodes of a simulated placement, with the number
+// of tasks placed on them, or why they are not eligible
+func PrintPlacement(out io.Writer, report PlacementReport) {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tHOSTNAME\tROLE\tTASKS\tEXCLUDED BY")
+	for _, node := range report.Nodes {
+		tasks := "-"
+		if len(node.Reasons) == 0 {
+			tasks = fmt.Sprint(node.Tasks)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", node.Node.ID, node.Node.Description.Hostname, node.Node.Spec.Role, tasks, strings.Join(node.Reasons, ", "))
+	}
+	w.Flush()
+	if report.Unplaced > 0 {
+		fmt.Fprintf(out, "%d of %d tasks cannot be placed on the eligible nodes\n", report.Unplaced, report.Tasks)
+	}
+}
+
+// runPlacementDryRun simulates the placement of the tasks of a service on the
+// nodes of the swarm, and prints it
+func runPlacementDryRun(ctx context.Context, dockerCli command.Cli, spec swarm.ServiceSpec) error {
+	nodes, err := dockerCli.Client().NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+	report, err := SimulatePlacement(spec, nodes)
+	if err != nil {
+		return err
+	}
+	PrintPlacement(dockerCli.Out(), report)
+	return nil
+}
+
+// ResolvePlatforms sets the platforms of the image of a service, as the daemon
+// does when it queries the registry on create and update, so that the
+// simulated placement checks them. The platforms are not checked if the image
+// cannot be resolved, and a warning is printed.
+func ResolvePlatforms(ctx context.Context, dockerCli command.Cli, spec *swarm.ServiceSpec, sendAuth bool) {
+	image := spec.TaskTemplate.ContainerSpec.Image
+	warn := func(err error) {
+		fmt.Fprintf(dockerCli.Err(), "WARNING: cannot resolve image %s, its platforms are not checked: %v\n", image, err)
+	}
+
+	var encodedAuth string
+	if sendAuth {
+		var err error
+		if encodedAuth, err = command.RetrieveAuthTokenFromImage(ctx, dockerCli, image); err != nil {
+			warn(err)
+			return
+		}
+	}
+	distribution, err := dockerCli.Client().DistributionInspect(ctx, image, encodedAuth)
+	if err != nil {
+		warn(err)
+		return
+	}
+
+	platforms := make([]swarm.Platform, 0, len(distribution.Platforms))
+	for _, platform := range distribution.Platforms {
+		platforms = append(platforms, swarm.Platform{OS: platform.OS, Architecture: platform.Architecture})
+	}
+	if spec.TaskTemplate.Placement == nil {
+		spec.TaskTemplate.Placement = &swarm.Placement{}
+	}
+	placement := *spec.TaskTemplate.Placement
+	placement.Platforms = platforms
+	spec.TaskTemplate.Placement = &placement
+}
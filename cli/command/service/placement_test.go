@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	. "github.com/docker/cli/internal/test/builders" // Import builders to get the builder function as package function
+	"github.com/docker/docker/api/types"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/swarm"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func placementNodes() []swarm.Node {
+	return []swarm.Node{
+		*Node(NodeID("m1"), Hostname("manager-1"), Manager(), NodeLabels(map[string]string{"zone": "east"})),
+		*Node(NodeID("w1"), Hostname("worker-1"), NodeLabels(map[string]string{"zone": "east"})),
+		*Node(NodeID("w2"), Hostname("worker-2"), NodeLabels(map[string]string{"zone": "west"})),
+		*Node(NodeID("w3"), Hostname("worker-3")),
+		*Node(NodeID("w4"), Hostname("worker-4"), func(node *swarm.Node) {
+			node.Spec.Availability = swarm.NodeAvailabilityDrain
+		}),
+		*Node(NodeID("w5"), Hostname("worker-5"), func(node *swarm.Node) {
+			node.Status.State = swarm.NodeStateDown
+		}),
+	}
+}
+
+func replicatedSpec(replicas uint64, placement *swarm.Placement) swarm.ServiceSpec {
+	return swarm.ServiceSpec{
+		Mode:         swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}, Placement: placement},
+	}
+}
+
+// placedTasks returns the number of tasks placed on each node, by ID, or -1
+// for the nodes that are not eligible
+func placedTasks(report PlacementReport) map[string]int {
+	tasks := map[string]int{}
+	for _, node := range report.Nodes {
+		if len(node.Reasons) > 0 {
+			tasks[node.Node.ID] = -1
+			continue
+		}
+		tasks[node.Node.ID] = int(node.Tasks)
+	}
+	return tasks
+}
+
+func TestSimulatePlacementSpread(t *testing.T) {
+	spec := replicatedSpec(5, &swarm.Placement{
+		Constraints: []string{"node.role==worker"},
+		Preferences: []swarm.PlacementPreference{{Spread: &swarm.SpreadOver{SpreadDescriptor: "node.labels.zone"}}},
+	})
+	report, err := SimulatePlacement(spec, placementNodes())
+	assert.NilError(t, err)
+
+	assert.Check(t, is.DeepEqual(map[string]int{"m1": -1, "w1": 2, "w2": 1, "w3": 2, "w4": -1, "w5": -1}, placedTasks(report)))
+	assert.Check(t, is.Equal(uint64(0), report.Unplaced))
+	assert.Check(t, is.DeepEqual([]string{`constraint node.role==worker: node.role is "manager"`}, report.Nodes[0].Reasons))
+	assert.Check(t, is.DeepEqual([]string{"availability is drain"}, report.Nodes[4].Reasons))
+	assert.Check(t, is.DeepEqual([]string{"node is down"}, report.Nodes[5].Reasons))
+}
+
+func TestSimulatePlacementConstraints(t *testing.T) {
+	testCases := []struct {
+		constraint string
+		expected   map[string]int
+	}{
+		{
+			constraint: "node.labels.zone == EAST",
+			expected:   map[string]int{"m1": 1, "w1": 1, "w2": -1, "w3": -1, "w4": -1, "w5": -1},
+		},
+		{
+			constraint: "node.labels.zone!=east",
+			expected:   map[string]int{"m1": -1, "w1": -1, "w2": 1, "w3": 1, "w4": -1, "w5": -1},
+		},
+		{
+			constraint: "node.hostname==worker-2",
+			expected:   map[string]int{"m1": -1, "w1": -1, "w2": 2, "w3": -1, "w4": -1, "w5": -1},
+		},
+		{
+			constraint: "Node.Hostname==worker-2",
+			expected:   map[string]int{"m1": -1, "w1": -1, "w2": 2, "w3": -1, "w4": -1, "w5": -1},
+		},
+		{
+			constraint: "NODE.LABELS.zone==east",
+			expected:   map[string]int{"m1": 1, "w1": 1, "w2": -1, "w3": -1, "w4": -1, "w5": -1},
+		},
+		{
+			constraint: "engine.labels.engine==label",
+			expected:   map[string]int{"m1": 1, "w1": 1, "w2": 0, "w3": 0, "w4": -1, "w5": -1},
+		},
+		{
+			constraint: "node.platform.os==windows",
+			expected:   map[string]int{"m1": -1, "w1": -1, "w2": -1, "w3": -1, "w4": -1, "w5": -1},
+		},
+		{
+			constraint: "node.unknown==value",
+			expected:   map[string]int{"m1": -1, "w1": -1, "w2": -1, "w3": -1, "w4": -1, "w5": -1},
+		},
+	}
+	for _, tc := range testCases {
+		report, err := SimulatePlacement(replicatedSpec(2, &swarm.Placement{Constraints: []string{tc.constraint}}), placementNodes())
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(tc.expected, placedTasks(report)), tc.constraint)
+	}
+}
+
+func TestSimulatePlacementMaxReplicas(t *testing.T) {
+	spec := replicatedSpec(6, &swarm.Placement{MaxReplicas: 1})
+	report, err := SimulatePlacement(spec, placementNodes())
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]int{"m1": 1, "w1": 1, "w2": 1, "w3": 1, "w4": -1, "w5": -1}, placedTasks(report)))
+	assert.Check(t, is.Equal(uint64(2), report.Unplaced))
+}
+
+func TestSimulatePlacementPlatforms(t *testing.T) {
+	spec := replicatedSpec(1, &swarm.Placement{Platforms: []swarm.Platform{{OS: "linux", Architecture: "arm64"}}})
+	report, err := SimulatePlacement(spec, placementNodes())
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"platform linux/x86_64 is not supported by the image"}, report.Nodes[0].Reasons))
+	assert.Check(t, is.Equal(uint64(1), report.Unplaced))
+
+	spec = replicatedSpec(1, &swarm.Placement{Platforms: []swarm.Platform{{OS: "linux", Architecture: "amd64"}}})
+	report, err = SimulatePlacement(spec, placementNodes())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(report.Nodes[0].Reasons, 0))
+}
+
+func TestResolvePlatforms(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		distributionInspectFunc: func(ctx context.Context, image, encodedRegistryAuth string) (registrytypes.DistributionInspect, error) {
+			return registrytypes.DistributionInspect{Platforms: []ocispec.Platform{{OS: "linux", Architecture: "arm64", Variant: "v8"}}}, nil
+		},
+	})
+	spec := replicatedSpec(1, nil)
+	spec.TaskTemplate.ContainerSpec.Image = "busybox"
+	ResolvePlatforms(context.Background(), cli, &spec, false)
+	assert.Check(t, is.DeepEqual([]swarm.Platform{{OS: "linux", Architecture: "arm64"}}, spec.TaskTemplate.Placement.Platforms))
+
+	cli = test.NewFakeCli(&fakeClient{
+		distributionInspectFunc: func(ctx context.Context, image, encodedRegistryAuth string) (registrytypes.DistributionInspect, error) {
+			return registrytypes.DistributionInspect{}, errors.New("unauthorized")
+		},
+	})
+	spec = replicatedSpec(1, nil)
+	spec.TaskTemplate.ContainerSpec.Image = "private/image"
+	ResolvePlatforms(context.Background(), cli, &spec, false)
+	assert.Check(t, is.Nil(spec.TaskTemplate.Placement))
+	assert.Check(t, is.Equal("WARNING: cannot resolve image private/image, its platforms are not checked: unauthorized\n", cli.ErrBuffer().String()))
+}
+
+func TestSimulatePlacementGlobal(t *testing.T) {
+	spec := swarm.ServiceSpec{
+		Mode: swarm.ServiceMode{Global: &swarm.GlobalService{}},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{},
+			Resources:     &swarm.ResourceRequirements{Reservations: &swarm.Resources{NanoCPUs: 2e9, MemoryBytes: 1 << 30}},
+		},
+	}
+	nodes := placementNodes()
+	for i := range nodes {
+		nodes[i].Description.Resources = swarm.Resources{NanoCPUs: 4e9, MemoryBytes: 8 << 30}
+	}
+	nodes[1].Description.Resources = swarm.Resources{NanoCPUs: 1e9, MemoryBytes: 512 << 20}
+	report, err := SimulatePlacement(spec, nodes)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]int{"m1": 1, "w1": -1, "w2": 1, "w3": 1, "w4": -1, "w5": -1}, placedTasks(report)))
+	assert.Check(t, is.Equal(uint64(3), report.Tasks))
+	assert.Check(t, is.DeepEqual([]string{"reserves 2 CPUs, node has 1", "reserves 1GiB of memory, node has 512MiB"}, report.Nodes[1].Reasons))
+}
+
+func TestSimulatePlacementInvalidConstraint(t *testing.T) {
+	_, err := SimulatePlacement(replicatedSpec(1, &swarm.Placement{Constraints: []string{"node.role=worker"}}), nil)
+	assert.Check(t, is.Error(err, `invalid constraint "node.role=worker": the operator must be == or !=`))
+}
+
+func TestCreateDryRunPlacement(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+			return placementNodes(), nil
+		},
+		serviceCreateFunc: func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+			return types.ServiceCreateResponse{}, errors.New("unexpected create")
+		},
+	})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "placement", "--replicas", "4", "--replicas-max-per-node", "1", "--constraint", "node.role==worker", "nginx"})
+	assert.NilError(t, cmd.Execute())
+
+	golden.Assert(t, cli.OutBuffer().String(), "service-create-dry-run-placement.golden")
+}
+
+func TestUpdateDryRunPlacement(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+			return placementNodes(), nil
+		},
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: serviceID, Spec: replicatedSpec(2, nil)}, nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			return types.ServiceUpdateResponse{}, errors.New("unexpected update")
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "placement", "--constraint-add", "node.labels.zone==west", "web"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "w2   worker-2    worker    2 "))
+}
+
+func TestDryRunInvalid(t *testing.T) {
+	cmd := newCreateCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--dry-run", "all", "nginx"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `invalid --dry-run value "all": only "placement" is supported`))
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newPromoteCommand(dockerCli command.Cli) *cobra.Command {
+	options := newServiceOptions()
+
+	cmd := &cobra.Command{
+		Use:   "promote [OPTIONS] SERVICE",
+		Short: "Update the other tasks of a service in a canary update",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPromote(dockerCli, options, args[0])
+		},
+		Annotations: map[string]string{"version": "1.29"},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.quiet, flagQuiet, "q", false, "Suppress progress output")
+	addDetachFlag(flags, &options.detach)
+
+	return cmd
+}
+
+func runPromote(dockerCli command.Cli, options *serviceOptions, serviceID string) error {
+	apiClient := dockerCli.Client()
+	ctx := context.Background()
+
+	service, _, err := apiClient.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+	saved, ok := service.Spec.Labels[canaryLabel]
+	if !ok {
+		return errors.Errorf("service %s is not in a canary update", serviceID)
+	}
+
+	// the number of canary tasks is the parallelism of the canary update
+	if !options.quiet && service.Spec.UpdateConfig != nil {
+		status, err := getCanaryStatus(ctx, apiClient, service)
+		if err != nil {
+			return err
+		}
+		status.print(dockerCli.Out(), service.Spec.UpdateConfig.Parallelism)
+	}
+
+	// restoring the update config resumes the update of the other tasks
+	spec := &service.Spec
+	var updateConfig *swarm.UpdateConfig
+	if err := json.Unmarshal([]byte(saved), &updateConfig); err != nil {
+		return errors.Wrapf(err, "invalid %s label", canaryLabel)
+	}
+	spec.UpdateConfig = updateConfig
+	delete(spec.Labels, canaryLabel)
+
+	response, err := apiClient.ServiceUpdate(ctx, service.ID, service.Version, *spec, types.ServiceUpdateOptions{
+		RegistryAuthFrom: types.RegistryAuthFromSpec,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range response.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s\n", serviceID)
+
+	if options.detach || versions.LessThan(apiClient.ClientVersion(), "1.29") {
+		return nil
+	}
+
+	return WaitOnService(ctx, dockerCli, serviceID, options.quiet)
+}
@@ -7,11 +7,11 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/idresolver"
-	"github.com/docker/cli/cli/command/node"
 	"github.com/docker/cli/cli/command/task"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -24,6 +24,7 @@ type psOptions struct {
 	noTrunc   bool
 	format    string
 	filter    opts.FilterOpt
+	watch     bool
 }
 
 func newPsCommand(dockerCli command.Cli) *cobra.Command {
@@ -44,6 +45,7 @@ func newPsCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noResolve, "no-resolve", false, "Do not map IDs to Names")
 	flags.StringVar(&options.format, "format", "", "Pretty-print tasks using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.BoolVar(&options.watch, "watch", false, "Keep updating the tasks and the history of their states")
 
 	return cmd
 }
@@ -60,6 +62,10 @@ func runPS(dockerCli command.Cli, options psOptions) error {
 		return err
 	}
 
+	if options.watch {
+		return watchPS(ctx, dockerCli, options, filter, notfound)
+	}
+
 	tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: filter})
 	if err != nil {
 		return err
@@ -81,6 +87,26 @@ func runPS(dockerCli command.Cli, options psOptions) error {
 	return nil
 }
 
+// watchPS displays the tasks of the services until interrupted
+func watchPS(ctx context.Context, dockerCli command.Cli, options psOptions, filter filters.Args, notfound []string) error {
+	if options.quiet {
+		return errors.New("--watch cannot be used with --quiet")
+	}
+	if len(notfound) != 0 {
+		return errors.New(strings.Join(notfound, "\n"))
+	}
+	client := dockerCli.Client()
+
+	format := options.format
+	if len(format) == 0 {
+		format = task.DefaultFormat(dockerCli.ConfigFile(), false)
+	}
+	list := func(ctx context.Context) ([]swarm.Task, error) {
+		return client.TaskList(ctx, types.TaskListOptions{Filters: filter})
+	}
+	return task.Watch(ctx, dockerCli, list, idresolver.New(client, options.noResolve), !options.noTrunc, format)
+}
+
 func createFilter(ctx context.Context, client client.APIClient, options psOptions) (filters.Args, []string, error) {
 	filter := options.filter.Value()
 
@@ -143,7 +169,7 @@ func updateNodeFilter(ctx context.Context, client client.APIClient, filter filte
 	if filter.Contains("node") {
 		nodeFilters := filter.Get("node")
 		for _, nodeFilter := range nodeFilters {
-			nodeReference, err := node.Reference(ctx, client, nodeFilter)
+			nodeReference, err := idresolver.NodeReference(ctx, client, nodeFilter)
 			if err != nil {
 				return err
 			}
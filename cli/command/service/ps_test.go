@@ -108,6 +108,18 @@ func TestRunPSQuiet(t *testing.T) {
 	assert.Check(t, is.Equal("sxabyp0obqokwekpun4rjo0b3\n", cli.OutBuffer().String()))
 }
 
+func TestRunPSWatchQuiet(t *testing.T) {
+	client := &fakeClient{
+		serviceListFunc: func(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{{ID: "foo"}}, nil
+		},
+	}
+
+	cli := test.NewFakeCli(client)
+	err := runPS(cli, psOptions{services: []string{"foo"}, quiet: true, watch: true, filter: opts.NewFilterOpt()})
+	assert.Check(t, is.Error(err, "--watch cannot be used with --quiet"))
+}
+
 func TestUpdateNodeFilter(t *testing.T) {
 	selfNodeID := "foofoo"
 	filter := filters.NewArgs(
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/pkg/errors"
+)
+
+// versionSuffix matches the version of a rotated secret or config in its name
+var versionSuffix = regexp.MustCompile(`^(.+)\.v([0-9]+)$`)
+
+// Rotation describes the replacement of a secret or a config with a new
+// version of it in all the services that use it.
+type Rotation struct {
+	// Kind is the kind of the object, "secret" or "config"
+	Kind string
+	// ID and Name identify the object to replace
+	ID   string
+	Name string
+	// NewName is the name of the new version of the object
+	NewName string
+	DryRun  bool
+	Quiet   bool
+
+	// Uses returns whether a container uses the object
+	Uses func(containerSpec *swarm.ContainerSpec) bool
+	// Create creates the new version of the object, and returns its ID
+	Create func(ctx context.Context) (string, error)
+	// Replace replaces the references of a container to the object with
+	// references to its new version
+	Replace func(containerSpec *swarm.ContainerSpec, newID string)
+	// Remove removes the object
+	Remove func(ctx context.Context) error
+}
+
+// Rotate creates the new version of a secret or a config, replaces the object
+// with it in the services that use it, and removes the object once all the
+// services are updated. With DryRun set, it only lists the services that use
+// the object.
+func Rotate(ctx context.Context, dockerCli command.Cli, rotation Rotation) error {
+	client := dockerCli.Client()
+
+	services, err := client.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+	services = servicesUsing(services, rotation.Uses)
+
+	if rotation.DryRun {
+		if len(services) == 0 {
+			fmt.Fprintf(dockerCli.Out(), "No service uses %s %s\n", rotation.Kind, rotation.Name)
+			return nil
+		}
+		fmt.Fprintf(dockerCli.Out(), "%s %s would be replaced with %s in services:\n", strings.Title(rotation.Kind), rotation.Name, rotation.NewName)
+		for _, service := range services {
+			fmt.Fprintf(dockerCli.Out(), "  %s\n", service.Spec.Name)
+		}
+		return nil
+	}
+
+	newID, err := rotation.Create(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "Created %s %s\n", rotation.Kind, rotation.NewName)
+
+	for _, service := range services {
+		serviceSpec := service.Spec
+		containerSpec := *serviceSpec.TaskTemplate.ContainerSpec
+		rotation.Replace(&containerSpec, newID)
+		serviceSpec.TaskTemplate.ContainerSpec = &containerSpec
+		updateResponse, err := client.ServiceUpdate(ctx, service.ID, service.Version, serviceSpec, types.ServiceUpdateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update service %s", service.Spec.Name)
+		}
+		for _, warning := range updateResponse.Warnings {
+			fmt.Fprintln(dockerCli.Err(), warning)
+		}
+		fmt.Fprintf(dockerCli.Out(), "Updating service %s\n", service.Spec.Name)
+
+		if versions.LessThan(client.ClientVersion(), "1.29") {
+			continue
+		}
+		if err := WaitOnService(ctx, dockerCli, service.ID, rotation.Quiet); err != nil {
+			return errors.Wrapf(err, "failed to update service %s, %s %s was not removed", service.Spec.Name, rotation.Kind, rotation.Name)
+		}
+	}
+
+	if err := rotation.Remove(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "Removed %s %s\n", rotation.Kind, rotation.Name)
+	return nil
+}
+
+// NextVersion returns the name of the next version of a secret or a config:
+// "db" is followed by "db.v2", and "db.v2" by "db.v3"
+func NextVersion(name string) string {
+	if m := versionSuffix.FindStringSubmatch(name); m != nil {
+		if version, err := strconv.Atoi(m[2]); err == nil {
+			return fmt.Sprintf("%s.v%d", m[1], version+1)
+		}
+	}
+	return name + ".v2"
+}
+
+// servicesUsing returns the services of which the container uses an object,
+// sorted by name
+func servicesUsing(services []swarm.Service, uses func(*swarm.ContainerSpec) bool) []swarm.Service {
+	using := []swarm.Service{}
+	for _, service := range services {
+		containerSpec := service.Spec.TaskTemplate.ContainerSpec
+		if containerSpec != nil && uses(containerSpec) {
+			using = append(using, service)
+		}
+	}
+	sort.Slice(using, func(i, j int) bool { return using[i].Spec.Name < using[j].Spec.Name })
+	return using
+}
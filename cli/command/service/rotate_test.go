@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRotate(t *testing.T) {
+	services := []swarm.Service{
+		{ID: "web-id", Spec: swarm.ServiceSpec{
+			Annotations:  swarm.Annotations{Name: "web"},
+			TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Hostname: "uses"}},
+		}},
+		{ID: "job-id", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "job"}}},
+		{ID: "api-id", Spec: swarm.ServiceSpec{
+			Annotations:  swarm.Annotations{Name: "api"},
+			TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Hostname: "uses"}},
+		}},
+		{ID: "cache-id", Spec: swarm.ServiceSpec{
+			Annotations:  swarm.Annotations{Name: "cache"},
+			TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}},
+		}},
+	}
+	var (
+		updated []string
+		removed bool
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated = append(updated, serviceID+":"+service.TaskTemplate.ContainerSpec.Hostname)
+			return types.ServiceUpdateResponse{Warnings: []string{"warning for " + serviceID}}, nil
+		},
+	})
+	err := Rotate(context.Background(), cli, Rotation{
+		Kind:    "config",
+		Name:    "app",
+		NewName: "app.v2",
+		Uses: func(containerSpec *swarm.ContainerSpec) bool {
+			return containerSpec.Hostname == "uses"
+		},
+		Create: func(ctx context.Context) (string, error) {
+			return "app-v2-id", nil
+		},
+		Replace: func(containerSpec *swarm.ContainerSpec, newID string) {
+			containerSpec.Hostname = newID
+		},
+		Remove: func(ctx context.Context) error {
+			removed = true
+			return nil
+		},
+	})
+	assert.NilError(t, err)
+
+	assert.Check(t, is.DeepEqual([]string{"api-id:app-v2-id", "web-id:app-v2-id"}, updated))
+	assert.Check(t, removed)
+	// the listed services are left unchanged
+	assert.Check(t, is.Equal("uses", services[0].Spec.TaskTemplate.ContainerSpec.Hostname))
+
+	expected := `Created config app.v2
+Updating service api
+Updating service web
+Removed config app
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+	assert.Check(t, is.Equal("warning for api-id\nwarning for web-id\n", cli.ErrBuffer().String()))
+}
+
+func TestRotateCreateError(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{{ID: "web-id", Spec: swarm.ServiceSpec{
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}},
+			}}}, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			return types.ServiceUpdateResponse{}, errors.New("unexpected update")
+		},
+	})
+	err := Rotate(context.Background(), cli, Rotation{
+		Kind: "secret",
+		Uses: func(*swarm.ContainerSpec) bool { return true },
+		Create: func(ctx context.Context) (string, error) {
+			return "", errors.New("name conflicts with an existing object")
+		},
+	})
+	assert.Check(t, is.Error(err, "name conflicts with an existing object"))
+	assert.Check(t, is.Equal("", cli.OutBuffer().String()))
+}
+
+func TestNextVersion(t *testing.T) {
+	assert.Check(t, is.Equal("db.v2", NextVersion("db")))
+	assert.Check(t, is.Equal("db.v10", NextVersion("db.v9")))
+	assert.Check(t, is.Equal("db.vault.v2", NextVersion("db.vault")))
+	assert.Check(t, is.Equal("v3.v2", NextVersion("v3")))
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// loadSpecFile reads a service spec from a JSON or YAML file, or from stdin if
+// the file is "-"
+func loadSpecFile(dockerCli command.Cli, file string) (swarm.ServiceSpec, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if file == "-" {
+		data, err = ioutil.ReadAll(dockerCli.In())
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	return parseSpec(data, file)
+}
+
+// parseSpec parses a service spec. The data holds either a service spec, or
+// a service as output by `docker service inspect`, whose read-only fields are
+// ignored. The errors of the spec are reported with the paths of their fields.
+func parseSpec(data []byte, file string) (swarm.ServiceSpec, error) {
+	// JSON is YAML, but indenting with tabs is only valid in JSON
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		var err error
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return swarm.ServiceSpec{}, errors.Wrapf(err, "invalid service spec in %s", file)
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return swarm.ServiceSpec{}, errors.Wrapf(err, "invalid service spec in %s", file)
+	}
+
+	if value == nil {
+		return swarm.ServiceSpec{}, errors.Errorf("invalid service spec in %s: the file is empty", file)
+	}
+	path := ""
+	if list, ok := value.([]interface{}); ok {
+		if len(list) != 1 {
+			return swarm.ServiceSpec{}, errors.Errorf("invalid service spec in %s: expected one service, got %d", file, len(list))
+		}
+		value, path = list[0], "[0]"
+	}
+	if service, ok := value.(map[string]interface{}); ok && service["Spec"] != nil {
+		value, path = service["Spec"], joinField(path, "Spec")
+	}
+
+	problems := validateValue(path, value, reflect.TypeOf(swarm.ServiceSpec{}))
+	if spec, ok := value.(map[string]interface{}); ok && !hasField(spec, "TaskTemplate", "ContainerSpec") {
+		problems = append(problems, fmt.Sprintf("%s: missing", joinField(path, "TaskTemplate.ContainerSpec")))
+	}
+	if len(problems) > 0 {
+		return swarm.ServiceSpec{}, errors.Errorf("invalid service spec in %s:\n%s", file, strings.Join(problems, "\n"))
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	var spec swarm.ServiceSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return swarm.ServiceSpec{}, errors.Wrapf(err, "invalid service spec in %s", file)
+	}
+	return spec, nil
+}
+
+// hasField returns whether the nested objects have a field at the path
+func hasField(object map[string]interface{}, path ...string) bool {
+	for _, name := range path {
+		var value interface{}
+		for key, v := range object {
+			if strings.EqualFold(key, name) {
+				value = v
+			}
+		}
+		if value == nil {
+			return false
+		}
+		if object, _ = value.(map[string]interface{}); object == nil {
+			return true
+		}
+	}
+	return true
+}
+
+// validateValue checks that a decoded JSON value can be decoded into a value
+// of the type, and returns the problems found, prefixed with the paths of
+// their fields
+func validateValue(path string, value interface{}, t reflect.Type) []string {
+	if value == nil || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	problem := func(expected string) []string {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, expected, describeValue(value))}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return validateValue(path, value, t.Elem())
+	case reflect.Interface:
+		return nil
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return problem("an object")
+		}
+		fields := jsonFields(t)
+		var problems []string
+		for _, key := range sortedKeys(object) {
+			field, ok := lookupField(fields, key)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown field", joinField(path, key)))
+				continue
+			}
+			problems = append(problems, validateValue(joinField(path, key), object[key], field.Type)...)
+		}
+		return problems
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return problem("an object")
+		}
+		var problems []string
+		for _, key := range sortedKeys(object) {
+			problems = append(problems, validateValue(path+"["+key+"]", object[key], t.Elem())...)
+		}
+		return problems
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if _, ok := value.(string); !ok {
+				return problem("a base64 string")
+			}
+			return nil
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return problem("a list")
+		}
+		var problems []string
+		for i, item := range list {
+			problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", path, i), item, t.Elem())...)
+		}
+		return problems
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return problem("a string")
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return problem("a boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := value.(json.Number); !ok {
+			return problem("an integer")
+		} else if _, err := strconv.ParseInt(string(n), 10, t.Bits()); err != nil {
+			return problem("an integer")
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := value.(json.Number); !ok {
+			return problem("an unsigned integer")
+		} else if _, err := strconv.ParseUint(string(n), 10, t.Bits()); err != nil {
+			return problem("an unsigned integer")
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(json.Number); !ok {
+			return problem("a number")
+		}
+	}
+	return nil
+}
+
+// jsonFields returns the fields of a struct by their JSON names, including the
+// fields of its embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		switch {
+		case name == "-" || (f.PkgPath != "" && !f.Anonymous):
+			continue
+		case f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct:
+			for embeddedName, embedded := range jsonFields(f.Type) {
+				if _, ok := fields[embeddedName]; !ok {
+					fields[embeddedName] = embedded
+				}
+			}
+			continue
+		case name == "":
+			name = f.Name
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// lookupField finds a field like encoding/json does, preferring an exact
+// match of its name to a case-insensitive one
+func lookupField(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	if f, ok := fields[key]; ok {
+		return f, true
+	}
+	for name, f := range fields {
+		if strings.EqualFold(name, key) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	}
+	return fmt.Sprint(value)
+}
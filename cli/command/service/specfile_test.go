@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+const inspectSpec = `[
+	{
+		"ID": "0123456789ab",
+		"Version": {"Index": 42},
+		"CreatedAt": "2019-10-01T12:00:00Z",
+		"Spec": {
+			"Name": "web",
+			"Labels": {"com.example.tier": "front"},
+			"TaskTemplate": {
+				"ContainerSpec": {
+					"Image": "nginx:1.17",
+					"Env": ["LEVEL=info"]
+				},
+				"ForceUpdate": 3
+			},
+			"Mode": {"Replicated": {"Replicas": 2}},
+			"UpdateConfig": {"Parallelism": 1, "Delay": 10000000000}
+		},
+		"Endpoint": {"Spec": {}}
+	}
+]`
+
+const yamlSpec = `Name: web
+TaskTemplate:
+  ContainerSpec:
+    Image: nginx:1.17
+    Env: [LEVEL=info]
+Mode:
+  Replicated:
+    Replicas: 2
+`
+
+func TestParseSpec(t *testing.T) {
+	replicas := uint64(2)
+	expected := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.17", Env: []string{"LEVEL=info"}},
+		},
+		Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+	}
+
+	spec, err := parseSpec([]byte(yamlSpec), "spec.yaml")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(expected, spec))
+
+	expected.Labels = map[string]string{"com.example.tier": "front"}
+	expected.TaskTemplate.ForceUpdate = 3
+	expected.UpdateConfig = &swarm.UpdateConfig{Parallelism: 1, Delay: 10 * time.Second}
+	spec, err = parseSpec([]byte(inspectSpec), "spec.json")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(expected, spec))
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          string
+		expectedError string
+	}{
+		{
+			name: "field-errors",
+			spec: `[{"Spec": {
+				"Name": "web",
+				"TaskTemplate": {"ContainerSpec": {"Image": "nginx", "Env": "LEVEL=info", "Imag": "nginx"}},
+				"Mode": {"Replicated": {"Replicas": -1}},
+				"Labels": {"com.example.tier": 1}
+			}}]`,
+			expectedError: `invalid service spec in spec.json:
+[0].Spec.Labels[com.example.tier]: expected a string, got 1
+[0].Spec.Mode.Replicated.Replicas: expected an unsigned integer, got -1
+[0].Spec.TaskTemplate.ContainerSpec.Env: expected a list, got "LEVEL=info"
+[0].Spec.TaskTemplate.ContainerSpec.Imag: unknown field`,
+		},
+		{
+			name: "missing-container-spec",
+			spec: "Name: web\nUpdateConfig:\n  Delay: 5s\n",
+			expectedError: `invalid service spec in spec.json:
+UpdateConfig.Delay: expected an integer, got "5s"
+TaskTemplate.ContainerSpec: missing`,
+		},
+		{
+			name:          "several-services",
+			spec:          `[{"Spec": {}}, {"Spec": {}}]`,
+			expectedError: "invalid service spec in spec.json: expected one service, got 2",
+		},
+		{
+			name:          "empty",
+			spec:          "\n",
+			expectedError: "invalid service spec in spec.json: the file is empty",
+		},
+	}
+	for _, tc := range testCases {
+		_, err := parseSpec([]byte(tc.spec), "spec.json")
+		assert.Check(t, is.Error(err, tc.expectedError), tc.name)
+	}
+}
+
+func TestCreateSpecFile(t *testing.T) {
+	file := fs.NewFile(t, "spec", fs.WithContent(yamlSpec))
+	defer file.Remove()
+
+	var created swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceCreateFunc: func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+			created = service
+			return types.ServiceCreateResponse{ID: "service-id"}, nil
+		},
+	})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--spec-file", file.Path(), "--name", "front", "--replicas", "3", "--detach", "nginx:1.18", "nginx", "-g", "daemon off;"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal("front", created.Name))
+	assert.Check(t, is.Equal(uint64(3), *created.Mode.Replicated.Replicas))
+	assert.Check(t, is.Equal("nginx:1.18", created.TaskTemplate.ContainerSpec.Image))
+	assert.Check(t, is.DeepEqual([]string{"nginx", "-g", "daemon off;"}, created.TaskTemplate.ContainerSpec.Args))
+	assert.Check(t, is.DeepEqual([]string{"LEVEL=info"}, created.TaskTemplate.ContainerSpec.Env))
+	assert.Check(t, is.Equal("service-id\n", cli.OutBuffer().String()))
+}
+
+func TestCreateSpecFileWithCreateFlags(t *testing.T) {
+	file := fs.NewFile(t, "spec", fs.WithContent(yamlSpec))
+	defer file.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--spec-file", file.Path(), "--env", "LEVEL=debug", "--mode", "global"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "--env, --mode cannot be used with --spec-file: set the fields in the spec file instead"))
+}
+
+func TestUpdateSpecFile(t *testing.T) {
+	file := fs.NewFile(t, "spec", fs.WithContent(inspectSpec))
+	defer file.Remove()
+
+	var updated swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			service := canaryService(nil, nil)
+			service.Spec.TaskTemplate.ForceUpdate = 5
+			return service, nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated = service
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--spec-file", file.Path(), "--env-add", "LEVEL=debug", "--detach", "service-id"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual(map[string]string{"com.example.tier": "front"}, updated.Labels))
+	assert.Check(t, is.Equal("nginx:1.17", updated.TaskTemplate.ContainerSpec.Image))
+	assert.Check(t, is.DeepEqual([]string{"LEVEL=debug"}, updated.TaskTemplate.ContainerSpec.Env))
+	assert.Check(t, is.Equal(uint64(5), updated.TaskTemplate.ForceUpdate))
+	assert.Check(t, is.DeepEqual(&swarm.UpdateConfig{Parallelism: 1, Delay: 10 * time.Second}, updated.UpdateConfig))
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -41,6 +43,8 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation(flagRollback, "version", []string{"1.25"})
 	flags.Bool("force", false, "Force update even if no changes require it")
 	flags.SetAnnotation("force", "version", []string{"1.25"})
+	flags.String(flagCanary, "", "Only update a number or a percentage of the tasks, until the update is promoted")
+	flags.SetAnnotation(flagCanary, "version", []string{"1.29"})
 	addServiceFlags(flags, options, nil)
 
 	flags.Var(newListOptsVar(), flagEnvRemove, "Remove an environment variable")
@@ -123,6 +127,10 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 	apiClient := dockerCli.Client()
 	ctx := context.Background()
 
+	if err := ValidateDryRun(options.dryRun); err != nil {
+		return err
+	}
+
 	service, _, err := apiClient.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
 	if err != nil {
 		return err
@@ -168,17 +176,44 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 		}
 	}
 
+	if _, ok := service.Spec.Labels[canaryLabel]; ok && !rollback && !flags.Changed(flagCanary) {
+		return errors.Errorf("service %s is in a canary update: promote it with `docker service promote`, or roll it back with `docker service rollback`", serviceID)
+	}
+
 	updateOpts := types.ServiceUpdateOptions{}
 	if serverSideRollback {
 		updateOpts.Rollback = "previous"
 	}
 
+	// the task template before the update, to check that a canary update
+	// updates tasks
+	previousTaskTemplate, err := json.Marshal(spec.TaskTemplate)
+	if err != nil {
+		return err
+	}
+
+	imageChanged := flags.Changed("image")
+	if options.specFile != "" {
+		fileSpec, err := loadSpecFile(dockerCli, options.specFile)
+		if err != nil {
+			return err
+		}
+		if fileSpec.Name == "" {
+			fileSpec.Name = spec.Name
+		}
+		// the spec file replaces the spec, except for the counter that
+		// --force increments
+		fileSpec.TaskTemplate.ForceUpdate = spec.TaskTemplate.ForceUpdate
+		imageChanged = imageChanged || fileSpec.TaskTemplate.ContainerSpec.Image != spec.TaskTemplate.ContainerSpec.Image
+		spec = &fileSpec
+	}
+
 	err = updateService(ctx, apiClient, flags, spec)
 	if err != nil {
 		return err
 	}
 
-	if flags.Changed("image") {
+	if imageChanged {
 		if err := resolveServiceImageDigestContentTrust(dockerCli, spec); err != nil {
 			return err
 		}
@@ -206,6 +241,29 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 	// CredentialSpec.
 	updateCredSpecConfig(flags, spec.TaskTemplate.ContainerSpec)
 
+	if options.dryRun == DryRunPlacement {
+		// the platforms of an image that did not change are in the spec
+		if updateOpts.QueryRegistry {
+			ResolvePlatforms(ctx, dockerCli, spec, options.registryAuth)
+		}
+		return runPlacementDryRun(ctx, dockerCli, *spec)
+	}
+
+	var canaryTasks uint64
+	if flags.Changed(flagCanary) {
+		taskTemplate, err := json.Marshal(spec.TaskTemplate)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(taskTemplate, previousTaskTemplate) {
+			return errors.Errorf("--%s requires changes to the tasks of the service, or --force", flagCanary)
+		}
+		canary, _ := flags.GetString(flagCanary)
+		if canaryTasks, err = setCanaryUpdate(spec, canary); err != nil {
+			return err
+		}
+	}
+
 	// only send auth if flag was set
 	sendAuth, err := flags.GetBool(flagRegistryAuth)
 	if err != nil {
@@ -241,7 +299,10 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 		return nil
 	}
 
-	return waitOnService(ctx, dockerCli, serviceID, options.quiet)
+	if canaryTasks > 0 {
+		return waitOnCanary(ctx, dockerCli, serviceID, canaryTasks, options.quiet)
+	}
+	return WaitOnService(ctx, dockerCli, serviceID, options.quiet)
 }
 
 // nolint: gocyclo
@@ -468,13 +529,16 @@ func updateService(ctx context.Context, apiClient client.NetworkAPIClient, flags
 		return err
 	}
 
-	force, err := flags.GetBool("force")
-	if err != nil {
-		return err
-	}
-
-	if force {
-		spec.TaskTemplate.ForceUpdate++
+	// --force is only defined by `docker service update`, and not by
+	// `docker service create --spec-file`
+	if flags.Changed("force") {
+		force, err := flags.GetBool("force")
+		if err != nil {
+			return err
+		}
+		if force {
+			spec.TaskTemplate.ForceUpdate++
+		}
 	}
 
 	if err := updateHealthcheck(flags, cspec); err != nil {
@@ -0,0 +1,260 @@
+package stack
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/pkg/urlutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newBuildCommand(dockerCli command.Cli) *cobra.Command {
+	var opts options.Build
+
+	cmd := &cobra.Command{
+		Use:   "build [OPTIONS] [SERVICE...]",
+		Short: "Build the images of the services of a Compose file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Services = args
+			return runBuild(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.StringArrayVar(&opts.EnvFiles, "env-file", nil, "Read in a file of environment variables to interpolate the Compose files with")
+	flags.StringSliceVar(&opts.Profiles, "profile", nil, "Include the services of a profile, in addition to the services without profile")
+	flags.IntVar(&opts.Parallel, "parallel", 4, "Maximum number of images to build at the same time")
+	flags.BoolVar(&opts.Push, "push", false, "Push the images after building them")
+	flags.BoolVar(&opts.NoCache, "no-cache", false, "Do not use cache when building the images")
+	flags.BoolVar(&opts.Pull, "pull", false, "Always attempt to pull a newer version of the base images")
+	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress the build output and print the image IDs on success")
+	return cmd
+}
+
+func runBuild(dockerCli command.Cli, opts options.Build) error {
+	if len(opts.Composefiles) == 0 {
+		return errors.New("Please specify a Compose file (with --compose-file).")
+	}
+	if opts.Parallel < 1 {
+		return errors.Errorf("invalid --parallel value %d: must be at least 1", opts.Parallel)
+	}
+	config, err := loader.LoadComposefileToBuild(dockerCli, options.Deploy{
+		Composefiles: opts.Composefiles,
+		EnvFiles:     opts.EnvFiles,
+		Profiles:     opts.Profiles,
+	})
+	if err != nil {
+		return err
+	}
+	workingDir, err := loader.WorkingDir(opts.Composefiles)
+	if err != nil {
+		return err
+	}
+	services, err := servicesToBuild(config.Services, opts.Services)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		fmt.Fprintln(dockerCli.Err(), "No service to build")
+		return nil
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed []string
+		sem    = make(chan struct{}, opts.Parallel)
+		width  = prefixWidth(services)
+	)
+	for _, service := range services {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(service composetypes.ServiceConfig) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			serviceCli := newPrefixedCli(dockerCli, &mu, fmt.Sprintf("%-*s | ", width, service.Name))
+			defer serviceCli.flush()
+
+			if err := buildService(serviceCli, service, workingDir, opts); err != nil {
+				fmt.Fprintln(serviceCli.Err(), err)
+				mu.Lock()
+				failed = append(failed, service.Name)
+				mu.Unlock()
+			}
+		}(service)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return errors.Errorf("failed to build services: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// servicesToBuild returns the services with a build section, or the given
+// ones if any, sorted by name
+func servicesToBuild(services composetypes.Services, names []string) (composetypes.Services, error) {
+	byName := map[string]composetypes.ServiceConfig{}
+	for _, service := range services {
+		byName[service.Name] = service
+	}
+
+	var toBuild composetypes.Services
+	if len(names) == 0 {
+		for _, service := range services {
+			if service.Build.Context != "" {
+				toBuild = append(toBuild, service)
+			}
+		}
+	} else {
+		for _, name := range names {
+			service, ok := byName[name]
+			switch {
+			case !ok:
+				return nil, errors.Errorf("no such service: %s", name)
+			case service.Build.Context == "":
+				return nil, errors.Errorf("service %s has no build section", name)
+			}
+			toBuild = append(toBuild, service)
+		}
+	}
+	for _, service := range toBuild {
+		if service.Image == "" {
+			return nil, errors.Errorf("service %s has a build section but no image to tag the build with", service.Name)
+		}
+	}
+	sort.Slice(toBuild, func(i, j int) bool { return toBuild[i].Name < toBuild[j].Name })
+	return toBuild, nil
+}
+
+func buildService(dockerCli command.Cli, service composetypes.ServiceConfig, workingDir string, opts options.Build) error {
+	buildContext, dockerfile := buildPaths(service.Build, workingDir)
+	err := image.BuildImage(dockerCli, image.BuildImageOptions{
+		Context:     buildContext,
+		Dockerfile:  dockerfile,
+		Tags:        []string{service.Image},
+		BuildArgs:   service.Build.Args,
+		Labels:      service.Build.Labels,
+		CacheFrom:   service.Build.CacheFrom,
+		NetworkMode: service.Build.Network,
+		Target:      service.Build.Target,
+		NoCache:     opts.NoCache,
+		Pull:        opts.Pull,
+		Quiet:       opts.Quiet,
+	})
+	if err != nil || !opts.Push {
+		return err
+	}
+	return image.PushImage(dockerCli, service.Image)
+}
+
+// buildPaths returns the build context of a service, relative to the working
+// directory if it is a local path, and its Dockerfile, which is relative to
+// the context in Compose files
+func buildPaths(build composetypes.BuildConfig, workingDir string) (string, string) {
+	if urlutil.IsGitURL(build.Context) || urlutil.IsURL(build.Context) {
+		return build.Context, build.Dockerfile
+	}
+	buildContext := build.Context
+	if !filepath.IsAbs(buildContext) {
+		buildContext = filepath.Join(workingDir, buildContext)
+	}
+	dockerfile := build.Dockerfile
+	if dockerfile != "" && !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(buildContext, dockerfile)
+	}
+	return buildContext, dockerfile
+}
+
+func prefixWidth(services composetypes.Services) int {
+	width := 0
+	for _, service := range services {
+		if len(service.Name) > width {
+			width = len(service.Name)
+		}
+	}
+	return width
+}
+
+// prefixedCli is a command.Cli whose output and error streams prefix each
+// line, so that the output of concurrent builds can be told apart
+type prefixedCli struct {
+	command.Cli
+	out *prefixWriter
+	err *prefixWriter
+}
+
+func newPrefixedCli(dockerCli command.Cli, mu *sync.Mutex, prefix string) *prefixedCli {
+	return &prefixedCli{
+		Cli: dockerCli,
+		out: &prefixWriter{out: dockerCli.Out(), mu: mu, prefix: prefix},
+		err: &prefixWriter{out: dockerCli.Err(), mu: mu, prefix: prefix},
+	}
+}
+
+func (c *prefixedCli) Out() *streams.Out {
+	return streams.NewOut(c.out)
+}
+
+func (c *prefixedCli) Err() io.Writer {
+	return c.err
+}
+
+func (c *prefixedCli) flush() {
+	c.out.Flush()
+	c.err.Flush()
+}
+
+// prefixWriter writes complete lines to out with a prefix. Writers sharing a
+// mutex never interleave their lines.
+type prefixWriter struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		data := w.buf.Bytes()
+		i := bytes.IndexAny(data, "\r\n")
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(data[:i])
+		w.buf.Next(i + 1)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w.out, "%s%s\n", w.prefix, line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush writes the last line, if it is not terminated
+func (w *prefixWriter) Flush() {
+	if w.buf.Len() > 0 {
+		w.Write([]byte("\n"))
+	}
+}
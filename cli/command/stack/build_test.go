@@ -0,0 +1,125 @@
+package stack
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestBuildErrors(t *testing.T) {
+	file := fs.NewFile(t, "test-stack-build", fs.WithContent(`version: "3.8"
+services:
+  web:
+    image: example/web
+    build: ./web
+  api:
+    build: ./api
+  db:
+    image: postgres
+`))
+	defer file.Remove()
+
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{},
+			expectedError: "Please specify a Compose file",
+		},
+		{
+			args:          []string{"--compose-file", file.Path(), "--parallel", "0"},
+			expectedError: "invalid --parallel value 0: must be at least 1",
+		},
+		{
+			args:          []string{"--compose-file", file.Path()},
+			expectedError: "service api has a build section but no image to tag the build with",
+		},
+		{
+			args:          []string{"--compose-file", file.Path(), "web", "cache"},
+			expectedError: "no such service: cache",
+		},
+		{
+			args:          []string{"--compose-file", file.Path(), "db"},
+			expectedError: "service db has no build section",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newBuildCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestBuildWithoutBuildSection(t *testing.T) {
+	file := fs.NewFile(t, "test-stack-build", fs.WithContent(`version: "3.8"
+services:
+  db:
+    image: postgres
+`))
+	defer file.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newBuildCommand(cli)
+	cmd.SetArgs([]string{"--compose-file", file.Path()})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("No service to build\n", cli.ErrBuffer().String()))
+}
+
+func TestBuildPaths(t *testing.T) {
+	workingDir := filepath.FromSlash("/src/app")
+	testCases := []struct {
+		build              composetypes.BuildConfig
+		expectedContext    string
+		expectedDockerfile string
+	}{
+		{
+			build:           composetypes.BuildConfig{Context: "."},
+			expectedContext: workingDir,
+		},
+		{
+			build:              composetypes.BuildConfig{Context: "web", Dockerfile: "Dockerfile.prod"},
+			expectedContext:    filepath.Join(workingDir, "web"),
+			expectedDockerfile: filepath.Join(workingDir, "web", "Dockerfile.prod"),
+		},
+		{
+			build:              composetypes.BuildConfig{Context: "https://github.com/docker/example.git", Dockerfile: "docker/Dockerfile"},
+			expectedContext:    "https://github.com/docker/example.git",
+			expectedDockerfile: "docker/Dockerfile",
+		},
+	}
+	for _, tc := range testCases {
+		buildContext, dockerfile := buildPaths(tc.build, workingDir)
+		assert.Check(t, is.Equal(tc.expectedContext, buildContext))
+		assert.Check(t, is.Equal(tc.expectedDockerfile, dockerfile))
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var (
+		out bytes.Buffer
+		mu  sync.Mutex
+	)
+	web := &prefixWriter{out: &out, mu: &mu, prefix: "web | "}
+	api := &prefixWriter{out: &out, mu: &mu, prefix: "api | "}
+
+	fmt.Fprint(web, "Step 1/2 : FROM")
+	fmt.Fprint(api, "Step 1/3 : FROM alpine\n")
+	fmt.Fprint(web, " nginx\r\nStep 2/2")
+	web.Flush()
+	api.Flush()
+
+	expected := "api | Step 1/3 : FROM alpine\nweb | Step 1/2 : FROM nginx\nweb | Step 2/2\n"
+	assert.Check(t, is.Equal(expected, out.String()))
+}
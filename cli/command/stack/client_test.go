@@ -5,11 +5,16 @@ import (
 	"strings"
 
 	"github.com/docker/cli/cli/compose/convert"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
 )
 
 type fakeClient struct {
@@ -248,3 +253,26 @@ func buildObjectIDs(objectNames []string) []string {
 	}
 	return IDs
 }
+
+// fakeRegistryClient resolves image references from a map of references to
+// the digest of their manifest
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	digests map[string]ocispec.Descriptor
+}
+
+func (c *fakeRegistryClient) GetManifestDescriptor(_ context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	desc, ok := c.digests[reference.FamiliarString(ref)]
+	if !ok {
+		return ocispec.Descriptor{}, errors.Errorf("manifest unknown")
+	}
+	return desc, nil
+}
+
+func (c *fakeRegistryClient) GetManifest(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+	canonical := ref.(reference.Canonical)
+	return manifesttypes.ImageManifest{Descriptor: ocispec.Descriptor{
+		Digest:   canonical.Digest(),
+		Platform: &ocispec.Platform{OS: "linux", Architecture: "amd64"},
+	}}, nil
+}
@@ -60,11 +60,19 @@ func NewStackCommand(dockerCli command.Cli) *cobra.Command {
 		defaultHelpFunc(c, args)
 	})
 	cmd.AddCommand(
+		newBuildCommand(dockerCli),
+		newConfigCommand(dockerCli),
+		newConvertCommand(dockerCli),
 		newDeployCommand(dockerCli, &opts),
+		newDiffCommand(dockerCli, &opts),
+		newExportCommand(dockerCli, &opts),
 		newListCommand(dockerCli, &opts),
+		newLogsCommand(dockerCli, &opts),
 		newPsCommand(dockerCli, &opts),
 		newRemoveCommand(dockerCli, &opts),
+		newResolveCommand(dockerCli, &opts),
 		newServicesCommand(dockerCli, &opts),
+		newValidateCommand(dockerCli),
 	)
 	flags := cmd.PersistentFlags()
 	flags.String("kubeconfig", "", "Kubernetes config file")
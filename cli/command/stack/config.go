@@ -0,0 +1,54 @@
+package stack
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func newConfigCommand(dockerCli command.Cli) *cobra.Command {
+	var opts options.Config
+
+	cmd := &cobra.Command{
+		Use:   "config [OPTIONS]",
+		Short: "Outputs the final config file, after doing merges and interpolations",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfig(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.StringArrayVar(&opts.EnvFiles, "env-file", nil, "Read in a file of environment variables to interpolate the Compose files with")
+	flags.StringSliceVar(&opts.Profiles, "profile", nil, "Include the services of a profile, in addition to the services without profile")
+	flags.BoolVar(&opts.Strict, "strict", false, "Fail if variables referenced without a default value are not set")
+	return cmd
+}
+
+func runConfig(dockerCli command.Cli, opts options.Config) error {
+	if len(opts.Composefiles) == 0 {
+		return errors.New("Please specify a Compose file (with --compose-file).")
+	}
+	config, err := loader.LoadComposefile(dockerCli, options.Deploy{
+		Composefiles: opts.Composefiles,
+		EnvFiles:     opts.EnvFiles,
+		Profiles:     opts.Profiles,
+		Strict:       opts.Strict,
+	})
+	if err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(dockerCli.Out(), string(out))
+	return nil
+}
@@ -0,0 +1,85 @@
+package stack
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+	"gotest.tools/golden"
+)
+
+func TestConfigWithEnvFiles(t *testing.T) {
+	dir := fs.NewDir(t, "test-stack-config",
+		fs.WithFile("docker-compose.yml", `version: "3.7"
+services:
+  web:
+    image: nginx:${TAG}
+    environment:
+      GREETING: ${GREETING}
+`),
+		fs.WithFile(".env", "TAG=1.14\nGREETING=\"hello\\tworld\"\n"),
+		fs.WithFile("prod.env", "TAG=\"1.15\" # overrides .env\n"),
+	)
+	defer dir.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newConfigCommand(cli)
+	cmd.SetArgs([]string{"--compose-file", dir.Join("docker-compose.yml"), "--env-file", dir.Join("prod.env")})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "stack-config-with-env-files.golden")
+}
+
+func TestConfigWithoutComposeFile(t *testing.T) {
+	cmd := newConfigCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "Please specify a Compose file")
+}
+
+func TestConfigStrict(t *testing.T) {
+	file := fs.NewFile(t, "test-stack-config", fs.WithContent(`version: "3.7"
+services:
+  web:
+    image: nginx:${STACK_CONFIG_TEST_TAG}
+    environment:
+      LEVEL: ${STACK_CONFIG_TEST_LEVEL:-info}
+`))
+	defer file.Remove()
+
+	cmd := newConfigCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--compose-file", file.Path(), "--strict"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Error(t, cmd.Execute(), "required variables are not set:\n  "+file.Path()+":4: services.web.image: STACK_CONFIG_TEST_TAG")
+}
+
+func TestConfigWithProfiles(t *testing.T) {
+	file := fs.NewFile(t, "test-stack-config", fs.WithContent(`version: "3.8"
+services:
+  web:
+    image: nginx
+  debug:
+    image: busybox
+    profiles: [debug]
+`))
+	defer file.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newConfigCommand(cli)
+	cmd.SetArgs([]string{"--compose-file", file.Path()})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, !strings.Contains(cli.OutBuffer().String(), "debug"))
+
+	cli = test.NewFakeCli(&fakeClient{})
+	cmd = newConfigCommand(cli)
+	cmd.SetArgs([]string{"--compose-file", file.Path(), "--profile", "debug", "--profile", "unknown"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "stack-config-with-profiles.golden")
+	assert.Check(t, is.Equal("Profile \"unknown\" is not used by any service\n", cli.ErrBuffer().String()))
+}
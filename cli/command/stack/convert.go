@@ -0,0 +1,59 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/kubernetes"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newConvertCommand(dockerCli command.Cli) *cobra.Command {
+	var opts options.Convert
+
+	cmd := &cobra.Command{
+		Use:   "convert [OPTIONS] STACK",
+		Short: "Convert a Compose file to the manifests of another orchestrator",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			return runConvert(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.StringArrayVar(&opts.EnvFiles, "env-file", nil, "Read in a file of environment variables to interpolate the Compose files with")
+	flags.StringSliceVar(&opts.Profiles, "profile", nil, "Include the services of a profile, in addition to the services without profile")
+	flags.StringVar(&opts.To, "to", "", `Format to convert to ("kubernetes")`)
+	return cmd
+}
+
+func runConvert(dockerCli command.Cli, opts options.Convert) error {
+	if len(opts.Composefiles) == 0 {
+		return errors.New("Please specify a Compose file (with --compose-file).")
+	}
+	if opts.To != "kubernetes" {
+		return errors.Errorf(`invalid --to value %q: only "kubernetes" is supported`, opts.To)
+	}
+	config, err := loader.LoadComposefile(dockerCli, options.Deploy{
+		Composefiles: opts.Composefiles,
+		EnvFiles:     opts.EnvFiles,
+		Namespace:    opts.Namespace,
+		Profiles:     opts.Profiles,
+	})
+	if err != nil {
+		return err
+	}
+	manifests, err := kubernetes.Manifests(dockerCli.Err(), opts.Namespace, config)
+	if err != nil {
+		return err
+	}
+	_, err = dockerCli.Out().Write(manifests)
+	return err
+}
@@ -0,0 +1,62 @@
+package stack
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	"gotest.tools/fs"
+)
+
+func TestConvertErrors(t *testing.T) {
+	file := fs.NewFile(t, "test-stack-convert", fs.WithContent(`version: "3.8"
+services:
+  web:
+    image: nginx
+`))
+	defer file.Remove()
+
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"mystack"},
+			expectedError: "Please specify a Compose file",
+		},
+		{
+			args:          []string{"--compose-file", file.Path(), "mystack"},
+			expectedError: `invalid --to value "": only "kubernetes" is supported`,
+		},
+		{
+			args:          []string{"--compose-file", file.Path(), "--to", "nomad", "mystack"},
+			expectedError: `invalid --to value "nomad": only "kubernetes" is supported`,
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newConvertCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestConvertToKubernetes(t *testing.T) {
+	file := fs.NewFile(t, "test-stack-convert", fs.WithContent(`version: "3.8"
+services:
+  web:
+    image: nginx
+`))
+	defer file.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newConvertCommand(cli)
+	cmd.SetArgs([]string{"--compose-file", file.Path(), "--to", "kubernetes", "mystack"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	out := cli.OutBuffer().String()
+	assert.Check(t, strings.Contains(out, "kind: Deployment"))
+	assert.Check(t, strings.Contains(out, "com.docker.stack.namespace: mystack"))
+}
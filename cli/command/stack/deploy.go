@@ -2,9 +2,11 @@ package stack
 
 import (
 	"context"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/lockfile"
 	"github.com/docker/cli/cli/command/stack/kubernetes"
 	"github.com/docker/cli/cli/command/stack/loader"
 	"github.com/docker/cli/cli/command/stack/options"
@@ -50,6 +52,11 @@ func newDeployCommand(dockerCli command.Cli, common *commonOptions) *cobra.Comma
 			if err != nil {
 				return err
 			}
+			if opts.Lockfile != "" {
+				if err := pinImages(config, opts.Lockfile); err != nil {
+					return err
+				}
+			}
 			return RunDeploy(dockerCli, cmd.Flags(), config, common.Orchestrator(), opts)
 		},
 	}
@@ -60,19 +67,51 @@ func newDeployCommand(dockerCli command.Cli, common *commonOptions) *cobra.Comma
 	flags.SetAnnotation("bundle-file", "swarm", nil)
 	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
 	flags.SetAnnotation("compose-file", "version", []string{"1.25"})
+	flags.StringArrayVar(&opts.EnvFiles, "env-file", nil, "Read in a file of environment variables to interpolate the Compose files with")
+	flags.StringSliceVar(&opts.Profiles, "profile", nil, "Deploy the services of a profile, in addition to the services without profile")
+	flags.BoolVar(&opts.Strict, "strict", false, "Fail if variables referenced without a default value are not set")
 	flags.BoolVar(&opts.SendRegistryAuth, "with-registry-auth", false, "Send registry authentication details to Swarm agents")
 	flags.SetAnnotation("with-registry-auth", "swarm", nil)
-	flags.BoolVar(&opts.Prune, "prune", false, "Prune services that are no longer referenced")
+	flags.BoolVar(&opts.Prune, "prune", false, "Prune services, secrets, and configs that are no longer referenced")
 	flags.SetAnnotation("prune", "version", []string{"1.27"})
 	flags.SetAnnotation("prune", "swarm", nil)
 	flags.StringVar(&opts.ResolveImage, "resolve-image", swarm.ResolveImageAlways,
 		`Query the registry to resolve image digest and supported platforms ("`+swarm.ResolveImageAlways+`"|"`+swarm.ResolveImageChanged+`"|"`+swarm.ResolveImageNever+`")`)
 	flags.SetAnnotation("resolve-image", "version", []string{"1.30"})
 	flags.SetAnnotation("resolve-image", "swarm", nil)
+	flags.BoolVar(&opts.Ordered, "ordered", false, "Deploy the services in the order of their dependencies (depends_on), waiting for them to be running and healthy")
+	flags.SetAnnotation("ordered", "swarm", nil)
+	flags.DurationVar(&opts.WaitTimeout, "wait-timeout", 5*time.Minute, "Maximum time to wait for a group of services to be running and healthy, with --ordered")
+	flags.SetAnnotation("wait-timeout", "swarm", nil)
+	flags.StringVar(&opts.DryRun, "dry-run", "", `Only simulate the deployment of the stack ("placement")`)
+	flags.SetAnnotation("dry-run", "swarm", nil)
+	flags.StringVar(&opts.Lockfile, "lockfile", "", "Deploy the images pinned in a lockfile written by \"docker stack resolve\"")
 	kubernetes.AddNamespaceFlag(flags)
 	return cmd
 }
 
+// pinImages replaces the image of each service in config with the reference
+// pinned in the lockfile at path. Every image must be pinned, so that the
+// stack is deployed exactly as resolved. The services without an image, such
+// as services that are only built, are left as they are.
+func pinImages(config *composetypes.Config, path string) error {
+	lock, err := lockfile.Load(path)
+	if err != nil {
+		return err
+	}
+	for i, service := range config.Services {
+		if service.Image == "" {
+			continue
+		}
+		pinned, ok := lock.Pin(service.Image)
+		if !ok {
+			return errors.Errorf("image %s of service %s is not pinned in lockfile %s", service.Image, service.Name, path)
+		}
+		config.Services[i].Image = pinned
+	}
+	return nil
+}
+
 // RunDeploy performs a stack deploy against the specified orchestrator
 func RunDeploy(dockerCli command.Cli, flags *pflag.FlagSet, config *composetypes.Config, commonOrchestrator command.Orchestrator, opts options.Deploy) error {
 	return runOrchestratedCommand(dockerCli, flags, commonOrchestrator,
@@ -2,10 +2,15 @@ package stack
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/docker/cli/cli/command/image/lockfile"
+	composetypes "github.com/docker/cli/cli/compose/types"
 	"github.com/docker/cli/internal/test"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func TestDeployWithEmptyName(t *testing.T) {
@@ -15,3 +20,28 @@ func TestDeployWithEmptyName(t *testing.T) {
 
 	assert.ErrorContains(t, cmd.Execute(), `invalid stack name: "'   '"`)
 }
+
+func TestPinImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stack-deploy-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stack.lock")
+
+	lock := lockfile.New()
+	lock.Images["nginx:1.15"] = lockfile.Image{Reference: "nginx:1.15@" + nginxDigest.String(), Digest: nginxDigest}
+	assert.NilError(t, lock.Save(path))
+
+	config := &composetypes.Config{Services: []composetypes.ServiceConfig{
+		{Name: "web", Image: "nginx:1.15"},
+		{Name: "app", Build: composetypes.BuildConfig{Context: "."}},
+	}}
+	assert.NilError(t, pinImages(config, path))
+	assert.Check(t, is.Equal("nginx:1.15@"+nginxDigest.String(), config.Services[0].Image))
+	assert.Check(t, is.Equal("", config.Services[1].Image))
+
+	config = &composetypes.Config{Services: []composetypes.ServiceConfig{
+		{Name: "web", Image: "nginx:1.15"},
+		{Name: "cache", Image: "redis"},
+	}}
+	assert.ErrorContains(t, pinImages(config, path), "image redis of service cache is not pinned in lockfile")
+}
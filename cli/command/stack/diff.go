@@ -0,0 +1,61 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
+	var opts options.Diff
+
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] STACK",
+		Short: "Show the changes that deploying Compose files would make to the services of a stack",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			if !common.Orchestrator().HasSwarm() {
+				return errors.New("comparing a stack is only supported on swarm")
+			}
+			return runDiff(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.StringArrayVar(&opts.EnvFiles, "env-file", nil, "Read in a file of environment variables to interpolate the Compose files with")
+	flags.StringSliceVar(&opts.Profiles, "profile", nil, "Compare the services of a profile, in addition to the services without profile")
+	flags.StringVar(&opts.Lockfile, "lockfile", "", "Compare with the images pinned in a lockfile written by \"docker stack resolve\"")
+	flags.StringSliceVar(&opts.Services, "service", nil, "Only compare the given services of the Compose files")
+	flags.StringVar(&opts.Format, "format", "", "Pretty-print the changes using a Go template")
+	return cmd
+}
+
+func runDiff(dockerCli command.Cli, opts options.Diff) error {
+	if len(opts.Composefiles) == 0 {
+		return errors.New("Please specify a Compose file (with --compose-file).")
+	}
+	config, err := loader.LoadComposefile(dockerCli, options.Deploy{
+		Composefiles: opts.Composefiles,
+		EnvFiles:     opts.EnvFiles,
+		Namespace:    opts.Namespace,
+		Profiles:     opts.Profiles,
+	})
+	if err != nil {
+		return err
+	}
+	if opts.Lockfile != "" {
+		if err := pinImages(config, opts.Lockfile); err != nil {
+			return err
+		}
+	}
+	return swarm.RunDiff(dockerCli, opts, config)
+}
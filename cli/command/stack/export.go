@@ -0,0 +1,31 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newExportCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
+	var opts options.Export
+
+	cmd := &cobra.Command{
+		Use:   "export STACK",
+		Short: "Generate a Compose file from the services of a stack",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			if !common.Orchestrator().HasSwarm() {
+				return errors.New("exporting a stack is only supported on swarm")
+			}
+			return swarm.RunExport(dockerCli, opts)
+		},
+	}
+	return cmd
+}
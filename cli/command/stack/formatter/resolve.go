@@ -0,0 +1,122 @@
+package formatter
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/pkg/stringid"
+	digest "github.com/opencontainers/go-digest"
+)
+
+const (
+	// ImageStatusTableFormat is the default format for image resolution reports
+	ImageStatusTableFormat = "table {{.Service}}\t{{.Image}}\t{{.Pinned}}\t{{.Current}}\t{{.Status}}"
+
+	imageStatusServiceHeader = "SERVICE"
+	imageStatusImageHeader   = "IMAGE"
+	imageStatusPinnedHeader  = "PINNED"
+	imageStatusCurrentHeader = "CURRENT"
+	imageStatusStatusHeader  = "STATUS"
+
+	// ImageStatusPinned is the status of a service pinned to the digest its
+	// image currently resolves to
+	ImageStatusPinned = "pinned"
+	// ImageStatusDrift is the status of a service pinned to a digest other
+	// than the one its image currently resolves to
+	ImageStatusDrift = "drift"
+	// ImageStatusUnpinned is the status of a service not pinned to a digest
+	ImageStatusUnpinned = "unpinned"
+)
+
+// ImageStatus compares the digest a service is pinned to with the digest its
+// image currently resolves to.
+type ImageStatus struct {
+	// Service is the name of the service
+	Service string
+	// Image is the image reference of the service
+	Image string
+	// Pinned is the digest the service is pinned to, if any
+	Pinned digest.Digest
+	// Current is the digest the image currently resolves to
+	Current digest.Digest
+	// Platforms are the digests of the platform specific manifests the image
+	// currently resolves to
+	Platforms []digest.Digest
+}
+
+// Status returns whether the service is pinned to the current digest of its
+// image. A service pinned to the manifest of one of the image's platforms
+// is considered up to date.
+func (s ImageStatus) Status() string {
+	if s.Pinned == "" {
+		return ImageStatusUnpinned
+	}
+	if s.Pinned == s.Current {
+		return ImageStatusPinned
+	}
+	for _, p := range s.Platforms {
+		if s.Pinned == p {
+			return ImageStatusPinned
+		}
+	}
+	return ImageStatusDrift
+}
+
+// ImageStatusWrite writes formatted image statuses using the Context
+func ImageStatusWrite(ctx formatter.Context, statuses []ImageStatus) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, status := range statuses {
+			if err := format(&imageStatusContext{trunc: ctx.Trunc, s: status}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	statusCtx := &imageStatusContext{}
+	statusCtx.Header = formatter.SubHeaderContext{
+		"Service": imageStatusServiceHeader,
+		"Image":   imageStatusImageHeader,
+		"Pinned":  imageStatusPinnedHeader,
+		"Current": imageStatusCurrentHeader,
+		"Status":  imageStatusStatusHeader,
+	}
+	return ctx.Write(statusCtx, render)
+}
+
+type imageStatusContext struct {
+	formatter.HeaderContext
+	trunc bool
+	s     ImageStatus
+}
+
+func (c *imageStatusContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *imageStatusContext) Service() string {
+	return c.s.Service
+}
+
+func (c *imageStatusContext) Image() string {
+	return c.s.Image
+}
+
+func (c *imageStatusContext) Pinned() string {
+	return c.digest(c.s.Pinned)
+}
+
+func (c *imageStatusContext) Current() string {
+	return c.digest(c.s.Current)
+}
+
+func (c *imageStatusContext) Status() string {
+	return c.s.Status()
+}
+
+func (c *imageStatusContext) digest(d digest.Digest) string {
+	if d == "" {
+		return "-"
+	}
+	if c.trunc {
+		return stringid.TruncateID(d.String())
+	}
+	return d.String()
+}
@@ -0,0 +1,638 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	latest "github.com/docker/compose-on-kubernetes/api/compose/v1alpha3"
+	"github.com/docker/compose-on-kubernetes/api/labels"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// defaultVolumeSize is the storage requested by the claims of the named
+	// volumes of a service
+	defaultVolumeSize = "1Gi"
+
+	nodeOSLabel       = "beta.kubernetes.io/os"
+	nodeArchLabel     = "beta.kubernetes.io/arch"
+	nodeHostnameLabel = "kubernetes.io/hostname"
+)
+
+var (
+	typeConfigMap   = metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+	typeSecret      = metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}
+	typeService     = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+	typeDeployment  = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	typeStatefulSet = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"}
+	typeDaemonSet   = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"}
+
+	invalidDNSLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// Manifests converts a compose config to the plain Kubernetes objects that
+// deploy the stack without the compose-on-kubernetes controller, and returns
+// them as a stream of YAML documents. The features that cannot be converted
+// are reported to stderr, as when deploying the stack.
+func Manifests(stderr io.Writer, stackName string, cfg *composetypes.Config) ([]byte, error) {
+	// templates are rendered in the ConfigMaps and Secrets, so they must not
+	// be reported as unsupported
+	converted := *cfg
+	converted.Secrets = map[string]composetypes.SecretConfig{}
+	for name, secret := range cfg.Secrets {
+		secret.Template = false
+		converted.Secrets[name] = secret
+	}
+	converted.Configs = map[string]composetypes.ConfigObjConfig{}
+	for name, config := range cfg.Configs {
+		config.Template = false
+		converted.Configs[name] = config
+	}
+	spec, err := fromComposeConfig(stderr, &converted, v1alpha3Capabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := toManifests(stackName, cfg, spec)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString("---\n")
+		out.Write(data)
+	}
+	return out.Bytes(), nil
+}
+
+func toManifests(stackName string, cfg *composetypes.Config, spec *latest.StackSpec) ([]interface{}, error) {
+	var objects []interface{}
+	for _, name := range sortedFileObjectNames(cfg.Configs) {
+		config := composetypes.FileObjectConfig(cfg.Configs[name])
+		if config.External.External {
+			continue
+		}
+		data, err := fileObjectData("config", name, config)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, &apiv1.ConfigMap{
+			TypeMeta:   typeConfigMap,
+			ObjectMeta: objectMeta(fileObjectName(stackName, name, config), stackName, "", config.Labels),
+			Data:       map[string]string{name: string(data)},
+		})
+	}
+	for _, name := range sortedFileObjectNames(cfg.Secrets) {
+		secret := composetypes.FileObjectConfig(cfg.Secrets[name])
+		if secret.External.External {
+			continue
+		}
+		data, err := fileObjectData("secret", name, secret)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, &apiv1.Secret{
+			TypeMeta:   typeSecret,
+			ObjectMeta: objectMeta(fileObjectName(stackName, name, secret), stackName, "", secret.Labels),
+			Type:       apiv1.SecretTypeOpaque,
+			Data:       map[string][]byte{name: data},
+		})
+	}
+
+	services := append([]latest.ServiceConfig(nil), spec.Services...)
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	for _, service := range services {
+		workload, err := toWorkload(stackName, cfg, service)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %s", service.Name)
+		}
+		objects = append(objects, workload)
+		objects = append(objects, toServices(stackName, service)...)
+	}
+	return objects, nil
+}
+
+func sortedFileObjectNames(objects interface{}) []string {
+	var names []string
+	switch objects := objects.(type) {
+	case map[string]composetypes.ConfigObjConfig:
+		for name := range objects {
+			names = append(names, name)
+		}
+	case map[string]composetypes.SecretConfig:
+		for name := range objects {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fileObjectName returns the name of the ConfigMap or Secret of a config or
+// secret. The key of its data is the name of the config or secret.
+func fileObjectName(stackName, name string, obj composetypes.FileObjectConfig) string {
+	if obj.Name != "" {
+		return obj.Name
+	}
+	return dnsLabel(stackName + "-" + name)
+}
+
+func fileObjectData(kind, name string, obj composetypes.FileObjectConfig) ([]byte, error) {
+	switch {
+	case obj.Template:
+		return []byte(obj.Content), nil
+	case obj.File == "":
+		return nil, errors.Errorf("%s %s: only file based %ss can be converted", kind, name, kind)
+	}
+	data, err := ioutil.ReadFile(obj.File)
+	return data, errors.Wrapf(err, "failed to read %s %s", kind, name)
+}
+
+func objectMeta(name, stackName, serviceName string, extraLabels map[string]string) metav1.ObjectMeta {
+	objectLabels := labels.ForService(stackName, serviceName)
+	for key, value := range extraLabels {
+		objectLabels[key] = value
+	}
+	return metav1.ObjectMeta{Name: name, Labels: objectLabels}
+}
+
+// dnsLabel makes name a valid DNS label, as required for the names of most
+// Kubernetes objects
+func dnsLabel(name string) string {
+	return strings.Trim(invalidDNSLabelChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// toWorkload returns a DaemonSet for a global service, a StatefulSet for a
+// service with named volumes, and a Deployment otherwise
+func toWorkload(stackName string, cfg *composetypes.Config, s latest.ServiceConfig) (interface{}, error) {
+	podTemplate, claims, err := toPodTemplate(stackName, cfg, s)
+	if err != nil {
+		return nil, err
+	}
+	meta := objectMeta(dnsLabel(s.Name), stackName, s.Name, s.Deploy.Labels)
+	selector := &metav1.LabelSelector{MatchLabels: labels.ForService(stackName, s.Name)}
+	var maxUnavailable *intstr.IntOrString
+	if s.Deploy.UpdateConfig != nil && s.Deploy.UpdateConfig.Parallelism != nil && *s.Deploy.UpdateConfig.Parallelism > 0 {
+		parallelism := intstr.FromInt(int(*s.Deploy.UpdateConfig.Parallelism))
+		maxUnavailable = &parallelism
+	}
+
+	if s.Deploy.Mode == "global" {
+		if len(claims) > 0 {
+			return nil, errors.New("named volumes are not supported by global services")
+		}
+		daemonSet := &appsv1.DaemonSet{
+			TypeMeta:   typeDaemonSet,
+			ObjectMeta: meta,
+			Spec: appsv1.DaemonSetSpec{
+				Selector: selector,
+				Template: podTemplate,
+			},
+		}
+		if maxUnavailable != nil {
+			daemonSet.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: maxUnavailable},
+			}
+		}
+		return daemonSet, nil
+	}
+
+	replicas := int32(1)
+	if s.Deploy.Replicas != nil {
+		replicas = int32(*s.Deploy.Replicas)
+	}
+	if len(claims) > 0 {
+		return &appsv1.StatefulSet{
+			TypeMeta:   typeStatefulSet,
+			ObjectMeta: meta,
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:             &replicas,
+				Selector:             selector,
+				ServiceName:          dnsLabel(s.Name),
+				Template:             podTemplate,
+				VolumeClaimTemplates: claims,
+			},
+		}, nil
+	}
+	deployment := &appsv1.Deployment{
+		TypeMeta:   typeDeployment,
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: selector,
+			Template: podTemplate,
+		},
+	}
+	if maxUnavailable != nil {
+		deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: maxUnavailable},
+		}
+	}
+	return deployment, nil
+}
+
+// toPodTemplate returns the pod template of a service, and the claims of its
+// named volumes
+func toPodTemplate(stackName string, cfg *composetypes.Config, s latest.ServiceConfig) (apiv1.PodTemplateSpec, []apiv1.PersistentVolumeClaim, error) {
+	container := apiv1.Container{
+		Name:            dnsLabel(s.Name),
+		Image:           s.Image,
+		ImagePullPolicy: apiv1.PullPolicy(s.PullPolicy),
+		Command:         s.Entrypoint,
+		Args:            s.Command,
+		WorkingDir:      s.WorkingDir,
+		Env:             toEnv(s.Environment),
+		Ports:           toContainerPorts(s),
+		Stdin:           s.StdinOpen,
+		TTY:             s.Tty,
+		LivenessProbe:   toProbe(s.HealthCheck),
+	}
+	resources, err := toResources(s.Deploy.Resources)
+	if err != nil {
+		return apiv1.PodTemplateSpec{}, nil, err
+	}
+	container.Resources = resources
+	if s.Privileged || s.ReadOnly || s.User != nil || len(s.CapAdd) > 0 || len(s.CapDrop) > 0 {
+		container.SecurityContext = &apiv1.SecurityContext{RunAsUser: s.User}
+		if s.Privileged {
+			container.SecurityContext.Privileged = &s.Privileged
+		}
+		if s.ReadOnly {
+			container.SecurityContext.ReadOnlyRootFilesystem = &s.ReadOnly
+		}
+		if len(s.CapAdd) > 0 || len(s.CapDrop) > 0 {
+			container.SecurityContext.Capabilities = &apiv1.Capabilities{
+				Add:  toCapabilities(s.CapAdd),
+				Drop: toCapabilities(s.CapDrop),
+			}
+		}
+	}
+
+	pod := apiv1.PodSpec{
+		Hostname:    s.Hostname,
+		HostIPC:     s.Ipc == "host",
+		HostPID:     s.Pid == "host",
+		HostAliases: toHostAliases(s.ExtraHosts),
+		Affinity:    toAffinity(s.Deploy.Placement.Constraints),
+	}
+	if s.PullSecret != "" {
+		pod.ImagePullSecrets = []apiv1.LocalObjectReference{{Name: s.PullSecret}}
+	}
+	if s.StopGracePeriod != nil {
+		seconds := int64(*s.StopGracePeriod / time.Second)
+		pod.TerminationGracePeriodSeconds = &seconds
+	}
+
+	var claims []apiv1.PersistentVolumeClaim
+	for i, v := range s.Volumes {
+		name := fmt.Sprintf("volume-%d", i)
+		var source apiv1.VolumeSource
+		switch {
+		case v.Type == "bind":
+			source.HostPath = &apiv1.HostPathVolumeSource{Path: v.Source}
+		case v.Type == "tmpfs":
+			source.EmptyDir = &apiv1.EmptyDirVolumeSource{Medium: apiv1.StorageMediumMemory}
+		case v.Type == "volume" && v.Source == "":
+			source.EmptyDir = &apiv1.EmptyDirVolumeSource{}
+		case v.Type == "volume" && cfg.Volumes[v.Source].External.External:
+			source.PersistentVolumeClaim = &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: cfg.Volumes[v.Source].Name, ReadOnly: v.ReadOnly}
+		case v.Type == "volume":
+			claim, err := toVolumeClaim(stackName, v.Source, cfg.Volumes[v.Source])
+			if err != nil {
+				return apiv1.PodTemplateSpec{}, nil, err
+			}
+			claims = append(claims, claim)
+			container.VolumeMounts = append(container.VolumeMounts, apiv1.VolumeMount{Name: claim.Name, MountPath: v.Target, ReadOnly: v.ReadOnly})
+			continue
+		default:
+			return apiv1.PodTemplateSpec{}, nil, errors.Errorf("volumes of type %s are not supported", v.Type)
+		}
+		pod.Volumes = append(pod.Volumes, apiv1.Volume{Name: name, VolumeSource: source})
+		container.VolumeMounts = append(container.VolumeMounts, apiv1.VolumeMount{Name: name, MountPath: v.Target, ReadOnly: v.ReadOnly})
+	}
+	for i, target := range s.Tmpfs {
+		name := fmt.Sprintf("tmpfs-%d", i)
+		pod.Volumes = append(pod.Volumes, apiv1.Volume{Name: name, VolumeSource: apiv1.VolumeSource{
+			EmptyDir: &apiv1.EmptyDirVolumeSource{Medium: apiv1.StorageMediumMemory},
+		}})
+		container.VolumeMounts = append(container.VolumeMounts, apiv1.VolumeMount{Name: name, MountPath: target})
+	}
+	for i, config := range s.Configs {
+		obj, ok := cfg.Configs[config.Source]
+		if !ok {
+			return apiv1.PodTemplateSpec{}, nil, errors.Errorf("undefined config %q", config.Source)
+		}
+		name := fmt.Sprintf("config-%d", i)
+		target := config.Target
+		if target == "" {
+			target = "/" + config.Source
+		}
+		pod.Volumes = append(pod.Volumes, apiv1.Volume{Name: name, VolumeSource: apiv1.VolumeSource{
+			ConfigMap: &apiv1.ConfigMapVolumeSource{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: fileObjectName(stackName, config.Source, composetypes.FileObjectConfig(obj))},
+				Items:                []apiv1.KeyToPath{{Key: config.Source, Path: config.Source, Mode: toFileMode(config.Mode)}},
+			},
+		}})
+		container.VolumeMounts = append(container.VolumeMounts, apiv1.VolumeMount{Name: name, MountPath: target, SubPath: config.Source, ReadOnly: true})
+	}
+	for i, secret := range s.Secrets {
+		obj, ok := cfg.Secrets[secret.Source]
+		if !ok {
+			return apiv1.PodTemplateSpec{}, nil, errors.Errorf("undefined secret %q", secret.Source)
+		}
+		name := fmt.Sprintf("secret-%d", i)
+		target := secret.Target
+		if target == "" {
+			target = secret.Source
+		}
+		if !strings.HasPrefix(target, "/") {
+			target = "/run/secrets/" + target
+		}
+		pod.Volumes = append(pod.Volumes, apiv1.Volume{Name: name, VolumeSource: apiv1.VolumeSource{
+			Secret: &apiv1.SecretVolumeSource{
+				SecretName: fileObjectName(stackName, secret.Source, composetypes.FileObjectConfig(obj)),
+				Items:      []apiv1.KeyToPath{{Key: secret.Source, Path: secret.Source, Mode: toFileMode(secret.Mode)}},
+			},
+		}})
+		container.VolumeMounts = append(container.VolumeMounts, apiv1.VolumeMount{Name: name, MountPath: target, SubPath: secret.Source, ReadOnly: true})
+	}
+
+	pod.Containers = []apiv1.Container{container}
+	return apiv1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      labels.ForService(stackName, s.Name),
+			Annotations: s.Labels,
+		},
+		Spec: pod,
+	}, claims, nil
+}
+
+func toVolumeClaim(stackName, name string, volume composetypes.VolumeConfig) (apiv1.PersistentVolumeClaim, error) {
+	if volume.Driver != "" && volume.Driver != "local" {
+		return apiv1.PersistentVolumeClaim{}, errors.Errorf("volume %s: driver %s is not supported", name, volume.Driver)
+	}
+	claimName := volume.Name
+	if claimName == "" {
+		claimName = name
+	}
+	return apiv1.PersistentVolumeClaim{
+		ObjectMeta: objectMeta(dnsLabel(claimName), stackName, "", volume.Labels),
+		Spec: apiv1.PersistentVolumeClaimSpec{
+			AccessModes: []apiv1.PersistentVolumeAccessMode{apiv1.ReadWriteOnce},
+			Resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse(defaultVolumeSize)},
+			},
+		},
+	}, nil
+}
+
+func toEnv(environment map[string]*string) []apiv1.EnvVar {
+	var names []string
+	for name, value := range environment {
+		if value != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	env := make([]apiv1.EnvVar, 0, len(names))
+	for _, name := range names {
+		env = append(env, apiv1.EnvVar{Name: name, Value: *environment[name]})
+	}
+	return env
+}
+
+func toProtocol(protocol string) apiv1.Protocol {
+	if protocol == "" {
+		return apiv1.ProtocolTCP
+	}
+	return apiv1.Protocol(strings.ToUpper(protocol))
+}
+
+func toContainerPorts(s latest.ServiceConfig) []apiv1.ContainerPort {
+	var ports []apiv1.ContainerPort
+	seen := map[apiv1.ContainerPort]bool{}
+	add := func(port apiv1.ContainerPort) {
+		if !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	for _, p := range s.Ports {
+		add(apiv1.ContainerPort{ContainerPort: int32(p.Target), Protocol: toProtocol(p.Protocol)})
+	}
+	for _, p := range s.InternalPorts {
+		add(apiv1.ContainerPort{ContainerPort: p.Port, Protocol: toProtocol(string(p.Protocol))})
+	}
+	return ports
+}
+
+func toProbe(h *latest.HealthCheckConfig) *apiv1.Probe {
+	if h == nil || len(h.Test) == 0 {
+		return nil
+	}
+	var command []string
+	switch h.Test[0] {
+	case "NONE":
+		return nil
+	case "CMD":
+		command = h.Test[1:]
+	case "CMD-SHELL":
+		command = append([]string{"sh", "-c"}, h.Test[1:]...)
+	default:
+		command = h.Test
+	}
+	probe := &apiv1.Probe{Handler: apiv1.Handler{Exec: &apiv1.ExecAction{Command: command}}}
+	if h.Timeout != nil {
+		probe.TimeoutSeconds = toSeconds(*h.Timeout)
+	}
+	if h.Interval != nil {
+		probe.PeriodSeconds = toSeconds(*h.Interval)
+	}
+	if h.Retries != nil {
+		probe.FailureThreshold = int32(*h.Retries)
+	}
+	return probe
+}
+
+// toSeconds rounds a duration up to a number of seconds, which is at least 1
+func toSeconds(d time.Duration) int32 {
+	seconds := int32((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+func toResources(r latest.Resources) (apiv1.ResourceRequirements, error) {
+	limits, err := toResourceList(r.Limits)
+	if err != nil {
+		return apiv1.ResourceRequirements{}, err
+	}
+	requests, err := toResourceList(r.Reservations)
+	if err != nil {
+		return apiv1.ResourceRequirements{}, err
+	}
+	return apiv1.ResourceRequirements{Limits: limits, Requests: requests}, nil
+}
+
+func toResourceList(r *latest.Resource) (apiv1.ResourceList, error) {
+	if r == nil {
+		return nil, nil
+	}
+	list := apiv1.ResourceList{}
+	if r.NanoCPUs != "" {
+		cpus, err := resource.ParseQuantity(r.NanoCPUs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cpus %q", r.NanoCPUs)
+		}
+		list[apiv1.ResourceCPU] = cpus
+	}
+	if r.MemoryBytes != 0 {
+		list[apiv1.ResourceMemory] = *resource.NewQuantity(r.MemoryBytes, resource.BinarySI)
+	}
+	return list, nil
+}
+
+func toCapabilities(caps []string) []apiv1.Capability {
+	var capabilities []apiv1.Capability
+	for _, c := range caps {
+		capabilities = append(capabilities, apiv1.Capability(strings.TrimPrefix(c, "CAP_")))
+	}
+	return capabilities
+}
+
+// toHostAliases converts extra hosts, in the "host:ip" format
+func toHostAliases(extraHosts []string) []apiv1.HostAlias {
+	var aliases []apiv1.HostAlias
+	for _, host := range extraHosts {
+		parts := strings.SplitN(host, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		aliases = append(aliases, apiv1.HostAlias{IP: parts[1], Hostnames: []string{parts[0]}})
+	}
+	return aliases
+}
+
+func toFileMode(mode *uint32) *int32 {
+	if mode == nil {
+		return nil
+	}
+	m := int32(*mode)
+	return &m
+}
+
+func toAffinity(c *latest.Constraints) *apiv1.Affinity {
+	if c == nil {
+		return nil
+	}
+	var requirements []apiv1.NodeSelectorRequirement
+	add := func(key string, constraint *latest.Constraint) {
+		if constraint == nil {
+			return
+		}
+		operator := apiv1.NodeSelectorOpIn
+		if constraint.Operator == "!=" {
+			operator = apiv1.NodeSelectorOpNotIn
+		}
+		requirements = append(requirements, apiv1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: operator,
+			Values:   []string{constraint.Value},
+		})
+	}
+	add(nodeOSLabel, c.OperatingSystem)
+	add(nodeArchLabel, c.Architecture)
+	add(nodeHostnameLabel, c.Hostname)
+	var keys []string
+	for key := range c.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		constraint := c.MatchLabels[key]
+		add(key, &constraint)
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+	return &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+				NodeSelectorTerms: []apiv1.NodeSelectorTerm{{MatchExpressions: requirements}},
+			},
+		},
+	}
+}
+
+// toServices returns the Service that exposes a service to the other services
+// of the stack and, if it publishes ports, the LoadBalancer Service that
+// publishes them
+func toServices(stackName string, s latest.ServiceConfig) []interface{} {
+	selector := labels.ForService(stackName, s.Name)
+	internal := &apiv1.Service{
+		TypeMeta:   typeService,
+		ObjectMeta: objectMeta(dnsLabel(s.Name), stackName, s.Name, nil),
+		Spec:       apiv1.ServiceSpec{Selector: selector},
+	}
+	headless := s.InternalServiceType == latest.InternalServiceTypeHeadless ||
+		s.InternalServiceType == latest.InternalServiceTypeAuto && len(s.InternalPorts) == 0
+	if headless {
+		internal.Spec.ClusterIP = apiv1.ClusterIPNone
+	}
+	for _, p := range s.InternalPorts {
+		protocol := toProtocol(string(p.Protocol))
+		internal.Spec.Ports = append(internal.Spec.Ports, apiv1.ServicePort{
+			Name:       fmt.Sprintf("%d-%s", p.Port, strings.ToLower(string(protocol))),
+			Port:       p.Port,
+			TargetPort: intstr.FromInt(int(p.Port)),
+			Protocol:   protocol,
+		})
+	}
+	services := []interface{}{internal}
+
+	var published []apiv1.ServicePort
+	for _, p := range s.Ports {
+		if p.Published == 0 {
+			continue
+		}
+		protocol := toProtocol(p.Protocol)
+		published = append(published, apiv1.ServicePort{
+			Name:       fmt.Sprintf("%d-%s", p.Published, strings.ToLower(string(protocol))),
+			Port:       int32(p.Published),
+			TargetPort: intstr.FromInt(int(p.Target)),
+			Protocol:   protocol,
+		})
+	}
+	if len(published) > 0 {
+		services = append(services, &apiv1.Service{
+			TypeMeta:   typeService,
+			ObjectMeta: objectMeta(dnsLabel(s.Name)+"-published", stackName, s.Name, nil),
+			Spec: apiv1.ServiceSpec{
+				Type:     apiv1.ServiceTypeLoadBalancer,
+				Selector: selector,
+				Ports:    published,
+			},
+		})
+	}
+	return services
+}
@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/loader"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func loadTestdataComposefile(t *testing.T, filename string) *composetypes.Config {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", filename))
+	assert.NilError(t, err)
+	dict, err := loader.ParseYAML(data)
+	assert.NilError(t, err)
+	config, err := loader.Load(composetypes.ConfigDetails{
+		WorkingDir:  "testdata",
+		ConfigFiles: []composetypes.ConfigFile{{Filename: filename, Config: dict}},
+	})
+	assert.NilError(t, err)
+	return config
+}
+
+func TestManifests(t *testing.T) {
+	config := loadTestdataComposefile(t, "compose-to-manifests.yml")
+
+	var stderr bytes.Buffer
+	manifests, err := Manifests(&stderr, "mystack", config)
+	assert.NilError(t, err)
+	golden.Assert(t, string(manifests), "manifests.golden")
+	assert.Check(t, is.Equal("service \"db\": restart is ignored\n", stderr.String()))
+}
+
+func TestManifestsErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        composetypes.Config
+		expectedError string
+	}{
+		{
+			name: "global service with named volume",
+			config: composetypes.Config{
+				Services: composetypes.Services{{
+					Name:    "agent",
+					Deploy:  composetypes.DeployConfig{Mode: "global"},
+					Volumes: []composetypes.ServiceVolumeConfig{{Type: "volume", Source: "data", Target: "/data"}},
+				}},
+				Volumes: map[string]composetypes.VolumeConfig{"data": {}},
+			},
+			expectedError: "service agent: named volumes are not supported by global services",
+		},
+		{
+			name: "volume driver",
+			config: composetypes.Config{
+				Services: composetypes.Services{{
+					Name:    "db",
+					Volumes: []composetypes.ServiceVolumeConfig{{Type: "volume", Source: "data", Target: "/data"}},
+				}},
+				Volumes: map[string]composetypes.VolumeConfig{"data": {Driver: "nfs"}},
+			},
+			expectedError: "service db: volume data: driver nfs is not supported",
+		},
+		{
+			name: "driver secret",
+			config: composetypes.Config{
+				Secrets: map[string]composetypes.SecretConfig{"password": {Driver: "vault"}},
+			},
+			expectedError: "secret password: only file based secrets can be converted",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Manifests(ioutil.Discard, "mystack", &tc.config)
+			assert.Check(t, is.Error(err, tc.expectedError))
+		})
+	}
+}
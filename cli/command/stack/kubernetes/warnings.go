@@ -9,6 +9,7 @@ import (
 
 func warnUnsupportedFeatures(stderr io.Writer, cfg *composetypes.Config) {
 	warnForGlobalNetworks(stderr, cfg)
+	warnForTemplates(stderr, cfg)
 	for _, s := range cfg.Services {
 		warnForServiceNetworks(stderr, s)
 		warnForUnsupportedDeploymentStrategy(stderr, s)
@@ -24,6 +25,19 @@ func warnForGlobalNetworks(stderr io.Writer, config *composetypes.Config) {
 	}
 }
 
+func warnForTemplates(stderr io.Writer, config *composetypes.Config) {
+	for name, secret := range config.Secrets {
+		if secret.Template {
+			fmt.Fprintf(stderr, "secret %q: template is not supported, the file is deployed as is\n", name)
+		}
+	}
+	for name, cfg := range config.Configs {
+		if cfg.Template {
+			fmt.Fprintf(stderr, "config %q: template is not supported, the file is deployed as is\n", name)
+		}
+	}
+}
+
 func warnServicef(stderr io.Writer, service, format string, args ...interface{}) {
 	fmt.Fprintf(stderr, "service \"%s\": %s\n", service, fmt.Sprintf(format, args...))
 }
@@ -11,21 +11,39 @@ import (
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
 	"github.com/docker/cli/cli/compose/loader"
 	"github.com/docker/cli/cli/compose/schema"
 	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/opts"
 	"github.com/pkg/errors"
 )
 
 // LoadComposefile parse the composefile specified in the cli and returns its Config and version.
+// If a namespace is specified, the configs and secrets that are templates are
+// rendered for that stack.
 func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.Config, error) {
-	configDetails, err := getConfigDetails(opts.Composefiles, dockerCli.In())
+	return loadComposefile(dockerCli, opts)
+}
+
+// LoadComposefileToBuild is like LoadComposefile, but does not warn that the
+// build sections of the services are not supported.
+func LoadComposefileToBuild(dockerCli command.Cli, opts options.Deploy) (*composetypes.Config, error) {
+	return loadComposefile(dockerCli, opts, "build")
+}
+
+// loadComposefile loads the composefiles, and warns about the unsupported
+// options they use, except the supported ones
+func loadComposefile(dockerCli command.Cli, opts options.Deploy, supported ...string) (*composetypes.Config, error) {
+	configDetails, err := getConfigDetails(opts.Composefiles, opts.EnvFiles, dockerCli.In())
 	if err != nil {
 		return nil, err
 	}
 
 	dicts := getDictsFrom(configDetails.ConfigFiles)
-	config, err := loader.Load(configDetails)
+	config, err := loader.Load(configDetails, func(options *loader.Options) {
+		options.Interpolate.Strict = opts.Strict
+	})
 	if err != nil {
 		if fpe, ok := err.(*loader.ForbiddenPropertiesError); ok {
 			return nil, errors.Errorf("Compose file contains unsupported options:\n\n%s\n",
@@ -35,7 +53,12 @@ func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.
 		return nil, err
 	}
 
-	unsupportedProperties := loader.GetUnsupportedProperties(dicts...)
+	var unsupportedProperties []string
+	for _, property := range loader.GetUnsupportedProperties(dicts...) {
+		if !containsString(supported, property) {
+			unsupportedProperties = append(unsupportedProperties, property)
+		}
+	}
 	if len(unsupportedProperties) > 0 {
 		fmt.Fprintf(dockerCli.Err(), "Ignoring unsupported options: %s\n\n",
 			strings.Join(unsupportedProperties, ", "))
@@ -46,7 +69,69 @@ func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.
 		fmt.Fprintf(dockerCli.Err(), "Ignoring deprecated options:\n\n%s\n\n",
 			propertyWarnings(deprecatedProperties))
 	}
-	return config, nil
+
+	known := convert.Profiles(config)
+	for _, profile := range opts.Profiles {
+		if profile != convert.AllProfiles && !containsString(known, profile) {
+			fmt.Fprintf(dockerCli.Err(), "Profile %q is not used by any service\n", profile)
+		}
+	}
+	enabled, err := convert.ApplyProfiles(config, opts.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	for _, skipped := range skippedObjects(config, enabled) {
+		fmt.Fprintf(dockerCli.Err(), "Skipping %s, only used by services that are not enabled\n", skipped)
+	}
+	config = enabled
+	if opts.Namespace == "" {
+		return config, nil
+	}
+	return convert.RenderTemplates(convert.NewNamespace(opts.Namespace), config, configDetails.Environment)
+}
+
+// CheckComposefile checks the composefiles specified in the cli, and returns
+// the errors and warnings found in them.
+func CheckComposefile(dockerCli command.Cli, opts options.Validate) ([]loader.Issue, error) {
+	configDetails, err := getConfigDetails(opts.Composefiles, opts.EnvFiles, dockerCli.In())
+	if err != nil {
+		return nil, err
+	}
+	return loader.Check(configDetails, func(options *loader.Options) {
+		options.Interpolate.Strict = opts.Strict
+	}), nil
+}
+
+// skippedObjects returns the networks, secrets and configs of config that are
+// not in enabled, sorted by kind and name
+func skippedObjects(config, enabled *composetypes.Config) []string {
+	var skipped []string
+	for name := range config.Networks {
+		if _, ok := enabled.Networks[name]; !ok {
+			skipped = append(skipped, fmt.Sprintf("network %q", name))
+		}
+	}
+	for name := range config.Secrets {
+		if _, ok := enabled.Secrets[name]; !ok {
+			skipped = append(skipped, fmt.Sprintf("secret %q", name))
+		}
+	}
+	for name := range config.Configs {
+		if _, ok := enabled.Configs[name]; !ok {
+			skipped = append(skipped, fmt.Sprintf("config %q", name))
+		}
+	}
+	sort.Strings(skipped)
+	return skipped
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
 }
 
 func getDictsFrom(configFiles []composetypes.ConfigFile) []map[string]interface{} {
@@ -68,38 +153,62 @@ func propertyWarnings(properties map[string]string) string {
 	return strings.Join(msgs, "\n\n")
 }
 
-func getConfigDetails(composefiles []string, stdin io.Reader) (composetypes.ConfigDetails, error) {
+func getConfigDetails(composefiles []string, envFiles []string, stdin io.Reader) (composetypes.ConfigDetails, error) {
 	var details composetypes.ConfigDetails
 
 	if len(composefiles) == 0 {
 		return details, errors.New("no composefile(s)")
 	}
 
-	if composefiles[0] == "-" && len(composefiles) == 1 {
-		workingDir, err := os.Getwd()
-		if err != nil {
-			return details, err
-		}
-		details.WorkingDir = workingDir
-	} else {
-		absPath, err := filepath.Abs(composefiles[0])
-		if err != nil {
-			return details, err
-		}
-		details.WorkingDir = filepath.Dir(absPath)
-	}
-
 	var err error
+	details.WorkingDir, err = WorkingDir(composefiles)
+	if err != nil {
+		return details, err
+	}
 	details.ConfigFiles, err = loadConfigFiles(composefiles, stdin)
 	if err != nil {
 		return details, err
 	}
 	// Take the first file version (2 files can't have different version)
 	details.Version = schema.Version(details.ConfigFiles[0].Config)
-	details.Environment, err = buildEnvironment(os.Environ())
+	details.Environment, err = getEnvironment(details.WorkingDir, envFiles)
 	return details, err
 }
 
+// WorkingDir returns the directory the relative paths in the composefiles are
+// relative to: the directory of the first composefile, or the current
+// directory if the composefile is read from stdin.
+func WorkingDir(composefiles []string) (string, error) {
+	if composefiles[0] == "-" && len(composefiles) == 1 {
+		return os.Getwd()
+	}
+	absPath, err := filepath.Abs(composefiles[0])
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(absPath), nil
+}
+
+// getEnvironment returns the variables used to interpolate the Compose files.
+// Variables set in the ".env" file in the working directory are overridden by
+// those set in the env files, in order, which are overridden by those set in
+// the shell.
+func getEnvironment(workingDir string, envFiles []string) (map[string]string, error) {
+	var env []string
+	dotEnv := filepath.Join(workingDir, ".env")
+	if fi, err := os.Stat(dotEnv); err == nil && !fi.IsDir() {
+		envFiles = append([]string{dotEnv}, envFiles...)
+	}
+	for _, envFile := range envFiles {
+		vars, err := opts.ParseDotEnvFile(envFile)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, vars...)
+	}
+	return buildEnvironment(append(env, os.Environ()...))
+}
+
 func buildEnvironment(env []string) (map[string]string, error) {
 	result := make(map[string]string, len(env))
 	for _, s := range env {
@@ -146,7 +255,8 @@ func loadConfigFile(filename string, stdin io.Reader) (*composetypes.ConfigFile,
 	}
 
 	return &composetypes.ConfigFile{
-		Filename: filename,
-		Config:   config,
+		Filename:  filename,
+		Config:    config,
+		Positions: loader.ParsePositions(filename, bytes),
 	}, nil
 }
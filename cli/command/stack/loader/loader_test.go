@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 
+	composetypes "github.com/docker/cli/cli/compose/types"
+
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	"gotest.tools/fs"
@@ -21,7 +23,7 @@ services:
 	file := fs.NewFile(t, "test-get-config-details", fs.WithContent(content))
 	defer file.Remove()
 
-	details, err := getConfigDetails([]string{file.Path()}, nil)
+	details, err := getConfigDetails([]string{file.Path()}, nil, nil)
 	assert.NilError(t, err)
 	assert.Check(t, is.Equal(filepath.Dir(file.Path()), details.WorkingDir))
 	assert.Assert(t, is.Len(details.ConfigFiles, 1))
@@ -36,7 +38,7 @@ services:
   foo:
     image: alpine:3.5
 `
-	details, err := getConfigDetails([]string{"-"}, strings.NewReader(content))
+	details, err := getConfigDetails([]string{"-"}, nil, strings.NewReader(content))
 	assert.NilError(t, err)
 	cwd, err := os.Getwd()
 	assert.NilError(t, err)
@@ -45,3 +47,49 @@ services:
 	assert.Check(t, is.Equal("3.0", details.ConfigFiles[0].Config["version"]))
 	assert.Check(t, is.Len(details.Environment, len(os.Environ())))
 }
+
+func TestGetConfigDetailsEnvironment(t *testing.T) {
+	dir := fs.NewDir(t, "test-get-config-details-env",
+		fs.WithFile("docker-compose.yml", "version: \"3.0\"\n"),
+		fs.WithFile(".env", "FROM_DOT_ENV=dotenv\nOVERRIDDEN=dotenv\nQUOTED=\"a\nb\"\n"),
+		fs.WithFile("first.env", "FROM_ENV_FILE=first\nOVERRIDDEN=first\n"),
+		fs.WithFile("second.env", "OVERRIDDEN=second\nFROM_SHELL=second\n"),
+	)
+	defer dir.Remove()
+	defer os.Unsetenv("FROM_SHELL")
+	os.Setenv("FROM_SHELL", "shell")
+
+	details, err := getConfigDetails(
+		[]string{dir.Join("docker-compose.yml")},
+		[]string{dir.Join("first.env"), dir.Join("second.env")},
+		nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("dotenv", details.Environment["FROM_DOT_ENV"]))
+	assert.Check(t, is.Equal("first", details.Environment["FROM_ENV_FILE"]))
+	assert.Check(t, is.Equal("second", details.Environment["OVERRIDDEN"]))
+	assert.Check(t, is.Equal("shell", details.Environment["FROM_SHELL"]))
+	assert.Check(t, is.Equal("a\nb", details.Environment["QUOTED"]))
+}
+
+func TestGetConfigDetailsMissingEnvFile(t *testing.T) {
+	file := fs.NewFile(t, "test-get-config-details", fs.WithContent("version: \"3.0\"\n"))
+	defer file.Remove()
+
+	_, err := getConfigDetails([]string{file.Path()}, []string{"/nonexistent.env"}, nil)
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestSkippedObjects(t *testing.T) {
+	config := &composetypes.Config{
+		Networks: map[string]composetypes.NetworkConfig{"default": {}, "tools": {}},
+		Secrets:  map[string]composetypes.SecretConfig{"shared": {}, "debug_token": {}},
+		Configs:  map[string]composetypes.ConfigObjConfig{"debug_config": {}},
+	}
+	enabled := &composetypes.Config{
+		Networks: map[string]composetypes.NetworkConfig{"default": {}},
+		Secrets:  map[string]composetypes.SecretConfig{"shared": {}},
+	}
+	expected := []string{`config "debug_config"`, `network "tools"`, `secret "debug_token"`}
+	assert.Check(t, is.DeepEqual(expected, skippedObjects(config, enabled)))
+	assert.Check(t, is.Len(skippedObjects(config, config), 0))
+}
@@ -0,0 +1,41 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newLogsCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
+	var opts options.Logs
+
+	cmd := &cobra.Command{
+		Use:   "logs [OPTIONS] STACK",
+		Short: "Fetch the logs of the services of a stack",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			if !common.Orchestrator().HasSwarm() {
+				return errors.New("fetching the logs of a stack is only supported on swarm")
+			}
+			return swarm.RunLogs(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.NoColor, "no-color", false, "Do not color the output by service")
+	flags.BoolVar(&opts.NoResolve, "no-resolve", false, "Do not map IDs to Names in output")
+	flags.BoolVar(&opts.NoTaskIDs, "no-task-ids", false, "Do not include task IDs in output")
+	flags.BoolVar(&opts.NoTrunc, "no-trunc", false, "Do not truncate output")
+	flags.BoolVarP(&opts.Follow, "follow", "f", false, "Follow log output, including the services added to the stack")
+	flags.StringVar(&opts.Since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.BoolVarP(&opts.Timestamps, "timestamps", "t", false, "Show timestamps")
+	flags.StringVar(&opts.Tail, "tail", "all", "Number of lines to show from the end of the logs of each service")
+	return cmd
+}
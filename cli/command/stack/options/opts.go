@@ -1,15 +1,72 @@
 package options
 
-import "github.com/docker/cli/opts"
+import (
+	"time"
+
+	"github.com/docker/cli/opts"
+)
+
+// Build holds docker stack build options
+type Build struct {
+	Composefiles []string
+	EnvFiles     []string
+	NoCache      bool
+	Parallel     int
+	Profiles     []string
+	Pull         bool
+	Push         bool
+	Quiet        bool
+	Services     []string
+}
+
+// Config holds docker stack config options
+type Config struct {
+	Composefiles []string
+	EnvFiles     []string
+	Profiles     []string
+	Strict       bool
+}
+
+// Convert holds docker stack convert options
+type Convert struct {
+	Composefiles []string
+	EnvFiles     []string
+	Namespace    string
+	Profiles     []string
+	To           string
+}
 
 // Deploy holds docker stack deploy options
 type Deploy struct {
 	Bundlefile       string
 	Composefiles     []string
+	DryRun           string
+	EnvFiles         []string
+	Lockfile         string
 	Namespace        string
+	Ordered          bool
+	Profiles         []string
 	ResolveImage     string
 	SendRegistryAuth bool
 	Prune            bool
+	Strict           bool
+	WaitTimeout      time.Duration
+}
+
+// Diff holds docker stack diff options
+type Diff struct {
+	Composefiles []string
+	EnvFiles     []string
+	Format       string
+	Lockfile     string
+	Namespace    string
+	Profiles     []string
+	Services     []string
+}
+
+// Export holds docker stack export options
+type Export struct {
+	Namespace string
 }
 
 // List holds docker stack ls options
@@ -19,6 +76,19 @@ type List struct {
 	Namespaces    []string
 }
 
+// Logs holds docker stack logs options
+type Logs struct {
+	Follow     bool
+	Namespace  string
+	NoColor    bool
+	NoResolve  bool
+	NoTaskIDs  bool
+	NoTrunc    bool
+	Since      string
+	Tail       string
+	Timestamps bool
+}
+
 // PS holds docker stack ps options
 type PS struct {
 	Filter    opts.FilterOpt
@@ -27,6 +97,7 @@ type PS struct {
 	NoResolve bool
 	Quiet     bool
 	Format    string
+	Watch     bool
 }
 
 // Remove holds docker stack remove options
@@ -34,6 +105,15 @@ type Remove struct {
 	Namespaces []string
 }
 
+// Resolve holds docker stack resolve options
+type Resolve struct {
+	Composefiles []string
+	Namespace    string
+	Format       string
+	NoTrunc      bool
+	Lockfile     string
+}
+
 // Services holds docker stack services options
 type Services struct {
 	Quiet     bool
@@ -41,3 +121,10 @@ type Services struct {
 	Filter    opts.FilterOpt
 	Namespace string
 }
+
+// Validate holds docker stack validate options
+type Validate struct {
+	Composefiles []string
+	EnvFiles     []string
+	Strict       bool
+}
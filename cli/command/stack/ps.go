@@ -32,6 +32,8 @@ func newPsCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
 	flags.VarP(&opts.Filter, "filter", "f", "Filter output based on conditions provided")
 	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display task IDs")
 	flags.StringVar(&opts.Format, "format", "", "Pretty-print tasks using a Go template")
+	flags.BoolVar(&opts.Watch, "watch", false, "Keep updating the tasks and the history of their states")
+	flags.SetAnnotation("watch", "swarm", nil)
 	kubernetes.AddNamespaceFlag(flags)
 	return cmd
 }
@@ -0,0 +1,54 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newResolveCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
+	var opts options.Resolve
+
+	cmd := &cobra.Command{
+		Use:   "resolve [OPTIONS] STACK",
+		Short: "Resolve the images of a stack to their digests and report drift",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			var config *composetypes.Config
+			switch {
+			case len(opts.Composefiles) == 0 && !common.Orchestrator().HasSwarm():
+				return errors.New("resolving a running stack is only supported on swarm, use --compose-file to resolve a Compose file")
+			case len(opts.Composefiles) != 0:
+				var err error
+				config, err = loader.LoadComposefile(dockerCli, options.Deploy{
+					Composefiles: opts.Composefiles,
+					Namespace:    opts.Namespace,
+					// resolve the images of all the services, so that any
+					// of them can be deployed from the lockfile
+					Profiles: []string{convert.AllProfiles},
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return swarm.RunResolve(dockerCli, opts, config)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin, to resolve instead of the running stack`)
+	flags.StringVar(&opts.Format, "format", "", "Pretty-print the report using a Go template")
+	flags.BoolVar(&opts.NoTrunc, "no-trunc", false, "Do not truncate digests")
+	flags.StringVar(&opts.Lockfile, "lockfile", "", "Write the resolved digests to a lockfile")
+	return cmd
+}
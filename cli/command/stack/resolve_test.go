@@ -0,0 +1,118 @@
+package stack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/command/image/lockfile"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/cli/internal/test"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+var (
+	nginxDigest    = digest.FromString("nginx:1.15")
+	oldNginxDigest = digest.FromString("nginx:1.15-old")
+	redisDigest    = digest.FromString("redis")
+)
+
+func newResolveRegistryClient() *fakeRegistryClient {
+	return &fakeRegistryClient{digests: map[string]ocispec.Descriptor{
+		"nginx:1.15":   {MediaType: schema2.MediaTypeManifest, Digest: nginxDigest},
+		"redis:latest": {MediaType: schema2.MediaTypeManifest, Digest: redisDigest},
+	}}
+}
+
+func stackService(name, image, specImage string) swarm.Service {
+	return *Service(
+		ServiceName("mystack_"+name),
+		ServiceLabels(map[string]string{
+			convert.LabelNamespace: "mystack",
+			convert.LabelImage:     image,
+		}),
+		ServiceImage(specImage),
+	)
+}
+
+func TestStackResolveRunningStack(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{
+				stackService("web", "nginx:1.15", "nginx:1.15@"+oldNginxDigest.String()),
+				stackService("cache", "redis", "redis:latest@"+redisDigest.String()),
+				stackService("proxy", "nginx:1.15", "nginx:1.15"),
+			}, nil
+		},
+	})
+	cli.SetRegistryClient(newResolveRegistryClient())
+	cmd := newResolveCommand(cli, &commonOptions{orchestrator: "swarm"})
+	cmd.SetArgs([]string{"mystack"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "stack-resolve-running.golden")
+}
+
+func TestStackResolveComposefile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stack-resolve-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	composefile := filepath.Join(dir, "docker-compose.yml")
+	assert.NilError(t, ioutil.WriteFile(composefile, []byte(`version: "3.7"
+services:
+  web:
+    image: nginx:1.15@`+oldNginxDigest.String()+`
+  cache:
+    image: redis
+`), 0644))
+	lockPath := filepath.Join(dir, "stack.lock")
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(newResolveRegistryClient())
+	cmd := newResolveCommand(cli, &commonOptions{orchestrator: "swarm"})
+	cmd.SetArgs([]string{"--compose-file", composefile, "--lockfile", lockPath, "--format", "{{.Service}} {{.Status}}", "mystack"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("cache unpinned\nweb drift\n", cli.OutBuffer().String()))
+
+	lock, err := lockfile.Load(lockPath)
+	assert.NilError(t, err)
+	pinned, ok := lock.Pin("nginx:1.15@" + oldNginxDigest.String())
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal("nginx:1.15@"+nginxDigest.String(), pinned))
+	pinned, ok = lock.Pin("redis")
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal("redis:latest@"+redisDigest.String(), pinned))
+}
+
+func TestStackResolveRunningStackRequiresSwarm(t *testing.T) {
+	cmd := newResolveCommand(test.NewFakeCli(&fakeClient{}), &commonOptions{orchestrator: "kubernetes"})
+	cmd.SetArgs([]string{"mystack"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "only supported on swarm")
+}
+
+func TestStackResolveRegistryError(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{stackService("db", "postgres:11", "postgres:11")}, nil
+		},
+	})
+	cli.SetRegistryClient(newResolveRegistryClient())
+	cmd := newResolveCommand(cli, &commonOptions{orchestrator: "swarm"})
+	cmd.SetArgs([]string{"mystack"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Error(t, cmd.Execute(), "failed to resolve postgres:11: manifest unknown")
+	assert.Check(t, strings.TrimSpace(cli.OutBuffer().String()) == "")
+}
@@ -2,6 +2,8 @@ package swarm
 
 import (
 	"context"
+	"io"
+	"io/ioutil"
 	"strings"
 
 	"github.com/docker/cli/cli/compose/convert"
@@ -34,6 +36,9 @@ type fakeClient struct {
 	nodeListFunc       func(options types.NodeListOptions) ([]swarm.Node, error)
 	taskListFunc       func(options types.TaskListOptions) ([]swarm.Task, error)
 	nodeInspectWithRaw func(ref string) (swarm.Node, []byte, error)
+	taskInspectFunc    func(taskID string) (swarm.Task, []byte, error)
+	serviceInspectFunc func(serviceID string) (swarm.Service, []byte, error)
+	serviceLogsFunc    func(serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 
 	serviceUpdateFunc func(serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error)
 
@@ -135,6 +140,27 @@ func (cli *fakeClient) NodeInspectWithRaw(ctx context.Context, ref string) (swar
 	return swarm.Node{}, nil, nil
 }
 
+func (cli *fakeClient) TaskInspectWithRaw(ctx context.Context, taskID string) (swarm.Task, []byte, error) {
+	if cli.taskInspectFunc != nil {
+		return cli.taskInspectFunc(taskID)
+	}
+	return swarm.Task{}, nil, nil
+}
+
+func (cli *fakeClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+	if cli.serviceInspectFunc != nil {
+		return cli.serviceInspectFunc(serviceID)
+	}
+	return swarm.Service{}, nil, nil
+}
+
+func (cli *fakeClient) ServiceLogs(ctx context.Context, serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	if cli.serviceLogsFunc != nil {
+		return cli.serviceLogsFunc(serviceID, options)
+	}
+	return ioutil.NopCloser(strings.NewReader("")), nil
+}
+
 func (cli *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
 	if cli.serviceUpdateFunc != nil {
 		return cli.serviceUpdateFunc(serviceID, version, service, options)
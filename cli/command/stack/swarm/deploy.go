@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/docker/cli/cli/command"
+	servicecli "github.com/docker/cli/cli/command/service"
 	"github.com/docker/cli/cli/command/stack/options"
 	"github.com/docker/cli/cli/compose/convert"
 	composetypes "github.com/docker/cli/cli/compose/types"
@@ -28,6 +29,12 @@ func RunDeploy(dockerCli command.Cli, opts options.Deploy, cfg *composetypes.Con
 	if err := validateResolveImageFlag(dockerCli, &opts); err != nil {
 		return err
 	}
+	if err := servicecli.ValidateDryRun(opts.DryRun); err != nil {
+		return err
+	}
+	if opts.DryRun == servicecli.DryRunPlacement {
+		return simulatePlacement(ctx, dockerCli, opts, cfg)
+	}
 
 	return deployCompose(ctx, dockerCli, opts, cfg)
 }
@@ -78,3 +85,47 @@ func pruneServices(ctx context.Context, dockerCli command.Cli, namespace convert
 	}
 	removeServices(ctx, dockerCli, pruneServices)
 }
+
+// pruneSecrets removes the secrets of the stack that are not in secrets, such
+// as the previous versions of the secrets of which the content changed
+func pruneSecrets(ctx context.Context, dockerCli command.Cli, namespace convert.Namespace, secrets []swarm.SecretSpec) {
+	oldSecrets, err := getStackSecrets(ctx, dockerCli.Client(), namespace.Name())
+	if err != nil {
+		fmt.Fprintf(dockerCli.Err(), "Failed to list secrets: %s\n", err)
+		return
+	}
+
+	keep := map[string]bool{}
+	for _, secret := range secrets {
+		keep[secret.Name] = true
+	}
+	pruneSecrets := []swarm.Secret{}
+	for _, secret := range oldSecrets {
+		if !keep[secret.Spec.Name] {
+			pruneSecrets = append(pruneSecrets, secret)
+		}
+	}
+	removeSecrets(ctx, dockerCli, pruneSecrets)
+}
+
+// pruneConfigs removes the configs of the stack that are not in configs, such
+// as the previous versions of the configs of which the content changed
+func pruneConfigs(ctx context.Context, dockerCli command.Cli, namespace convert.Namespace, configs []swarm.ConfigSpec) {
+	oldConfigs, err := getStackConfigs(ctx, dockerCli.Client(), namespace.Name())
+	if err != nil {
+		fmt.Fprintf(dockerCli.Err(), "Failed to list configs: %s\n", err)
+		return
+	}
+
+	keep := map[string]bool{}
+	for _, config := range configs {
+		keep[config.Name] = true
+	}
+	pruneConfigs := []swarm.Config{}
+	for _, config := range oldConfigs {
+		if !keep[config.Spec.Name] {
+			pruneConfigs = append(pruneConfigs, config)
+		}
+	}
+	removeConfigs(ctx, dockerCli, pruneConfigs)
+}
@@ -40,6 +40,11 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts options.Depl
 		return err
 	}
 
+	config, err := convert.VersionFileObjects(namespace, config)
+	if err != nil {
+		return err
+	}
+
 	secrets, err := convert.Secrets(namespace, config.Secrets)
 	if err != nil {
 		return err
@@ -60,7 +65,22 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts options.Depl
 	if err != nil {
 		return err
 	}
-	return deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
+	if opts.Ordered {
+		err = deployServicesOrdered(ctx, dockerCli, config, services, namespace, opts)
+	} else {
+		err = deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.Prune {
+		// the services no longer use the previous versions of the secrets
+		// and configs, which can now be removed
+		pruneSecrets(ctx, dockerCli, namespace, secrets)
+		pruneConfigs(ctx, dockerCli, namespace, configs)
+	}
+	return nil
 }
 
 func getServicesDeclaredNetworks(serviceConfigs []composetypes.ServiceConfig) map[string]struct{} {
@@ -26,6 +26,25 @@ func TestPruneServices(t *testing.T) {
 	assert.Check(t, is.DeepEqual(buildObjectIDs([]string{objectName("foo", "remove")}), client.removedServices))
 }
 
+func TestPruneSecretsAndConfigs(t *testing.T) {
+	ctx := context.Background()
+	namespace := convert.NewNamespace("foo")
+	client := &fakeClient{
+		secrets: []string{objectName("foo", "db-1234"), objectName("foo", "db-5678"), objectName("bar", "db-1234")},
+		configs: []string{objectName("foo", "app-1234"), objectName("foo", "unused")},
+	}
+	dockerCli := test.NewFakeCli(client)
+
+	pruneSecrets(ctx, dockerCli, namespace, []swarm.SecretSpec{
+		{Annotations: swarm.Annotations{Name: objectName("foo", "db-5678")}},
+	})
+	pruneConfigs(ctx, dockerCli, namespace, []swarm.ConfigSpec{
+		{Annotations: swarm.Annotations{Name: objectName("foo", "app-1234")}},
+	})
+	assert.Check(t, is.DeepEqual(buildObjectIDs([]string{objectName("foo", "db-1234")}), client.removedSecrets))
+	assert.Check(t, is.DeepEqual(buildObjectIDs([]string{objectName("foo", "unused")}), client.removedConfigs))
+}
+
 // TestServiceUpdateResolveImageChanged tests that the service's
 // image digest, and "ForceUpdate" is preserved if the image did not change in
 // the compose file
@@ -0,0 +1,159 @@
+package swarm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	servicecli "github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+)
+
+// RunDiff is the swarm implementation of docker stack diff
+func RunDiff(dockerCli command.Cli, opts options.Diff, config *composetypes.Config) error {
+	ctx := context.Background()
+	namespace := convert.NewNamespace(opts.Namespace)
+
+	config, err := selectServices(config, opts.Services)
+	if err != nil {
+		return err
+	}
+	config, err = convert.VersionFileObjects(namespace, config)
+	if err != nil {
+		return err
+	}
+	specs, err := convert.Services(namespace, config, dockerCli.Client())
+	if err != nil {
+		return err
+	}
+	services, err := getStackServices(ctx, dockerCli.Client(), opts.Namespace)
+	if err != nil {
+		return err
+	}
+	networkList, err := dockerCli.Client().NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+	networks := map[string]types.NetworkResource{}
+	for _, network := range networkList {
+		networks[network.ID] = network
+	}
+
+	format := opts.Format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	diffCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: servicecli.NewDiffFormat(format, true),
+	}
+	return servicecli.DiffFormatWrite(diffCtx, diffServices(namespace, specs, services, networks))
+}
+
+// selectServices returns a copy of config with only the given services, or
+// config itself if no service is given
+func selectServices(config *composetypes.Config, names []string) (*composetypes.Config, error) {
+	if len(names) == 0 {
+		return config, nil
+	}
+	services := map[string]composetypes.ServiceConfig{}
+	for _, service := range config.Services {
+		services[service.Name] = service
+	}
+	result := *config
+	result.Services = nil
+	for _, name := range names {
+		service, ok := services[name]
+		if !ok {
+			return nil, errors.Errorf("service %s is not defined in the Compose files", name)
+		}
+		result.Services = append(result.Services, service)
+	}
+	return &result, nil
+}
+
+// diffServices compares the services of a stack with the specs they would be
+// updated to by a deploy. A service that would be created is reported as a
+// change of its name. The services that are not in the specs are left out, as
+// a deploy only removes them with --prune. networks are the networks of the
+// swarm, by ID.
+func diffServices(namespace convert.Namespace, specs map[string]swarm.ServiceSpec, services []swarm.Service, networks map[string]types.NetworkResource) []servicecli.SpecChange {
+	existing := map[string]swarm.Service{}
+	for _, service := range services {
+		existing[service.Spec.Name] = service
+	}
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := []servicecli.SpecChange{}
+	for _, name := range names {
+		spec := specs[name]
+		service, ok := existing[namespace.Scope(name)]
+		if !ok {
+			changes = append(changes, servicecli.SpecChange{Service: name, Field: "Name", New: spec.Name})
+			continue
+		}
+		// like docker stack deploy, keep the digest of an image that did not
+		// change, and the force update counter
+		if spec.TaskTemplate.ContainerSpec.Image == service.Spec.Labels[convert.LabelImage] {
+			spec.TaskTemplate.ContainerSpec.Image = service.Spec.TaskTemplate.ContainerSpec.Image
+		}
+		spec.TaskTemplate.ForceUpdate = service.Spec.TaskTemplate.ForceUpdate
+
+		for _, change := range servicecli.DiffSpecs(fromDaemonSpec(service.Spec, networks), withTaskNetworks(spec)) {
+			change.Service = name
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// fromDaemonSpec returns a copy of a service spec returned by the daemon in
+// the form of the specs converted from a Compose file: networks are referenced
+// by name rather than by ID, and the default isolation is left empty.
+func fromDaemonSpec(spec swarm.ServiceSpec, networks map[string]types.NetworkResource) swarm.ServiceSpec {
+	spec = withTaskNetworks(spec)
+	spec.TaskTemplate.Networks = networkNames(spec.TaskTemplate.Networks, networks)
+	if containerSpec := spec.TaskTemplate.ContainerSpec; containerSpec != nil && containerSpec.Isolation.IsDefault() {
+		copied := *containerSpec
+		copied.Isolation = ""
+		spec.TaskTemplate.ContainerSpec = &copied
+	}
+	return spec
+}
+
+// withTaskNetworks returns a copy of spec with the networks set in the
+// deprecated ServiceSpec.Networks moved to TaskTemplate.Networks, as the
+// daemon does
+func withTaskNetworks(spec swarm.ServiceSpec) swarm.ServiceSpec {
+	if len(spec.TaskTemplate.Networks) == 0 {
+		spec.TaskTemplate.Networks = spec.Networks
+	}
+	spec.Networks = nil
+	return spec
+}
+
+// networkNames returns a copy of attachments in which the networks known by
+// ID are referenced by name
+func networkNames(attachments []swarm.NetworkAttachmentConfig, networks map[string]types.NetworkResource) []swarm.NetworkAttachmentConfig {
+	if attachments == nil {
+		return nil
+	}
+	named := make([]swarm.NetworkAttachmentConfig, len(attachments))
+	for i, attachment := range attachments {
+		if network, ok := networks[attachment.Target]; ok {
+			attachment.Target = network.Name
+		}
+		named[i] = attachment
+	}
+	return named
+}
@@ -0,0 +1,84 @@
+package swarm
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRunDiff(t *testing.T) {
+	level := "info"
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{Name: "web", Image: "nginx:1.17", Environment: composetypes.MappingWithEquals{"LEVEL": &level}},
+			{Name: "worker", Image: "busybox"},
+		},
+	}
+	client := &fakeClient{}
+	namespace := convert.NewNamespace("test")
+	specs, err := convert.Services(namespace, config, client)
+	assert.NilError(t, err)
+
+	// the spec of the deployed service is returned by the daemon: the image
+	// was resolved to its digest, networks are referenced by ID, and the
+	// isolation is set. Its environment differs from the Compose file.
+	web := specs["web"]
+	containerSpec := *web.TaskTemplate.ContainerSpec
+	containerSpec.Image = "nginx:1.17@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	containerSpec.Env = []string{"LEVEL=debug"}
+	containerSpec.Isolation = container.IsolationDefault
+	web.TaskTemplate.ContainerSpec = &containerSpec
+	web.TaskTemplate.Networks = []swarm.NetworkAttachmentConfig{{Target: "default-id", Aliases: []string{"web"}}}
+	web.TaskTemplate.ForceUpdate = 2
+	web.Networks = nil
+	client.networkListFunc = func(options types.NetworkListOptions) ([]types.NetworkResource, error) {
+		return []types.NetworkResource{{ID: "default-id", Name: "test_default"}, {ID: "ingress-id", Name: "ingress"}}, nil
+	}
+	client.serviceListFunc = func(options types.ServiceListOptions) ([]swarm.Service, error) {
+		return []swarm.Service{
+			{ID: "web-id", Spec: web},
+			{ID: "db-id", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "test_db"}}},
+		}, nil
+	}
+
+	cli := test.NewFakeCli(client)
+	assert.NilError(t, RunDiff(cli, options.Diff{Namespace: "test"}, config))
+	assert.Check(t, is.Equal(`SERVICE             FIELD                            OLD                 NEW
+web                 TaskTemplate.ContainerSpec.Env   LEVEL=debug         
+web                 TaskTemplate.ContainerSpec.Env                       LEVEL=info
+worker              Name                                                 test_worker
+`, cli.OutBuffer().String()))
+}
+
+func TestRunDiffServices(t *testing.T) {
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{Name: "web", Image: "nginx"},
+			{Name: "worker", Image: "busybox", Secrets: []composetypes.ServiceSecretConfig{{Source: "undefined"}}},
+		},
+	}
+
+	// the services that are not compared are not converted
+	client := &fakeClient{
+		networkListFunc: func(options types.NetworkListOptions) ([]types.NetworkResource, error) {
+			return nil, nil
+		},
+	}
+	cli := test.NewFakeCli(client)
+	assert.NilError(t, RunDiff(cli, options.Diff{Namespace: "test", Services: []string{"web"}}, config))
+	assert.Check(t, is.Equal(`SERVICE             FIELD               OLD                 NEW
+web                 Name                                    test_web
+`, cli.OutBuffer().String()))
+	assert.Check(t, is.Len(config.Services, 2))
+
+	err := RunDiff(test.NewFakeCli(client), options.Diff{Namespace: "test", Services: []string{"db"}}, config)
+	assert.Check(t, is.Error(err, "service db is not defined in the Compose files"))
+}
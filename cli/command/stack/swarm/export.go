@@ -0,0 +1,52 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/docker/api/types"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// RunExport is the swarm implementation of docker stack export
+func RunExport(dockerCli command.Cli, opts options.Export) error {
+	ctx := context.Background()
+	client := dockerCli.Client()
+
+	services, err := getStackServices(ctx, client, opts.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		fmt.Fprintf(dockerCli.Err(), "Nothing found in stack: %s\n", opts.Namespace)
+		return nil
+	}
+	networkList, err := client.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+	networks := map[string]types.NetworkResource{}
+	for _, network := range networkList {
+		networks[network.ID] = network
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Spec.Name < services[j].Spec.Name })
+	export := convert.NewExport()
+	namespace := convert.NewNamespace(opts.Namespace)
+	for _, service := range services {
+		export.AddService(namespace, service, networks)
+	}
+	for _, warning := range export.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+	out, err := yaml.Marshal(export.Config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(dockerCli.Out(), string(out))
+	return nil
+}
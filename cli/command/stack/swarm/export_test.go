@@ -0,0 +1,65 @@
+package swarm
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func TestRunExport(t *testing.T) {
+	replicas := uint64(2)
+	labels := map[string]string{convert.LabelNamespace: "test"}
+	client := &fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{
+				{Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "test_web", Labels: labels},
+					TaskTemplate: swarm.TaskSpec{
+						ContainerSpec: &swarm.ContainerSpec{Image: "nginx", Labels: labels},
+						Networks:      []swarm.NetworkAttachmentConfig{{Target: "back-id", Aliases: []string{"web"}}},
+					},
+					Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+					EndpointSpec: &swarm.EndpointSpec{Ports: []swarm.PortConfig{
+						{Protocol: swarm.PortConfigProtocolTCP, TargetPort: 80, PublishedPort: 8080, PublishMode: swarm.PortConfigPublishModeIngress},
+					}},
+				}},
+				{Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "test_db", Labels: labels},
+					TaskTemplate: swarm.TaskSpec{
+						ContainerSpec: &swarm.ContainerSpec{
+							Image:     "postgres",
+							Labels:    labels,
+							DNSConfig: &swarm.DNSConfig{Options: []string{"ndots:2"}},
+						},
+						Networks: []swarm.NetworkAttachmentConfig{{Target: "back-id", Aliases: []string{"db"}}},
+					},
+				}},
+			}, nil
+		},
+		networkListFunc: func(options types.NetworkListOptions) ([]types.NetworkResource, error) {
+			return []types.NetworkResource{
+				{ID: "back-id", Name: "test_back", Driver: "overlay", Labels: labels},
+			}, nil
+		},
+	}
+	cli := test.NewFakeCli(client)
+
+	assert.NilError(t, RunExport(cli, options.Export{Namespace: "test"}))
+	golden.Assert(t, cli.OutBuffer().String(), "export.golden")
+	assert.Check(t, is.Equal(`service "db": dns options cannot be expressed in a Compose file`+"\n", cli.ErrBuffer().String()))
+}
+
+func TestRunExportEmptyStack(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+
+	assert.NilError(t, RunExport(cli, options.Export{Namespace: "test"}))
+	assert.Check(t, is.Equal("", cli.OutBuffer().String()))
+	assert.Check(t, is.Equal("Nothing found in stack: test\n", cli.ErrBuffer().String()))
+}
@@ -0,0 +1,283 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/cli/service/logs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/pkg/errors"
+)
+
+// logsPollInterval is the time between two checks for the services added to
+// a stack, when following its logs
+var logsPollInterval = 2 * time.Second
+
+// logColors are the ANSI colors of the prefixes of the services
+var logColors = []int{36, 33, 32, 35, 34, 96, 93, 92, 95, 94}
+
+// RunLogs is the swarm implementation of docker stack logs
+func RunLogs(dockerCli command.Cli, opts options.Logs) error {
+	return runLogs(context.Background(), dockerCli, opts)
+}
+
+func runLogs(ctx context.Context, dockerCli command.Cli, opts options.Logs) error {
+	client := dockerCli.Client()
+
+	services, err := getStackServices(ctx, client, opts.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		fmt.Fprintf(dockerCli.Err(), "Nothing found in stack: %s\n", opts.Namespace)
+		return nil
+	}
+
+	l := newStackLogs(dockerCli, opts)
+	l.start(ctx, services)
+	for opts.Follow {
+		select {
+		case <-ctx.Done():
+			l.wg.Wait()
+			return nil
+		case <-time.After(logsPollInterval):
+		}
+		services, err := getStackServices(ctx, client, opts.Namespace)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return err
+		}
+		l.start(ctx, services)
+	}
+	l.wg.Wait()
+
+	if len(l.failed) > 0 {
+		sort.Strings(l.failed)
+		return errors.Errorf("failed to get the logs of services: %s", strings.Join(l.failed, ", "))
+	}
+	return nil
+}
+
+// stackLogs merges the logs of the services of a stack
+type stackLogs struct {
+	dockerCli command.Cli
+	opts      options.Logs
+	namespace convert.Namespace
+	colors    bool
+	streaming map[string]bool
+	wg        sync.WaitGroup
+
+	// mu guards the failed services, and the writes to the output
+	mu     sync.Mutex
+	failed []string
+
+	// namesMu guards the names of the tasks. It is not held while a name is
+	// looked up, so that a slow lookup does not stall the other services.
+	namesMu sync.Mutex
+	names   map[string]string
+}
+
+func newStackLogs(dockerCli command.Cli, opts options.Logs) *stackLogs {
+	return &stackLogs{
+		dockerCli: dockerCli,
+		opts:      opts,
+		namespace: convert.NewNamespace(opts.Namespace),
+		colors:    !opts.NoColor && dockerCli.Out().IsTerminal(),
+		streaming: map[string]bool{},
+		names:     map[string]string{},
+	}
+}
+
+// start streams the logs of the services that are not streamed yet
+func (l *stackLogs) start(ctx context.Context, services []swarm.Service) {
+	sort.Slice(services, func(i, j int) bool { return services[i].Spec.Name < services[j].Spec.Name })
+	for _, service := range services {
+		if l.streaming[service.ID] {
+			continue
+		}
+		l.streaming[service.ID] = true
+		l.wg.Add(1)
+		go func(service swarm.Service) {
+			defer l.wg.Done()
+			if err := l.stream(ctx, service); err != nil && ctx.Err() == nil {
+				name := l.namespace.Descope(service.Spec.Name)
+				l.mu.Lock()
+				defer l.mu.Unlock()
+				fmt.Fprintf(l.dockerCli.Err(), "%s: %s\n", name, err)
+				l.failed = append(l.failed, name)
+			}
+		}(service)
+	}
+}
+
+func (l *stackLogs) stream(ctx context.Context, service swarm.Service) error {
+	name := l.namespace.Descope(service.Spec.Name)
+	tty := service.Spec.TaskTemplate.ContainerSpec != nil && service.Spec.TaskTemplate.ContainerSpec.TTY
+
+	body, err := l.dockerCli.Client().ServiceLogs(ctx, service.ID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      l.opts.Since,
+		Timestamps: l.opts.Timestamps,
+		Follow:     l.opts.Follow,
+		Tail:       l.opts.Tail,
+		// the details identify the task of each line, but tty logs are
+		// not multiplexed, and only get the name of the service
+		Details: !tty,
+	})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if tty {
+		w := &ttyLogWriter{logs: l, service: name}
+		_, err = io.Copy(w, body)
+		w.flush()
+		return err
+	}
+	stdout := &serviceLogWriter{logs: l, ctx: ctx, service: name, w: l.dockerCli.Out()}
+	stderr := &serviceLogWriter{logs: l, ctx: ctx, service: name, w: l.dockerCli.Err()}
+	_, err = stdcopy.StdCopy(stdout, stderr, body)
+	return err
+}
+
+// write writes a line of the logs with its prefix, colored after the service
+func (l *stackLogs) write(w io.Writer, timestamp []byte, service, prefix string, message []byte) {
+	output := []byte{}
+	if len(timestamp) > 0 {
+		output = append(output, timestamp...)
+		output = append(output, ' ')
+	}
+	if l.colors {
+		h := fnv.New32a()
+		h.Write([]byte(service))
+		prefix = fmt.Sprintf("\x1b[%dm%s\x1b[0m", logColors[h.Sum32()%uint32(len(logColors))], prefix)
+	}
+	output = append(output, prefix+" | "...)
+	output = append(output, message...)
+	w.Write(output)
+}
+
+// taskName returns the name of the task of a line of the logs, like
+// `docker service logs` does
+func (l *stackLogs) taskName(ctx context.Context, service, taskID, nodeID string) (string, error) {
+	l.namesMu.Lock()
+	name, ok := l.names[taskID]
+	l.namesMu.Unlock()
+	if ok {
+		return name, nil
+	}
+
+	task, _, err := l.dockerCli.Client().TaskInspectWithRaw(ctx, taskID)
+	if err != nil {
+		return "", err
+	}
+	nodeName, err := idresolver.New(l.dockerCli.Client(), l.opts.NoResolve).Resolve(ctx, swarm.Node{}, nodeID)
+	if err != nil {
+		return "", err
+	}
+
+	name = fmt.Sprintf("%s.%d", service, task.Slot)
+	if !l.opts.NoTaskIDs {
+		if l.opts.NoTrunc {
+			name += "." + taskID
+		} else {
+			name += "." + stringid.TruncateID(taskID)
+		}
+	}
+	name += "@" + nodeName
+
+	l.namesMu.Lock()
+	l.names[taskID] = name
+	l.namesMu.Unlock()
+	return name, nil
+}
+
+// serviceLogWriter prefixes the lines of the logs of a service with the name
+// of their task. As in `docker service logs`, each write is a whole line.
+type serviceLogWriter struct {
+	logs    *stackLogs
+	ctx     context.Context
+	service string
+	w       io.Writer
+}
+
+func (sw *serviceLogWriter) Write(buf []byte) (int, error) {
+	// a line is made of an optional timestamp, the details and the message
+	numParts := 2
+	if sw.logs.opts.Timestamps {
+		numParts++
+	}
+	parts := bytes.SplitN(buf, []byte(" "), numParts)
+	if len(parts) != numParts {
+		return 0, errors.Errorf("invalid context in log message: %v", string(buf))
+	}
+	var timestamp []byte
+	if sw.logs.opts.Timestamps {
+		timestamp = parts[0]
+	}
+	details, err := logs.ParseLogDetails(string(parts[numParts-2]))
+	if err != nil {
+		return 0, err
+	}
+
+	prefix, err := sw.logs.taskName(sw.ctx, sw.service, details["com.docker.swarm.task.id"], details["com.docker.swarm.node.id"])
+	if err != nil {
+		return 0, err
+	}
+	sw.logs.mu.Lock()
+	defer sw.logs.mu.Unlock()
+	sw.logs.write(sw.w, timestamp, sw.service, prefix, parts[numParts-1])
+	return len(buf), nil
+}
+
+// ttyLogWriter prefixes the lines of the logs of a service that uses a tty
+// with the name of the service
+type ttyLogWriter struct {
+	logs    *stackLogs
+	service string
+	buf     []byte
+}
+
+func (tw *ttyLogWriter) Write(p []byte) (int, error) {
+	tw.buf = append(tw.buf, p...)
+	for {
+		i := bytes.IndexByte(tw.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		tw.writeLine(tw.buf[:i+1])
+		tw.buf = tw.buf[i+1:]
+	}
+}
+
+// flush writes the last line, if it does not end with a newline
+func (tw *ttyLogWriter) flush() {
+	if len(tw.buf) > 0 {
+		tw.writeLine(append(tw.buf, '\n'))
+		tw.buf = nil
+	}
+}
+
+func (tw *ttyLogWriter) writeLine(line []byte) {
+	tw.logs.mu.Lock()
+	defer tw.logs.mu.Unlock()
+	tw.logs.write(tw.logs.dockerCli.Out(), nil, tw.service, tw.service, line)
+}
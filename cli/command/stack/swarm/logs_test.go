@@ -0,0 +1,194 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func stackService(id string, tty bool) swarm.Service {
+	return swarm.Service{ID: id, Spec: swarm.ServiceSpec{
+		Annotations:  swarm.Annotations{Name: "test_" + id, Labels: map[string]string{convert.LabelNamespace: "test"}},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{TTY: tty}},
+	}}
+}
+
+// multiplexedLogs returns logs as the daemon sends them for services without
+// a tty, with the details identifying the task of each line
+func multiplexedLogs(taskID string, stdout, stderr []string) io.ReadCloser {
+	buf := &bytes.Buffer{}
+	details := "com.docker.swarm.node.id=node1,com.docker.swarm.service.id=service,com.docker.swarm.task.id=" + taskID + " "
+	for _, line := range stdout {
+		stdcopy.NewStdWriter(buf, stdcopy.Stdout).Write([]byte(details + line + "\n"))
+	}
+	for _, line := range stderr {
+		stdcopy.NewStdWriter(buf, stdcopy.Stderr).Write([]byte(details + line + "\n"))
+	}
+	return ioutil.NopCloser(buf)
+}
+
+func sortedLines(s string) []string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+func TestRunLogs(t *testing.T) {
+	client := &fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{stackService("web", false), stackService("console", true), stackService("cache", false)}, nil
+		},
+		serviceLogsFunc: func(serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+			assert.Check(t, is.Equal("10", options.Tail))
+			assert.Check(t, is.Equal("1h", options.Since))
+			switch serviceID {
+			case "web":
+				assert.Check(t, options.Details)
+				return multiplexedLogs("task1", []string{"listening", "GET /"}, []string{"warning"}), nil
+			case "console":
+				assert.Check(t, !options.Details)
+				return ioutil.NopCloser(strings.NewReader("login:\r\nwelcome")), nil
+			default:
+				return nil, errors.New("logs unavailable")
+			}
+		},
+		taskInspectFunc: func(taskID string) (swarm.Task, []byte, error) {
+			return swarm.Task{ID: taskID, Slot: 1}, nil, nil
+		},
+	}
+	cli := test.NewFakeCli(client)
+
+	err := RunLogs(cli, options.Logs{Namespace: "test", Tail: "10", Since: "1h", NoResolve: true})
+	assert.Check(t, is.Error(err, "failed to get the logs of services: cache"))
+	assert.Check(t, is.DeepEqual([]string{
+		"console | login:\r",
+		"console | welcome",
+		"web.1.task1@node1 | GET /",
+		"web.1.task1@node1 | listening",
+	}, sortedLines(cli.OutBuffer().String())))
+	assert.Check(t, is.DeepEqual([]string{
+		"cache: logs unavailable",
+		"web.1.task1@node1 | warning",
+	}, sortedLines(cli.ErrBuffer().String())))
+}
+
+func TestRunLogsNoTaskIDsTimestamps(t *testing.T) {
+	client := &fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{stackService("web", false)}, nil
+		},
+		serviceLogsFunc: func(serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+			assert.Check(t, options.Timestamps)
+			buf := &bytes.Buffer{}
+			stdcopy.NewStdWriter(buf, stdcopy.Stdout).Write([]byte("2019-05-01T10:00:00.000000000Z com.docker.swarm.node.id=node1,com.docker.swarm.service.id=web,com.docker.swarm.task.id=task1 listening\n"))
+			return ioutil.NopCloser(buf), nil
+		},
+		taskInspectFunc: func(taskID string) (swarm.Task, []byte, error) {
+			return swarm.Task{ID: taskID, Slot: 2}, nil, nil
+		},
+	}
+	cli := test.NewFakeCli(client)
+
+	assert.NilError(t, RunLogs(cli, options.Logs{Namespace: "test", NoTaskIDs: true, NoResolve: true, Timestamps: true}))
+	assert.Check(t, is.Equal("2019-05-01T10:00:00.000000000Z web.2@node1 | listening\n", cli.OutBuffer().String()))
+}
+
+// notifyingReader closes its done channel once its content is read, and the
+// writes of the lines read are complete
+type notifyingReader struct {
+	io.Reader
+	done chan struct{}
+}
+
+func (r *notifyingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		close(r.done)
+	}
+	return n, err
+}
+
+func (r *notifyingReader) Close() error {
+	return nil
+}
+
+func TestRunLogsSlowTaskLookup(t *testing.T) {
+	consoleWritten := make(chan struct{})
+	client := &fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{stackService("web", false), stackService("console", true)}, nil
+		},
+		serviceLogsFunc: func(serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+			if serviceID == "console" {
+				return &notifyingReader{Reader: strings.NewReader("login:\n"), done: consoleWritten}, nil
+			}
+			return multiplexedLogs("task1", []string{"listening"}, nil), nil
+		},
+		taskInspectFunc: func(taskID string) (swarm.Task, []byte, error) {
+			// the lookup of the task of web only completes once the logs of
+			// console are written
+			select {
+			case <-consoleWritten:
+				return swarm.Task{ID: taskID, Slot: 1}, nil, nil
+			case <-time.After(5 * time.Second):
+				return swarm.Task{}, nil, errors.New("the logs of console were not written during the lookup")
+			}
+		},
+	}
+	cli := test.NewFakeCli(client)
+
+	assert.NilError(t, RunLogs(cli, options.Logs{Namespace: "test", NoTaskIDs: true, NoResolve: true}))
+	assert.Check(t, is.DeepEqual([]string{"console | login:", "web.1@node1 | listening"}, sortedLines(cli.OutBuffer().String())))
+}
+
+func TestRunLogsFollowAddedServices(t *testing.T) {
+	defer func(interval time.Duration) { logsPollInterval = interval }(logsPollInterval)
+	logsPollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lists := 0
+	client := &fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			lists++
+			if lists < 3 {
+				return []swarm.Service{stackService("web", true)}, nil
+			}
+			return []swarm.Service{stackService("web", true), stackService("worker", true)}, nil
+		},
+		serviceLogsFunc: func(serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+			assert.Check(t, options.Follow)
+			if serviceID == "worker" {
+				defer cancel()
+			}
+			return ioutil.NopCloser(strings.NewReader("started\n")), nil
+		},
+	}
+	cli := test.NewFakeCli(client)
+
+	assert.NilError(t, runLogs(ctx, cli, options.Logs{Namespace: "test", Follow: true}))
+	assert.Check(t, is.DeepEqual([]string{"web | started", "worker | started"}, sortedLines(cli.OutBuffer().String())))
+}
+
+func TestRunLogsEmptyStack(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+
+	assert.NilError(t, RunLogs(cli, options.Logs{Namespace: "test"}))
+	assert.Check(t, is.Equal("Nothing found in stack: test\n", cli.ErrBuffer().String()))
+}
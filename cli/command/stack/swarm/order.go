@@ -0,0 +1,195 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	servicecli "github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+)
+
+// deployServicesOrdered deploys the services in waves, following the order of
+// their dependencies. Each wave is made of the services of which all the
+// dependencies were deployed in a previous wave, and the deploy waits for the
+// services of a wave to be running and healthy before deploying the next one.
+func deployServicesOrdered(
+	ctx context.Context,
+	dockerCli command.Cli,
+	config *composetypes.Config,
+	services map[string]swarm.ServiceSpec,
+	namespace convert.Namespace,
+	opts options.Deploy,
+) error {
+	waves, err := dependencyWaves(config.Services)
+	if err != nil {
+		return err
+	}
+	for i, wave := range waves {
+		waveServices := make(map[string]swarm.ServiceSpec, len(wave))
+		for _, name := range wave {
+			waveServices[name] = services[name]
+		}
+		if err := deployServices(ctx, dockerCli, waveServices, namespace, opts.SendRegistryAuth, opts.ResolveImage); err != nil {
+			return err
+		}
+		if i == len(waves)-1 {
+			break
+		}
+		if err := waitOnServices(ctx, dockerCli, namespace, wave, opts.WaitTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dependencyWaves sorts the services in waves, such that each service only
+// depends on services of the previous waves. The services of each wave are
+// sorted by name.
+func dependencyWaves(services []composetypes.ServiceConfig) ([][]string, error) {
+	dependencies := make(map[string][]string, len(services))
+	for _, service := range services {
+		dependencies[service.Name] = nil
+	}
+	for _, service := range services {
+		for _, dependency := range service.DependsOn {
+			if _, ok := dependencies[dependency]; !ok {
+				return nil, errors.Errorf("service %q depends on undefined service %q", service.Name, dependency)
+			}
+			dependencies[service.Name] = append(dependencies[service.Name], dependency)
+		}
+	}
+
+	var waves [][]string
+	deployed := map[string]bool{}
+	for len(deployed) < len(dependencies) {
+		var wave []string
+		for name, deps := range dependencies {
+			if !deployed[name] && allDeployed(deps, deployed) {
+				wave = append(wave, name)
+			}
+		}
+		if len(wave) == 0 {
+			return nil, errors.Errorf("services have circular dependencies: %s", strings.Join(findCycle(dependencies, deployed), " -> "))
+		}
+		sort.Strings(wave)
+		for _, name := range wave {
+			deployed[name] = true
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+func allDeployed(names []string, deployed map[string]bool) bool {
+	for _, name := range names {
+		if !deployed[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// findCycle returns a cycle of dependencies between the services that are
+// not deployed, starting and ending with the same service
+func findCycle(dependencies map[string][]string, deployed map[string]bool) []string {
+	var names []string
+	for name := range dependencies {
+		if !deployed[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// every service that is not deployed depends on another one that is not
+	// deployed, so following the dependencies eventually loops
+	var path []string
+	seen := map[string]int{}
+	for name := names[0]; ; {
+		if i, ok := seen[name]; ok {
+			return append(path[i:], name)
+		}
+		seen[name] = len(path)
+		path = append(path, name)
+		deps := append([]string{}, dependencies[name]...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if !deployed[dep] {
+				name = dep
+				break
+			}
+		}
+	}
+}
+
+// waitOnServices waits for the services to be running and healthy, as the
+// tasks of a service with a healthcheck are only reported running once they
+// are healthy. An interrupt fails the wait, so that the next wave is not
+// deployed.
+func waitOnServices(ctx context.Context, dockerCli command.Cli, namespace convert.Namespace, names []string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, name := range names {
+		service, _, err := dockerCli.Client().ServiceInspectWithRaw(ctx, namespace.Scope(name), types.ServiceInspectOptions{})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(dockerCli.Out(), "Waiting for service %s to be ready\n", namespace.Scope(name))
+		interrupted, err := waitOnService(ctx, dockerCli, service.ID)
+		if interrupted {
+			return errors.Errorf("interrupted while waiting for service %s to be ready, the next services were not deployed", namespace.Scope(name))
+		}
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return errors.Errorf("service %s was not ready after %s", namespace.Scope(name), timeout)
+			}
+			return errors.Wrapf(err, "service %s failed to start", namespace.Scope(name))
+		}
+	}
+	return nil
+}
+
+// waitOnService waits for a service to converge, and returns whether the wait
+// was interrupted. The progress of the service stops without error on an
+// interrupt, so the interrupt is caught here too.
+func waitOnService(ctx context.Context, dockerCli command.Cli, serviceID string) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigint := make(chan os.Signal, 1)
+	signal.Notify(sigint, os.Interrupt)
+	done := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	go func() {
+		select {
+		case <-sigint:
+			cancel()
+			interrupted <- true
+		case <-done:
+			// once signal.Stop returns, an interrupt received during the
+			// wait is in the channel
+			select {
+			case <-sigint:
+				interrupted <- true
+			default:
+				interrupted <- false
+			}
+		}
+	}()
+
+	err := servicecli.WaitOnService(ctx, dockerCli, serviceID, false)
+	signal.Stop(sigint)
+	close(done)
+	return <-interrupted, err
+}
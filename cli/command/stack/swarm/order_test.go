@@ -0,0 +1,84 @@
+package swarm
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestDependencyWaves(t *testing.T) {
+	waves, err := dependencyWaves([]composetypes.ServiceConfig{
+		{Name: "web", DependsOn: []string{"api", "cache"}},
+		{Name: "api", DependsOn: []string{"db"}},
+		{Name: "db"},
+		{Name: "cache"},
+		{Name: "worker", DependsOn: []string{"db"}},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([][]string{
+		{"cache", "db"},
+		{"api", "worker"},
+		{"web"},
+	}, waves))
+}
+
+func TestDependencyWavesWithoutDependencies(t *testing.T) {
+	waves, err := dependencyWaves([]composetypes.ServiceConfig{{Name: "b"}, {Name: "a"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([][]string{{"a", "b"}}, waves))
+}
+
+func TestDependencyWavesCycle(t *testing.T) {
+	_, err := dependencyWaves([]composetypes.ServiceConfig{
+		{Name: "db"},
+		{Name: "api", DependsOn: []string{"db", "auth"}},
+		{Name: "auth", DependsOn: []string{"users"}},
+		{Name: "users", DependsOn: []string{"api"}},
+	})
+	assert.Check(t, is.Error(err, "services have circular dependencies: api -> auth -> users -> api"))
+}
+
+func TestDependencyWavesUndefinedService(t *testing.T) {
+	_, err := dependencyWaves([]composetypes.ServiceConfig{
+		{Name: "api", DependsOn: []string{"db"}},
+	})
+	assert.Check(t, is.Error(err, `service "api" depends on undefined service "db"`))
+}
+
+func TestWaitOnServicesInterrupted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts cannot be sent on Windows")
+	}
+	replicas := uint64(1)
+	inspects := 0
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectFunc: func(serviceID string) (swarm.Service, []byte, error) {
+			// the progress of the service is being watched from the second
+			// inspect on, and the service never converges
+			inspects++
+			if inspects == 2 {
+				process, err := os.FindProcess(os.Getpid())
+				assert.NilError(t, err)
+				assert.NilError(t, process.Signal(os.Interrupt))
+			}
+			return swarm.Service{
+				ID: "db-id",
+				Spec: swarm.ServiceSpec{
+					Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+				},
+			}, nil, nil
+		},
+	})
+
+	err := waitOnServices(context.Background(), cli, convert.NewNamespace("test"), []string{"db", "web"}, time.Minute)
+	assert.Check(t, is.Error(err, "interrupted while waiting for service test_db to be ready, the next services were not deployed"))
+}
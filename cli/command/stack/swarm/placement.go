@@ -0,0 +1,83 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/docker/cli/cli/command"
+	servicecli "github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+)
+
+// simulatePlacement prints the simulated placement of the tasks of each
+// service of a stack on the nodes of the swarm, without deploying the stack
+func simulatePlacement(ctx context.Context, dockerCli command.Cli, opts options.Deploy, config *composetypes.Config) error {
+	namespace := convert.NewNamespace(opts.Namespace)
+
+	config, err := convert.VersionFileObjects(namespace, config)
+	if err != nil {
+		return err
+	}
+	specs, err := convert.Services(namespace, config, placementClient{dockerCli.Client()})
+	if err != nil {
+		return err
+	}
+	nodes, err := dockerCli.Client().NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := dockerCli.Out()
+	for i, name := range names {
+		spec := specs[name]
+		if opts.ResolveImage != ResolveImageNever {
+			servicecli.ResolvePlatforms(ctx, dockerCli, &spec, opts.SendRegistryAuth)
+		}
+		report, err := servicecli.SimulatePlacement(spec, nodes)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "Service %s:\n", name)
+		servicecli.PrintPlacement(out, report)
+	}
+	return nil
+}
+
+// placementClient is the client the services of a stack are converted with to
+// simulate their placement. The secrets and configs of the stack may not be
+// created yet, and do not affect the placement, so each one that is looked up
+// is stubbed with an object of which the ID is its name.
+type placementClient struct {
+	client.APIClient
+}
+
+func (c placementClient) SecretList(ctx context.Context, options types.SecretListOptions) ([]swarm.Secret, error) {
+	secrets := []swarm.Secret{}
+	for _, name := range options.Filters.Get("name") {
+		secrets = append(secrets, swarm.Secret{ID: name, Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: name}}})
+	}
+	return secrets, nil
+}
+
+func (c placementClient) ConfigList(ctx context.Context, options types.ConfigListOptions) ([]swarm.Config, error) {
+	configs := []swarm.Config{}
+	for _, name := range options.Filters.Get("name") {
+		configs = append(configs, swarm.Config{ID: name, Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: name}}})
+	}
+	return configs, nil
+}
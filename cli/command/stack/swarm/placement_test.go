@@ -0,0 +1,73 @@
+package swarm
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+	"gotest.tools/golden"
+)
+
+func TestRunDeployDryRunPlacement(t *testing.T) {
+	file := fs.NewFile(t, "placement", fs.WithContent("content"))
+	defer file.Remove()
+
+	replicas := uint64(3)
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{
+				Name:  "web",
+				Image: "nginx",
+				Deploy: composetypes.DeployConfig{
+					Replicas:  &replicas,
+					Placement: composetypes.Placement{Constraints: []string{"node.role == worker"}},
+				},
+				Secrets: []composetypes.ServiceSecretConfig{{Source: "password"}},
+				Configs: []composetypes.ServiceConfigObjConfig{{Source: "site"}, {Source: "external"}},
+			},
+			{Name: "agent", Image: "busybox", Deploy: composetypes.DeployConfig{Mode: "global"}},
+		},
+		Secrets: map[string]composetypes.SecretConfig{
+			"password": {File: file.Path()},
+		},
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"site":     {File: file.Path()},
+			"external": {Name: "external", External: composetypes.External{External: true}},
+		},
+	}
+	node := func(id, hostname string, role swarm.NodeRole) swarm.Node {
+		return swarm.Node{
+			ID:          id,
+			Spec:        swarm.NodeSpec{Role: role, Availability: swarm.NodeAvailabilityActive},
+			Description: swarm.NodeDescription{Hostname: hostname},
+			Status:      swarm.NodeStatus{State: swarm.NodeStateReady},
+		}
+	}
+	// the fake client has no methods to create objects, and lists no secrets
+	// or configs: the deploy would fail if it was not only simulated
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func(options types.NodeListOptions) ([]swarm.Node, error) {
+			return []swarm.Node{
+				node("m1", "manager-1", swarm.NodeRoleManager),
+				node("w1", "worker-1", swarm.NodeRoleWorker),
+				node("w2", "worker-2", swarm.NodeRoleWorker),
+			}, nil
+		},
+	})
+	opts := options.Deploy{Namespace: "test", ResolveImage: ResolveImageNever, DryRun: "placement"}
+	assert.NilError(t, RunDeploy(cli, opts, config))
+
+	golden.Assert(t, cli.OutBuffer().String(), "stack-deploy-dry-run-placement.golden")
+}
+
+func TestRunDeployDryRunInvalid(t *testing.T) {
+	opts := options.Deploy{Namespace: "test", ResolveImage: ResolveImageNever, DryRun: "all"}
+	err := RunDeploy(test.NewFakeCli(&fakeClient{}), opts, &composetypes.Config{})
+	assert.Check(t, is.Error(err, `invalid --dry-run value "all": only "placement" is supported`))
+}
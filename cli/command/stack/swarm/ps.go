@@ -9,6 +9,8 @@ import (
 	"github.com/docker/cli/cli/command/stack/options"
 	"github.com/docker/cli/cli/command/task"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
 )
 
 // RunPS is the swarm implementation of docker stack ps
@@ -17,6 +19,21 @@ func RunPS(dockerCli command.Cli, opts options.PS) error {
 
 	ctx := context.Background()
 	client := dockerCli.Client()
+
+	if opts.Watch {
+		if opts.Quiet {
+			return errors.New("--watch cannot be used with --quiet")
+		}
+		format := opts.Format
+		if len(format) == 0 {
+			format = task.DefaultFormat(dockerCli.ConfigFile(), false)
+		}
+		list := func(ctx context.Context) ([]swarm.Task, error) {
+			return client.TaskList(ctx, types.TaskListOptions{Filters: filter})
+		}
+		return task.Watch(ctx, dockerCli, list, idresolver.New(client, opts.NoResolve), !opts.NoTrunc, format)
+	}
+
 	tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: filter})
 	if err != nil {
 		return err
@@ -0,0 +1,140 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/lockfile"
+	"github.com/docker/cli/cli/command/stack/formatter"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/client"
+	digest "github.com/opencontainers/go-digest"
+	"vbom.ml/util/sortorder"
+)
+
+// serviceImage is the image reference of a service, and the digest the
+// service is pinned to
+type serviceImage struct {
+	service string
+	image   string
+	pinned  digest.Digest
+}
+
+// RunResolve is the swarm implementation of docker stack resolve. The images
+// of the services in config are resolved, or those of the running stack if
+// config is nil.
+func RunResolve(dockerCli command.Cli, opts options.Resolve, config *composetypes.Config) error {
+	ctx := context.Background()
+
+	var (
+		images []serviceImage
+		err    error
+	)
+	if config != nil {
+		images, err = getComposeServiceImages(config)
+	} else {
+		images, err = getStackServiceImages(ctx, dockerCli.Client(), opts.Namespace)
+	}
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		fmt.Fprintf(dockerCli.Err(), "Nothing found in stack: %s\n", opts.Namespace)
+		return nil
+	}
+
+	registryClient := dockerCli.RegistryClient(false)
+	lock := lockfile.New()
+	statuses := make([]formatter.ImageStatus, 0, len(images))
+	for _, img := range images {
+		resolved, ok := lock.Images[img.image]
+		if !ok {
+			resolved, err = lockfile.Resolve(ctx, registryClient, img.image)
+			if err != nil {
+				return err
+			}
+			lock.Images[img.image] = resolved
+		}
+		status := formatter.ImageStatus{
+			Service: img.service,
+			Image:   img.image,
+			Pinned:  img.pinned,
+			Current: resolved.Digest,
+		}
+		for _, p := range resolved.Platforms {
+			status.Platforms = append(status.Platforms, p.Digest)
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return sortorder.NaturalLess(statuses[i].Service, statuses[j].Service)
+	})
+
+	format := opts.Format
+	if len(format) == 0 || format == formatter.TableFormatKey {
+		format = formatter.ImageStatusTableFormat
+	}
+	statusCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: formatter.Format(format),
+		Trunc:  !opts.NoTrunc,
+	}
+	if err := formatter.ImageStatusWrite(statusCtx, statuses); err != nil {
+		return err
+	}
+	if opts.Lockfile != "" {
+		return lock.Save(opts.Lockfile)
+	}
+	return nil
+}
+
+// getComposeServiceImages returns the images of the services in config. A
+// service is pinned if its image reference includes a digest.
+func getComposeServiceImages(config *composetypes.Config) ([]serviceImage, error) {
+	images := []serviceImage{}
+	for _, service := range config.Services {
+		if service.Image == "" {
+			continue
+		}
+		pinned, err := lockfile.PinnedDigest(service.Image)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, serviceImage{service: service.Name, image: service.Image, pinned: pinned})
+	}
+	return images, nil
+}
+
+// getStackServiceImages returns the images of the services of a running
+// stack, as written in the Compose file they were deployed from. A service is
+// pinned to the digest in its spec, which is set when the image is resolved
+// on deploy.
+func getStackServiceImages(ctx context.Context, apiClient client.APIClient, namespace string) ([]serviceImage, error) {
+	services, err := getStackServices(ctx, apiClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	images := []serviceImage{}
+	for _, service := range services {
+		specImage := service.Spec.TaskTemplate.ContainerSpec.Image
+		image := service.Spec.Labels[convert.LabelImage]
+		if image == "" {
+			image = specImage
+		}
+		pinned, err := lockfile.PinnedDigest(specImage)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, serviceImage{
+			service: strings.TrimPrefix(service.Spec.Name, namespace+"_"),
+			image:   image,
+			pinned:  pinned,
+		})
+	}
+	return images, nil
+}
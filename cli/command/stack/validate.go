@@ -0,0 +1,53 @@
+package stack
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	composeloader "github.com/docker/cli/cli/compose/loader"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCommand(dockerCli command.Cli) *cobra.Command {
+	var opts options.Validate
+
+	cmd := &cobra.Command{
+		Use:   "validate [OPTIONS]",
+		Short: "Validate Compose files and list their errors and warnings",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runValidate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.StringArrayVar(&opts.EnvFiles, "env-file", nil, "Read in a file of environment variables to interpolate the Compose files with")
+	flags.BoolVar(&opts.Strict, "strict", false, "Report variables referenced without a default value that are not set")
+	return cmd
+}
+
+func runValidate(dockerCli command.Cli, opts options.Validate) error {
+	if len(opts.Composefiles) == 0 {
+		return errors.New("Please specify a Compose file (with --compose-file).")
+	}
+	issues, err := loader.CheckComposefile(dockerCli, opts)
+	if err != nil {
+		return err
+	}
+	errs := 0
+	for _, issue := range issues {
+		fmt.Fprintln(dockerCli.Out(), issue)
+		if issue.Severity == composeloader.SeverityError {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return cli.StatusError{StatusCode: 1, Status: fmt.Sprintf("Compose file is invalid: %d error(s) found", errs)}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package stack
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func TestValidateWithoutComposeFile(t *testing.T) {
+	cmd := newValidateCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "Please specify a Compose file")
+}
+
+func TestValidateInvalidComposeFile(t *testing.T) {
+	dockerCli := test.NewFakeCli(&fakeClient{})
+	dockerCli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader(`version: "3.8"
+services:
+  web:
+    image: nginx:${TAG}
+    restart: always
+    ports:
+      - 80:80
+      - target: "443"
+  db:
+    image: postgres
+    container_name: db
+    volume:
+      - data:/var/lib/postgresql/data
+`))))
+	cmd := newValidateCommand(dockerCli)
+	cmd.SetArgs([]string{"--compose-file", "-", "--strict"})
+	cmd.SetOutput(ioutil.Discard)
+	err := cmd.Execute()
+	assert.Check(t, is.DeepEqual(cli.StatusError{StatusCode: 1, Status: "Compose file is invalid: 3 error(s) found"}, err))
+	golden.Assert(t, dockerCli.OutBuffer().String(), "stack-validate-invalid.golden")
+}
+
+func TestValidateValidComposeFile(t *testing.T) {
+	dockerCli := test.NewFakeCli(&fakeClient{})
+	dockerCli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader(`version: "3.8"
+services:
+  web:
+    image: nginx
+`))))
+	cmd := newValidateCommand(dockerCli)
+	cmd.SetArgs([]string{"--compose-file", "-"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("", dockerCli.OutBuffer().String()))
+}
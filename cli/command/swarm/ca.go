@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -24,6 +25,10 @@ type caOptions struct {
 	rotate     bool
 	detach     bool
 	quiet      bool
+
+	inspect       bool
+	format        string
+	expiryWarning time.Duration
 }
 
 func newCACommand(dockerCli command.Cli) *cobra.Command {
@@ -47,6 +52,10 @@ func newCACommand(dockerCli command.Cli) *cobra.Command {
 
 	flags.BoolVarP(&opts.detach, "detach", "d", false, "Exit immediately instead of waiting for the root rotation to converge")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress progress output")
+
+	flags.BoolVar(&opts.inspect, flagInspect, false, "Display the certificates of the swarm and their expiry, and warn about due rotations")
+	flags.StringVar(&opts.format, flagFormat, "", "Format the report of --inspect using the given Go template")
+	flags.DurationVar(&opts.expiryWarning, flagExpiryWarning, 30*24*time.Hour, "Warn about root CA certificates that expire within this duration (ns|us|ms|s|m|h)")
 	return cmd
 }
 
@@ -59,6 +68,18 @@ func runCA(dockerCli command.Cli, flags *pflag.FlagSet, opts caOptions) error {
 		return err
 	}
 
+	if opts.inspect {
+		if opts.rotate {
+			return errors.Errorf("--%s cannot be used with --%s", flagInspect, flagRotate)
+		}
+		return runCAInspect(ctx, dockerCli, swarmInspect, opts)
+	}
+	for _, f := range []string{flagFormat, flagExpiryWarning} {
+		if flags.Changed(f) {
+			return errors.Errorf("--%s requires --%s", f, flagInspect)
+		}
+	}
+
 	if !opts.rotate {
 		for _, f := range []string{flagCACert, flagCAKey, flagCertExpiry, flagExternalCA} {
 			if flags.Changed(f) {
@@ -0,0 +1,261 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/templates"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+)
+
+// base36DigestLen is the length of the digest of the root CA in join tokens
+const base36DigestLen = 50
+
+// caReport is the report of `docker swarm ca --inspect` on the certificates of
+// the swarm
+type caReport struct {
+	RootCA                 []certificateInfo
+	RootRotationInProgress bool
+	NodeCertExpiry         time.Duration
+	ExternalCAs            []string
+	JoinTokensMatchRootCA  bool
+	Nodes                  []nodeTLSInfo
+	Warnings               []string
+}
+
+type certificateInfo struct {
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// nodeTLSInfo is the TLS information of a node. The API does not expose the
+// expiry of the certificates of the nodes, which renew them by themselves.
+type nodeTLSInfo struct {
+	ID       string
+	Hostname string
+	Issuer   string
+	// TrustsRootCA is whether the node trusts the current root CA
+	TrustsRootCA bool
+	// IssuedByCurrentCA is whether the certificate of the node was issued
+	// by the current CA of the swarm
+	IssuedByCurrentCA bool
+}
+
+func runCAInspect(ctx context.Context, dockerCli command.Cli, info swarm.Swarm, opts caOptions) error {
+	nodes, err := dockerCli.Client().NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+	report, err := newCAReport(info, nodes, time.Now(), opts.expiryWarning)
+	if err != nil {
+		return err
+	}
+
+	if opts.format != "" {
+		tmpl, err := templates.Parse(opts.format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64,
+				Status: "Template parsing error: " + err.Error()}
+		}
+		err = tmpl.Execute(dockerCli.Out(), report)
+		dockerCli.Out().Write([]byte{'\n'})
+		return err
+	}
+
+	printCAReport(dockerCli.Out(), report)
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(dockerCli.Err(), "WARNING: %s\n", warning)
+	}
+	return nil
+}
+
+// newCAReport builds the report of the certificates of the swarm, and warns
+// about the rotations that are due, or that did not complete, at a time
+func newCAReport(info swarm.Swarm, nodes []swarm.Node, now time.Time, expiryWarning time.Duration) (caReport, error) {
+	tlsInfo := info.ClusterInfo.TLSInfo
+	if tlsInfo.TrustRoot == "" {
+		return caReport{}, errors.New("No CA information available")
+	}
+	rootCA, err := parseCertificates(tlsInfo.TrustRoot)
+	if err != nil {
+		return caReport{}, errors.Wrap(err, "invalid root CA")
+	}
+
+	caConfig := info.Spec.CAConfig
+	report := caReport{
+		RootCA:                 []certificateInfo{},
+		RootRotationInProgress: info.RootRotationInProgress,
+		NodeCertExpiry:         caConfig.NodeCertExpiry,
+		ExternalCAs:            []string{},
+		JoinTokensMatchRootCA:  joinTokenMatches(info.JoinTokens.Worker, tlsInfo.TrustRoot) && joinTokenMatches(info.JoinTokens.Manager, tlsInfo.TrustRoot),
+		Nodes:                  []nodeTLSInfo{},
+		Warnings:               []string{},
+	}
+	for _, externalCA := range caConfig.ExternalCAs {
+		report.ExternalCAs = append(report.ExternalCAs, externalCA.URL)
+	}
+
+	for _, cert := range rootCA {
+		report.RootCA = append(report.RootCA, certificateInfo{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+		})
+		switch notAfter := cert.NotAfter.Format(time.RFC3339); {
+		case now.After(cert.NotAfter):
+			report.Warnings = append(report.Warnings, fmt.Sprintf("the root CA certificate %s expired on %s: rotate it with `docker swarm ca --rotate`", cert.Subject, notAfter))
+		case cert.NotAfter.Sub(now) < expiryWarning:
+			report.Warnings = append(report.Warnings, fmt.Sprintf("the root CA certificate %s expires on %s: rotate it with `docker swarm ca --rotate`", cert.Subject, notAfter))
+		case caConfig.NodeCertExpiry > 0 && cert.NotAfter.Sub(now) < caConfig.NodeCertExpiry:
+			report.Warnings = append(report.Warnings, fmt.Sprintf("the root CA certificate %s expires on %s, before the node certificates issued from now: rotate it with `docker swarm ca --rotate`", cert.Subject, notAfter))
+		}
+	}
+	if !report.JoinTokensMatchRootCA {
+		report.Warnings = append(report.Warnings, "the join tokens are not for the current root CA: rotate them with `docker swarm join-token --rotate`")
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Description.Hostname < nodes[j].Description.Hostname })
+	for _, node := range nodes {
+		nodeInfo := node.Description.TLSInfo
+		issuer, err := parseSubject(nodeInfo.CertIssuerSubject)
+		if err != nil {
+			return caReport{}, errors.Wrapf(err, "invalid issuer of node %s", node.ID)
+		}
+		n := nodeTLSInfo{
+			ID:                node.ID,
+			Hostname:          node.Description.Hostname,
+			Issuer:            issuer,
+			TrustsRootCA:      nodeInfo.TrustRoot == tlsInfo.TrustRoot,
+			IssuedByCurrentCA: bytes.Equal(nodeInfo.CertIssuerPublicKey, tlsInfo.CertIssuerPublicKey),
+		}
+		report.Nodes = append(report.Nodes, n)
+
+		// during a rotation of the root CA, the nodes renew their
+		// certificates, and then trust the new root CA
+		if info.RootRotationInProgress {
+			continue
+		}
+		if !n.TrustsRootCA {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("node %s (%s) does not trust the current root CA", n.Hostname, n.ID))
+		}
+		if !n.IssuedByCurrentCA {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("the certificate of node %s (%s) is not issued by the current CA", n.Hostname, n.ID))
+		}
+	}
+	return report, nil
+}
+
+func printCAReport(out io.Writer, report caReport) {
+	for _, cert := range report.RootCA {
+		fmt.Fprintln(out, "Root CA:")
+		fmt.Fprintln(out, " Subject:", cert.Subject)
+		fmt.Fprintln(out, " Issuer:", cert.Issuer)
+		fmt.Fprintln(out, " Not Before:", cert.NotBefore.Format(time.RFC3339))
+		fmt.Fprintln(out, " Not After:", cert.NotAfter.Format(time.RFC3339))
+	}
+	fmt.Fprintln(out, "Root Rotation In Progress:", report.RootRotationInProgress)
+	fmt.Fprintln(out, "Node Certificate Expiry:", report.NodeCertExpiry)
+	if len(report.ExternalCAs) > 0 {
+		fmt.Fprintln(out, "External CAs:")
+		for _, url := range report.ExternalCAs {
+			fmt.Fprintln(out, "", url)
+		}
+	}
+	fmt.Fprintln(out, "Join Tokens Match Root CA:", report.JoinTokensMatchRootCA)
+
+	if len(report.Nodes) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "Nodes:")
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, " ID\tHOSTNAME\tISSUER\tTRUSTS ROOT CA\tISSUED BY CURRENT CA")
+	for _, node := range report.Nodes {
+		fmt.Fprintf(w, " %s\t%s\t%s\t%t\t%t\n", node.ID, node.Hostname, node.Issuer, node.TrustsRootCA, node.IssuedByCurrentCA)
+	}
+	w.Flush()
+}
+
+// parseCertificates parses the certificates of a PEM bundle
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certs, nil
+}
+
+// parseSubject returns the distinguished name of a raw ASN.1 subject
+func parseSubject(raw []byte) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var rdn pkix.RDNSequence
+	if _, err := asn1.Unmarshal(raw, &rdn); err != nil {
+		return "", err
+	}
+	var name pkix.Name
+	name.FillFromRDNSequence(&rdn)
+	return name.String(), nil
+}
+
+// joinTokenMatches returns whether a join token is for a root CA. Join tokens
+// hold the SHA-256 digest of the root CA in base 36, before their secret.
+func joinTokenMatches(token, trustRoot string) bool {
+	if token == "" {
+		return true
+	}
+	parts := strings.Split(token, "-")
+	if len(parts) < 4 || parts[0] != "SWMTKN" {
+		return false
+	}
+	return parts[len(parts)-2] == rootCADigest(trustRoot)
+}
+
+// rootCADigest returns the digest of a root CA, as it is written in the join
+// tokens
+func rootCADigest(trustRoot string) string {
+	sum := sha256.Sum256([]byte(trustRoot))
+	var digest big.Int
+	digest.SetString(hex.EncodeToString(sum[:]), 16)
+	text := digest.Text(36)
+	if len(text) < base36DigestLen {
+		text = strings.Repeat("0", base36DigestLen-len(text)) + text
+	}
+	return text
+}
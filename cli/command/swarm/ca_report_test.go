@@ -0,0 +1,166 @@
+package swarm
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func caReportSwarm(t *testing.T) (swarm.Swarm, []swarm.Node) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(cert)))
+	rootCA, err := x509.ParseCertificate(block.Bytes)
+	assert.NilError(t, err)
+
+	tlsInfo := swarm.TLSInfo{
+		TrustRoot:           cert,
+		CertIssuerSubject:   rootCA.RawSubject,
+		CertIssuerPublicKey: rootCA.RawSubjectPublicKeyInfo,
+	}
+	info := swarm.Swarm{
+		ClusterInfo: swarm.ClusterInfo{
+			Spec:    swarm.Spec{CAConfig: swarm.CAConfig{NodeCertExpiry: 90 * 24 * time.Hour}},
+			TLSInfo: tlsInfo,
+		},
+		JoinTokens: swarm.JoinTokens{
+			Worker:  "SWMTKN-1-" + rootCADigest(cert) + "-0123456789abcdefghijklmno",
+			Manager: "SWMTKN-1-" + rootCADigest(cert) + "-onmlkjihgfedcba9876543210",
+		},
+	}
+	nodes := []swarm.Node{
+		{ID: "node-2", Description: swarm.NodeDescription{Hostname: "worker", TLSInfo: swarm.TLSInfo{TrustRoot: "old root", CertIssuerSubject: rootCA.RawSubject}}},
+		{ID: "node-1", Description: swarm.NodeDescription{Hostname: "manager", TLSInfo: tlsInfo}},
+	}
+	return info, nodes
+}
+
+func TestCAReport(t *testing.T) {
+	info, nodes := caReportSwarm(t)
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	report, err := newCAReport(info, nodes, now, 30*24*time.Hour)
+	assert.NilError(t, err)
+
+	subject := "CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US"
+	expected := caReport{
+		RootCA: []certificateInfo{{
+			Subject:   subject,
+			Issuer:    subject,
+			NotBefore: time.Date(2018, 7, 2, 21, 29, 18, 0, time.UTC),
+			NotAfter:  time.Date(3017, 11, 2, 21, 29, 18, 0, time.UTC),
+		}},
+		NodeCertExpiry:        90 * 24 * time.Hour,
+		ExternalCAs:           []string{},
+		JoinTokensMatchRootCA: true,
+		Nodes: []nodeTLSInfo{
+			{ID: "node-1", Hostname: "manager", Issuer: subject, TrustsRootCA: true, IssuedByCurrentCA: true},
+			{ID: "node-2", Hostname: "worker", Issuer: subject},
+		},
+		Warnings: []string{
+			"node worker (node-2) does not trust the current root CA",
+			"the certificate of node worker (node-2) is not issued by the current CA",
+		},
+	}
+	assert.Check(t, is.DeepEqual(expected, report))
+
+	info.RootRotationInProgress = true
+	report, err = newCAReport(info, nodes, now, 30*24*time.Hour)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(report.Warnings, 0))
+}
+
+func TestCAReportWarnings(t *testing.T) {
+	info, _ := caReportSwarm(t)
+	info.JoinTokens.Worker = "SWMTKN-1-3pu6hszjas19xyp7ghgosyx9k8atbfcr8p2is99znpy26u2lkl-1awxwuwd3z9j1z3puu7rcgdbx"
+	expiry := time.Date(3017, 11, 2, 21, 29, 18, 0, time.UTC)
+
+	testCases := []struct {
+		now      time.Time
+		expected []string
+	}{
+		{
+			now: expiry.Add(-60 * 24 * time.Hour),
+			expected: []string{
+				"the root CA certificate CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US expires on 3017-11-02T21:29:18Z, before the node certificates issued from now: rotate it with `docker swarm ca --rotate`",
+				"the join tokens are not for the current root CA: rotate them with `docker swarm join-token --rotate`",
+			},
+		},
+		{
+			now: expiry.Add(-24 * time.Hour),
+			expected: []string{
+				"the root CA certificate CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US expires on 3017-11-02T21:29:18Z: rotate it with `docker swarm ca --rotate`",
+				"the join tokens are not for the current root CA: rotate them with `docker swarm join-token --rotate`",
+			},
+		},
+		{
+			now: expiry.Add(time.Hour),
+			expected: []string{
+				"the root CA certificate CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US expired on 3017-11-02T21:29:18Z: rotate it with `docker swarm ca --rotate`",
+				"the join tokens are not for the current root CA: rotate them with `docker swarm join-token --rotate`",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		report, err := newCAReport(info, nil, tc.now, 30*24*time.Hour)
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(tc.expected, report.Warnings))
+	}
+}
+
+func TestCAInspect(t *testing.T) {
+	info, nodes := caReportSwarm(t)
+	cli := test.NewFakeCli(&fakeClient{
+		swarmInspectFunc: func() (swarm.Swarm, error) { return info, nil },
+		nodeListFunc:     func() ([]swarm.Node, error) { return nodes, nil },
+	})
+	cmd := newCACommand(cli)
+	cmd.SetArgs([]string{"--inspect"})
+	assert.NilError(t, cmd.Execute())
+
+	expected := `Root CA:
+ Subject: CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US
+ Issuer: CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US
+ Not Before: 2018-07-02T21:29:18Z
+ Not After: 3017-11-02T21:29:18Z
+Root Rotation In Progress: false
+Node Certificate Expiry: 2160h0m0s
+Join Tokens Match Root CA: true
+Nodes:
+ ID       HOSTNAME   ISSUER                                                  TRUSTS ROOT CA   ISSUED BY CURRENT CA
+ node-1   manager    CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US   true             true
+ node-2   worker     CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US   false            false
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+	assert.Check(t, is.Equal(`WARNING: node worker (node-2) does not trust the current root CA
+WARNING: the certificate of node worker (node-2) is not issued by the current CA
+`, cli.ErrBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	cmd = newCACommand(cli)
+	cmd.SetArgs([]string{"--inspect", "--format", "{{json .Warnings}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`["node worker (node-2) does not trust the current root CA","the certificate of node worker (node-2) is not issued by the current CA"]`+"\n", cli.OutBuffer().String()))
+}
+
+func TestCAInspectInvalidFlags(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{args: []string{"--inspect", "--rotate"}, expectedError: "--inspect cannot be used with --rotate"},
+		{args: []string{"--format", "{{json .}}"}, expectedError: "--format requires --inspect"},
+		{args: []string{"--rotate", "--expiry-warning", "24h"}, expectedError: "--expiry-warning requires --inspect"},
+	}
+	for _, tc := range testCases {
+		cmd := newCACommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+	}
+}
@@ -36,6 +36,9 @@ const (
 	flagAvailability              = "availability"
 	flagCACert                    = "ca-cert"
 	flagCAKey                     = "ca-key"
+	flagInspect                   = "inspect"
+	flagFormat                    = "format"
+	flagExpiryWarning             = "expiry-warning"
 )
 
 type swarmOptions struct {
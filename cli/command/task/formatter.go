@@ -21,6 +21,7 @@ const (
 	desiredStateHeader = "DESIRED STATE"
 	currentStateHeader = "CURRENT STATE"
 	errorHeader        = "ERROR"
+	historyHeader      = "HISTORY"
 
 	maxErrLength = 30
 )
@@ -44,9 +45,15 @@ func NewTaskFormat(source string, quiet bool) formatter.Format {
 
 // FormatWrite writes the context
 func FormatWrite(ctx formatter.Context, tasks []swarm.Task, names map[string]string, nodes map[string]string) error {
+	return formatWrite(ctx, tasks, names, nodes, nil)
+}
+
+// formatWrite writes the context, with the history of the states of the slots
+// of the tasks
+func formatWrite(ctx formatter.Context, tasks []swarm.Task, names, nodes, histories map[string]string) error {
 	render := func(format func(subContext formatter.SubContext) error) error {
 		for _, task := range tasks {
-			taskCtx := &taskContext{trunc: ctx.Trunc, task: task, name: names[task.ID], node: nodes[task.ID]}
+			taskCtx := &taskContext{trunc: ctx.Trunc, task: task, name: names[task.ID], node: nodes[task.ID], history: histories[task.ID]}
 			if err := format(taskCtx); err != nil {
 				return err
 			}
@@ -63,16 +70,18 @@ func FormatWrite(ctx formatter.Context, tasks []swarm.Task, names map[string]str
 		"CurrentState": currentStateHeader,
 		"Error":        errorHeader,
 		"Ports":        formatter.PortsHeader,
+		"History":      historyHeader,
 	}
 	return ctx.Write(&taskCtx, render)
 }
 
 type taskContext struct {
 	formatter.HeaderContext
-	trunc bool
-	task  swarm.Task
-	name  string
-	node  string
+	trunc   bool
+	task    swarm.Task
+	name    string
+	node    string
+	history string
 }
 
 func (c *taskContext) MarshalJSON() ([]byte, error) {
@@ -147,3 +156,9 @@ func (c *taskContext) Ports() string {
 	}
 	return strings.Join(ports, ",")
 }
+
+// History returns the last states of the slot of the task, when watching the
+// tasks
+func (c *taskContext) History() string {
+	return c.history
+}
@@ -38,49 +38,63 @@ func (t tasksBySlot) Less(i, j int) bool {
 func Print(ctx context.Context, dockerCli command.Cli, tasks []swarm.Task, resolver *idresolver.IDResolver, trunc, quiet bool, format string) error {
 	sort.Stable(tasksBySlot(tasks))
 
-	names := map[string]string{}
-	nodes := map[string]string{}
-
 	tasksCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewTaskFormat(format, quiet),
 		Trunc:  trunc,
 	}
 
-	prevName := ""
+	names, nodes, err := resolveNames(ctx, resolver, tasks)
+	if err != nil {
+		return err
+	}
+	if tasksCtx.Format.IsTable() {
+		names = indentNames(tasks, names)
+	}
+
+	return FormatWrite(tasksCtx, tasks, names, nodes)
+}
+
+// resolveNames returns the names of the tasks, made of the names of their
+// services and their slots or nodes, and the names of their nodes
+func resolveNames(ctx context.Context, resolver *idresolver.IDResolver, tasks []swarm.Task) (map[string]string, map[string]string, error) {
+	names := map[string]string{}
+	nodes := map[string]string{}
 	for _, task := range tasks {
 		serviceName, err := resolver.Resolve(ctx, swarm.Service{}, task.ServiceID)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
 		nodeValue, err := resolver.Resolve(ctx, swarm.Node{}, task.NodeID)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		var name string
 		if task.Slot != 0 {
-			name = fmt.Sprintf("%v.%v", serviceName, task.Slot)
+			names[task.ID] = fmt.Sprintf("%v.%v", serviceName, task.Slot)
 		} else {
-			name = fmt.Sprintf("%v.%v", serviceName, task.NodeID)
+			names[task.ID] = fmt.Sprintf("%v.%v", serviceName, task.NodeID)
 		}
+		nodes[task.ID] = nodeValue
+	}
+	return names, nodes, nil
+}
 
-		// Indent the name if necessary
-		indentedName := name
+// indentNames indents the names of the tasks that follow a task of the same
+// slot, which are its previous tasks
+func indentNames(tasks []swarm.Task, names map[string]string) map[string]string {
+	indented := map[string]string{}
+	prevName := ""
+	for _, task := range tasks {
+		name := names[task.ID]
+		indented[task.ID] = name
 		if name == prevName {
-			indentedName = fmt.Sprintf(" \\_ %s", indentedName)
+			indented[task.ID] = fmt.Sprintf(" \\_ %s", name)
 		}
 		prevName = name
-
-		names[task.ID] = name
-		if tasksCtx.Format.IsTable() {
-			names[task.ID] = indentedName
-		}
-		nodes[task.ID] = nodeValue
 	}
-
-	return FormatWrite(tasksCtx, tasks, names, nodes)
+	return indented
 }
 
 // DefaultFormat returns the default format from the config file, or table
@@ -0,0 +1,189 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+const (
+	defaultWatchTableFormat = "table {{.ID}}\t{{.Name}}\t{{.Image}}\t{{.Node}}\t{{.DesiredState}}\t{{.CurrentState}}\t{{.History}}\t{{.Error}}"
+
+	// maxHistory is the number of states kept in the history of a slot
+	maxHistory = 5
+)
+
+// watchInterval is the time between two updates of the tasks, when watching
+// them
+var watchInterval = 2 * time.Second
+
+// ListFunc lists tasks to watch
+type ListFunc func(ctx context.Context) ([]swarm.Task, error)
+
+// Watch prints the tasks returned by list in a format, and updates them until
+// the context is done. The "table" format adds the last states of each slot
+// to the default columns.
+//
+// On a terminal, the tasks are redrawn at each update, and the tasks whose
+// state changed since the previous update, and the tasks that failed, are
+// highlighted. Otherwise, the tasks are printed again when their states
+// change.
+func Watch(ctx context.Context, dockerCli command.Cli, list ListFunc, resolver *idresolver.IDResolver, trunc bool, format string) error {
+	if format == formatter.TableFormatKey {
+		format = defaultWatchTableFormat
+	}
+	w := &watcher{
+		dockerCli: dockerCli,
+		resolver:  resolver,
+		trunc:     trunc,
+		format:    NewTaskFormat(format, false),
+		terminal:  dockerCli.Out().IsTerminal(),
+		slots:     map[string]*slotHistory{},
+	}
+	for {
+		tasks, err := list(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := w.update(ctx, tasks); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// slotHistory is the history of the states of a slot
+type slotHistory struct {
+	taskID string
+	states []string
+}
+
+type watcher struct {
+	dockerCli command.Cli
+	resolver  *idresolver.IDResolver
+	trunc     bool
+	format    formatter.Format
+	terminal  bool
+
+	// slots are the histories of the slots, by name
+	slots map[string]*slotHistory
+	// states are the states and errors of the tasks at the last update, by
+	// ID, or nil before the first update
+	states map[string]string
+}
+
+func (w *watcher) update(ctx context.Context, tasks []swarm.Task) error {
+	sort.Stable(tasksBySlot(tasks))
+	names, nodes, err := resolveNames(ctx, w.resolver, tasks)
+	if err != nil {
+		return err
+	}
+
+	changed := map[string]bool{}
+	states := map[string]string{}
+	for _, task := range tasks {
+		state := string(task.Status.State) + " " + task.Status.Err
+		if previous, ok := w.states[task.ID]; !ok || previous != state {
+			changed[task.ID] = true
+		}
+		states[task.ID] = state
+	}
+	first := w.states == nil
+	if !first && !w.terminal && len(changed) == 0 && len(states) == len(w.states) {
+		return nil
+	}
+	w.states = states
+
+	histories := w.updateHistories(tasks, names)
+	if w.format.IsTable() {
+		names = indentNames(tasks, names)
+	}
+	var buf bytes.Buffer
+	tasksCtx := formatter.Context{
+		Output: &buf,
+		Format: w.format,
+		Trunc:  w.trunc,
+	}
+	if err := formatWrite(tasksCtx, tasks, names, nodes, histories); err != nil {
+		return err
+	}
+
+	out := w.dockerCli.Out()
+	switch {
+	case w.terminal:
+		fmt.Fprint(out, "\033[2J")
+		fmt.Fprint(out, "\033[H")
+		if w.format.IsTable() {
+			highlight(&buf, tasks, changed)
+		}
+	case !first:
+		fmt.Fprintln(out)
+	}
+	_, err = buf.WriteTo(out)
+	return err
+}
+
+// updateHistories adds the states of the most recent tasks of the slots to
+// their histories, and returns the histories by the IDs of these tasks
+func (w *watcher) updateHistories(tasks []swarm.Task, names map[string]string) map[string]string {
+	histories := map[string]string{}
+	for i, task := range tasks {
+		name := names[task.ID]
+		if i > 0 && names[tasks[i-1].ID] == name {
+			// a previous task of the slot
+			continue
+		}
+		slot, ok := w.slots[name]
+		if !ok {
+			slot = &slotHistory{}
+			w.slots[name] = slot
+		}
+		state := command.PrettyPrint(task.Status.State)
+		if slot.taskID != task.ID || slot.states[len(slot.states)-1] != state {
+			slot.states = append(slot.states, state)
+			if len(slot.states) > maxHistory {
+				slot.states = slot.states[len(slot.states)-maxHistory:]
+			}
+		}
+		slot.taskID = task.ID
+		histories[task.ID] = strings.Join(slot.states, " > ")
+	}
+	return histories
+}
+
+// highlight colors the rows of a table of tasks: the tasks that failed in red,
+// and the tasks whose state changed in bold
+func highlight(buf *bytes.Buffer, tasks []swarm.Task, changed map[string]bool) {
+	lines := strings.SplitAfter(buf.String(), "\n")
+	// a header, a row per task, and the empty string after the last newline
+	if len(lines) != len(tasks)+2 {
+		return
+	}
+	for i, task := range tasks {
+		line := strings.TrimSuffix(lines[i+1], "\n")
+		switch {
+		case task.Status.Err != "" && (task.Status.State == swarm.TaskStateFailed || task.Status.State == swarm.TaskStateRejected):
+			lines[i+1] = "\033[31m" + line + "\033[0m\n"
+		case changed[task.ID]:
+			lines[i+1] = "\033[1m" + line + "\033[0m\n"
+		}
+	}
+	buf.Reset()
+	buf.WriteString(strings.Join(lines, ""))
+}
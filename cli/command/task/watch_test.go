@@ -0,0 +1,55 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/cli/internal/test"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	"gotest.tools/golden"
+)
+
+func TestTaskWatch(t *testing.T) {
+	defer func(interval time.Duration) { watchInterval = interval }(watchInterval)
+	watchInterval = 0
+
+	created := time.Now()
+	first := func(state swarm.TaskState, err string) swarm.Task {
+		return *Task(TaskID("task-1"), TaskServiceID("web"), TaskDesiredState(swarm.TaskStateRunning), WithStatus(TaskState(state), StatusErr(err)))
+	}
+	second := func(state swarm.TaskState) swarm.Task {
+		task := *Task(TaskID("task-2"), TaskServiceID("web"), TaskDesiredState(swarm.TaskStateRunning), WithStatus(TaskState(state)))
+		task.CreatedAt = created
+		return task
+	}
+	updates := [][]swarm.Task{
+		{first(swarm.TaskStatePreparing, "")},
+		{first(swarm.TaskStateRunning, "")},
+		{first(swarm.TaskStateRunning, "")},
+		{first(swarm.TaskStateFailed, "task: non-zero exit (1)")},
+		{first(swarm.TaskStateFailed, "task: non-zero exit (1)"), second(swarm.TaskStateRunning)},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	list := func(ctx context.Context) ([]swarm.Task, error) {
+		if len(updates) == 0 {
+			cancel()
+			return nil, ctx.Err()
+		}
+		tasks := updates[0]
+		updates = updates[1:]
+		return tasks, nil
+	}
+
+	apiClient := &fakeClient{}
+	cli := test.NewFakeCli(apiClient)
+	err := Watch(ctx, cli, list, idresolver.New(apiClient, true), true, "table {{.Name}}\t{{.DesiredState}}\t{{.History}}\t{{.Error}}")
+	assert.NilError(t, err)
+	golden.Assert(t, cli.OutBuffer().String(), "task-watch.golden")
+}
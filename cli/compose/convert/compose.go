@@ -178,9 +178,13 @@ func driverObjectConfig(namespace Namespace, name string, obj composetypes.FileO
 }
 
 func fileObjectConfig(namespace Namespace, name string, obj composetypes.FileObjectConfig) (swarmFileObject, error) {
-	data, err := ioutil.ReadFile(obj.File)
-	if err != nil {
-		return swarmFileObject{}, err
+	data := []byte(obj.Content)
+	if !obj.Template {
+		var err error
+		data, err = ioutil.ReadFile(obj.File)
+		if err != nil {
+			return swarmFileObject{}, err
+		}
 	}
 
 	if obj.Name != "" {
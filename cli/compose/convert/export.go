@@ -0,0 +1,822 @@
+package convert
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	// exportVersion is the version of the Compose files created by Export
+	exportVersion = "3.8"
+
+	defaultNetworkDriver = "overlay"
+	defaultShmSize       = 64 * 1024 * 1024
+)
+
+var (
+	// contentHashSuffix matches the suffix added to the names of the configs
+	// and secrets of a stack by VersionFileObjects
+	contentHashSuffix = regexp.MustCompile(fmt.Sprintf("-[0-9a-f]{%d}$", contentHashLength))
+	// anonymousVolumeName matches the names generated for anonymous volumes
+	anonymousVolumeName = regexp.MustCompile("^[0-9a-f]{64}$")
+)
+
+// Export converts swarm services or containers back to a Compose file. The
+// settings that cannot be expressed in a Compose file are reported in
+// Warnings.
+type Export struct {
+	Config   *composetypes.Config
+	Warnings []string
+}
+
+// NewExport returns an empty Export
+func NewExport() *Export {
+	return &Export{Config: &composetypes.Config{Version: exportVersion}}
+}
+
+func (e *Export) warnf(service, format string, args ...interface{}) {
+	e.Warnings = append(e.Warnings, fmt.Sprintf("service %q: %s", service, fmt.Sprintf(format, args...)))
+}
+
+func (e *Export) addNetwork(name string, network composetypes.NetworkConfig) {
+	if e.Config.Networks == nil {
+		e.Config.Networks = map[string]composetypes.NetworkConfig{}
+	}
+	e.Config.Networks[name] = network
+}
+
+func (e *Export) addVolume(name string, volume composetypes.VolumeConfig) {
+	if e.Config.Volumes == nil {
+		e.Config.Volumes = map[string]composetypes.VolumeConfig{}
+	}
+	e.Config.Volumes[name] = volume
+}
+
+func (e *Export) addSecret(name string, secret composetypes.SecretConfig) {
+	if e.Config.Secrets == nil {
+		e.Config.Secrets = map[string]composetypes.SecretConfig{}
+	}
+	e.Config.Secrets[name] = secret
+}
+
+func (e *Export) addConfig(name string, config composetypes.ConfigObjConfig) {
+	if e.Config.Configs == nil {
+		e.Config.Configs = map[string]composetypes.ConfigObjConfig{}
+	}
+	e.Config.Configs[name] = config
+}
+
+// AddService adds a swarm service of the stack namespace to the Compose file.
+// networks are the networks the service is attached to, by ID.
+func (e *Export) AddService(namespace Namespace, service swarm.Service, networks map[string]types.NetworkResource) {
+	spec := service.Spec
+	name := namespace.Descope(spec.Name)
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	if containerSpec == nil {
+		e.warnf(name, "only container services can be expressed in a Compose file")
+		return
+	}
+
+	image := containerSpec.Image
+	if original, ok := spec.Labels[LabelImage]; ok && original != "" {
+		image = original
+	}
+	s := composetypes.ServiceConfig{
+		Name:            name,
+		Image:           image,
+		Entrypoint:      containerSpec.Command,
+		Command:         containerSpec.Args,
+		Hostname:        containerSpec.Hostname,
+		ExtraHosts:      fromSwarmHosts(containerSpec.Hosts),
+		HealthCheck:     fromHealthConfig(containerSpec.Healthcheck),
+		Environment:     fromEnv(containerSpec.Env, nil),
+		Labels:          withoutLabels(containerSpec.Labels, LabelNamespace),
+		WorkingDir:      containerSpec.Dir,
+		User:            containerSpec.User,
+		StopGracePeriod: fromDurationPtr(containerSpec.StopGracePeriod),
+		StopSignal:      containerSpec.StopSignal,
+		Tty:             containerSpec.TTY,
+		StdinOpen:       containerSpec.OpenStdin,
+		ReadOnly:        containerSpec.ReadOnly,
+		Isolation:       string(containerSpec.Isolation),
+		Init:            containerSpec.Init,
+		Sysctls:         containerSpec.Sysctls,
+		Deploy: composetypes.DeployConfig{
+			Labels:         withoutLabels(spec.Labels, LabelNamespace, LabelImage),
+			UpdateConfig:   fromUpdateConfig(spec.UpdateConfig),
+			RollbackConfig: fromUpdateConfig(spec.RollbackConfig),
+		},
+	}
+	if containerSpec.Isolation.IsDefault() {
+		s.Isolation = ""
+	}
+	if dns := containerSpec.DNSConfig; dns != nil {
+		s.DNS = dns.Nameservers
+		s.DNSSearch = dns.Search
+		if len(dns.Options) > 0 {
+			e.warnf(name, "dns options cannot be expressed in a Compose file")
+		}
+	}
+	if len(containerSpec.Groups) > 0 {
+		e.warnf(name, "additional groups cannot be expressed in a Compose file")
+	}
+	if p := containerSpec.Privileges; p != nil {
+		if p.SELinuxContext != nil {
+			e.warnf(name, "SELinux context cannot be expressed in a Compose file")
+		}
+		if p.CredentialSpec != nil {
+			s.CredentialSpec = composetypes.CredentialSpecConfig{
+				File:     p.CredentialSpec.File,
+				Registry: p.CredentialSpec.Registry,
+			}
+			for _, ref := range containerSpec.Configs {
+				if ref.Runtime != nil && ref.ConfigID == p.CredentialSpec.Config {
+					s.CredentialSpec.Config = e.addStackConfig(namespace, ref.ConfigName)
+				}
+			}
+		}
+	}
+
+	switch {
+	case spec.Mode.Global != nil:
+		s.Deploy.Mode = "global"
+	case spec.Mode.Replicated != nil:
+		s.Deploy.Replicas = spec.Mode.Replicated.Replicas
+	}
+	if endpoint := spec.EndpointSpec; endpoint != nil {
+		if endpoint.Mode != swarm.ResolutionModeVIP {
+			s.Deploy.EndpointMode = string(endpoint.Mode)
+		}
+		for _, port := range endpoint.Ports {
+			s.Ports = append(s.Ports, fromPortConfig(port))
+		}
+	}
+	e.addTaskSpec(&s, spec.TaskTemplate)
+
+	for _, m := range containerSpec.Mounts {
+		if volume, ok := e.fromMount(namespace, &s, m); ok {
+			s.Volumes = append(s.Volumes, volume)
+		}
+	}
+	for _, ref := range containerSpec.Secrets {
+		source := e.addStackSecret(namespace, ref.SecretName)
+		s.Secrets = append(s.Secrets, composetypes.ServiceSecretConfig(fromFileTarget(source, ref.File.Name, ref.File.UID, ref.File.GID, ref.File.Mode)))
+	}
+	for _, ref := range containerSpec.Configs {
+		if ref.File == nil {
+			continue
+		}
+		source := e.addStackConfig(namespace, ref.ConfigName)
+		s.Configs = append(s.Configs, composetypes.ServiceConfigObjConfig(fromFileTarget(source, ref.File.Name, ref.File.UID, ref.File.GID, ref.File.Mode)))
+	}
+
+	taskNetworks := spec.TaskTemplate.Networks
+	if len(taskNetworks) == 0 {
+		taskNetworks = spec.Networks
+	}
+	for _, attachment := range taskNetworks {
+		network, ok := networks[attachment.Target]
+		if !ok {
+			// the target may be a name, as in a service spec that is not
+			// returned by the daemon
+			network = types.NetworkResource{Name: attachment.Target}
+		}
+		if network.Ingress {
+			continue
+		}
+		key := e.addStackNetwork(namespace, network)
+		var aliases []string
+		for _, alias := range attachment.Aliases {
+			if alias != name {
+				aliases = append(aliases, alias)
+			}
+		}
+		if len(attachment.DriverOpts) > 0 {
+			e.warnf(name, "driver options of network %s cannot be expressed in a Compose file", key)
+		}
+		if key == defaultNetwork && len(aliases) == 0 && len(taskNetworks) == 1 {
+			continue
+		}
+		if s.Networks == nil {
+			s.Networks = map[string]*composetypes.ServiceNetworkConfig{}
+		}
+		if len(aliases) == 0 {
+			s.Networks[key] = nil
+		} else {
+			s.Networks[key] = &composetypes.ServiceNetworkConfig{Aliases: aliases}
+		}
+	}
+
+	e.Config.Services = append(e.Config.Services, s)
+}
+
+func (e *Export) addTaskSpec(s *composetypes.ServiceConfig, task swarm.TaskSpec) {
+	if task.LogDriver != nil {
+		s.Logging = &composetypes.LoggingConfig{Driver: task.LogDriver.Name, Options: task.LogDriver.Options}
+	}
+	if resources := task.Resources; resources != nil {
+		s.Deploy.Resources.Limits = fromResources(resources.Limits)
+		s.Deploy.Resources.Reservations = fromResources(resources.Reservations)
+	}
+	if policy := task.RestartPolicy; policy != nil {
+		s.Deploy.RestartPolicy = &composetypes.RestartPolicy{
+			Condition:   string(policy.Condition),
+			Delay:       fromDurationPtr(policy.Delay),
+			MaxAttempts: policy.MaxAttempts,
+			Window:      fromDurationPtr(policy.Window),
+		}
+	}
+	if placement := task.Placement; placement != nil {
+		s.Deploy.Placement.Constraints = placement.Constraints
+		s.Deploy.Placement.MaxReplicas = placement.MaxReplicas
+		for _, preference := range placement.Preferences {
+			if preference.Spread != nil {
+				s.Deploy.Placement.Preferences = append(s.Deploy.Placement.Preferences, composetypes.PlacementPreferences{
+					Spread: preference.Spread.SpreadDescriptor,
+				})
+			}
+		}
+	}
+	if task.Runtime != "" && task.Runtime != swarm.RuntimeContainer {
+		e.warnf(s.Name, "runtime %s cannot be expressed in a Compose file", task.Runtime)
+	}
+}
+
+// fromMount converts a mount, and adds its volume to the Compose file
+func (e *Export) fromMount(namespace Namespace, s *composetypes.ServiceConfig, m mount.Mount) (composetypes.ServiceVolumeConfig, bool) {
+	volume := composetypes.ServiceVolumeConfig{
+		Type:        string(m.Type),
+		Source:      m.Source,
+		Target:      m.Target,
+		ReadOnly:    m.ReadOnly,
+		Consistency: string(m.Consistency),
+	}
+	if volume.Consistency == string(mount.ConsistencyDefault) {
+		volume.Consistency = ""
+	}
+	switch m.Type {
+	case mount.TypeBind, mount.TypeNamedPipe:
+		if m.BindOptions != nil && m.BindOptions.Propagation != "" {
+			volume.Bind = &composetypes.ServiceVolumeBind{Propagation: string(m.BindOptions.Propagation)}
+		}
+	case mount.TypeTmpfs:
+		if m.TmpfsOptions != nil {
+			if m.TmpfsOptions.SizeBytes != 0 {
+				volume.Tmpfs = &composetypes.ServiceVolumeTmpfs{Size: m.TmpfsOptions.SizeBytes}
+			}
+			if m.TmpfsOptions.Mode != 0 {
+				e.warnf(s.Name, "mode of tmpfs %s cannot be expressed in a Compose file", m.Target)
+			}
+		}
+	case mount.TypeVolume:
+		if m.VolumeOptions != nil && m.VolumeOptions.NoCopy {
+			volume.Volume = &composetypes.ServiceVolumeVolume{NoCopy: true}
+		}
+		if m.Source == "" {
+			break
+		}
+		options := m.VolumeOptions
+		if options == nil || options.Labels[LabelNamespace] != namespace.Name() {
+			volume.Source = externalName(m.Source)
+			e.addVolume(volume.Source, composetypes.VolumeConfig{Name: m.Source, External: composetypes.External{External: true}})
+			break
+		}
+		volume.Source = namespace.Descope(m.Source)
+		stackVolume := composetypes.VolumeConfig{Labels: withoutLabels(options.Labels, LabelNamespace)}
+		if volume.Source == m.Source {
+			stackVolume.Name = m.Source
+		}
+		if options.DriverConfig != nil {
+			stackVolume.Driver = options.DriverConfig.Name
+			stackVolume.DriverOpts = options.DriverConfig.Options
+		}
+		e.addVolume(volume.Source, stackVolume)
+	default:
+		e.warnf(s.Name, "mount of type %s cannot be expressed in a Compose file", m.Type)
+		return volume, false
+	}
+	return volume, true
+}
+
+// addStackNetwork adds a network to the Compose file, and returns its key. The
+// networks of the stack are declared, the other ones are external.
+func (e *Export) addStackNetwork(namespace Namespace, network types.NetworkResource) string {
+	if network.Labels[LabelNamespace] != namespace.Name() || namespace.Name() == "" {
+		key := externalName(network.Name)
+		e.addNetwork(key, composetypes.NetworkConfig{Name: network.Name, External: composetypes.External{External: true}})
+		return key
+	}
+	key := namespace.Descope(network.Name)
+	config := composetypes.NetworkConfig{
+		Driver:     network.Driver,
+		DriverOpts: network.Options,
+		Internal:   network.Internal,
+		Attachable: network.Attachable,
+		Labels:     withoutLabels(network.Labels, LabelNamespace),
+	}
+	if config.Driver == defaultNetworkDriver {
+		config.Driver = ""
+	}
+	if key == network.Name {
+		config.Name = network.Name
+	}
+	if network.IPAM.Driver != "" && network.IPAM.Driver != "default" {
+		config.Ipam.Driver = network.IPAM.Driver
+	}
+	for _, pool := range network.IPAM.Config {
+		config.Ipam.Config = append(config.Ipam.Config, &composetypes.IPAMPool{Subnet: pool.Subnet})
+	}
+	if key == defaultNetwork && config.Driver == "" && len(config.DriverOpts) == 0 && !config.Internal && !config.Attachable && len(config.Labels) == 0 && len(config.Ipam.Config) == 0 {
+		// the default network of the stack is created implicitly
+		return key
+	}
+	e.addNetwork(key, config)
+	return key
+}
+
+// addStackSecret adds a secret to the Compose file, and returns its key. As
+// the content of secrets cannot be read, they are all declared as external.
+func (e *Export) addStackSecret(namespace Namespace, name string) string {
+	key := fileObjectKey(namespace, name)
+	e.addSecret(key, composetypes.SecretConfig{Name: name, External: composetypes.External{External: true}})
+	return key
+}
+
+// addStackConfig adds a config to the Compose file, and returns its key. The
+// configs are declared as external, as the configs of the stack are versioned.
+func (e *Export) addStackConfig(namespace Namespace, name string) string {
+	key := fileObjectKey(namespace, name)
+	e.addConfig(key, composetypes.ConfigObjConfig{Name: name, External: composetypes.External{External: true}})
+	return key
+}
+
+func fileObjectKey(namespace Namespace, name string) string {
+	if namespace.Name() == "" || !strings.HasPrefix(name, namespace.Name()+"_") {
+		return externalName(name)
+	}
+	return contentHashSuffix.ReplaceAllString(namespace.Descope(name), "")
+}
+
+// externalName returns the key of an object that is not part of the stack
+func externalName(name string) string {
+	return strings.Replace(name, ".", "_", -1)
+}
+
+// AddContainer adds a container to the Compose file. The settings of the
+// container that are the defaults of its image are omitted. The volumes and
+// networks of the container are declared as external.
+func (e *Export) AddContainer(c types.ContainerJSON, image types.ImageInspect) {
+	name := strings.TrimPrefix(c.Name, "/")
+	config := c.Config
+	imageConfig := image.Config
+	if imageConfig == nil {
+		imageConfig = &container.Config{}
+	}
+	s := composetypes.ServiceConfig{
+		Name:        name,
+		Image:       config.Image,
+		Environment: fromEnv(config.Env, imageConfig.Env),
+		Labels:      withoutImageLabels(config.Labels, imageConfig.Labels),
+		User:        config.User,
+		Tty:         config.Tty,
+		StdinOpen:   config.OpenStdin,
+	}
+	if !equalStrings(config.Entrypoint, imageConfig.Entrypoint) {
+		s.Entrypoint = composetypes.ShellCommand(config.Entrypoint)
+	}
+	if !equalStrings(config.Cmd, imageConfig.Cmd) || len(s.Entrypoint) > 0 {
+		s.Command = composetypes.ShellCommand(config.Cmd)
+	}
+	if config.WorkingDir != imageConfig.WorkingDir {
+		s.WorkingDir = config.WorkingDir
+	}
+	if config.StopSignal != imageConfig.StopSignal {
+		s.StopSignal = config.StopSignal
+	}
+	if config.Hostname != "" && !strings.HasPrefix(c.ID, config.Hostname) {
+		s.Hostname = config.Hostname
+	}
+	if config.Domainname != "" {
+		s.DomainName = config.Domainname
+	}
+	if config.StopTimeout != nil {
+		stopTimeout := time.Duration(*config.StopTimeout) * time.Second
+		s.StopGracePeriod = fromDurationPtr(&stopTimeout)
+	}
+	if config.Healthcheck != nil && (imageConfig.Healthcheck == nil || !equalStrings(config.Healthcheck.Test, imageConfig.Healthcheck.Test)) {
+		s.HealthCheck = fromHealthConfig(config.Healthcheck)
+	}
+	if hostConfig := c.HostConfig; hostConfig != nil {
+		for port := range config.ExposedPorts {
+			if _, ok := imageConfig.ExposedPorts[port]; !ok {
+				if _, published := hostConfig.PortBindings[port]; !published {
+					s.Expose = append(s.Expose, string(port))
+				}
+			}
+		}
+		sort.Strings(s.Expose)
+		e.addHostConfig(&s, hostConfig)
+	}
+	for _, m := range c.Mounts {
+		if volume, ok := e.fromMountPoint(&s, m); ok {
+			s.Volumes = append(s.Volumes, volume)
+		}
+	}
+	if c.NetworkSettings != nil {
+		e.addContainerNetworks(&s, c)
+	}
+	e.Config.Services = append(e.Config.Services, s)
+}
+
+func (e *Export) addHostConfig(s *composetypes.ServiceConfig, hostConfig *container.HostConfig) {
+	ports := make([]string, 0, len(hostConfig.PortBindings))
+	for port := range hostConfig.PortBindings {
+		ports = append(ports, string(port))
+	}
+	sort.Strings(ports)
+	for _, port := range ports {
+		containerPort := nat.Port(port)
+		for _, binding := range hostConfig.PortBindings[containerPort] {
+			published, _ := strconv.ParseUint(binding.HostPort, 10, 32)
+			if binding.HostIP != "" && binding.HostIP != "0.0.0.0" {
+				e.warnf(s.Name, "host IP of port %s cannot be expressed in a Compose file", port)
+			}
+			portConfig := composetypes.ServicePortConfig{
+				Target:    uint32(containerPort.Int()),
+				Published: uint32(published),
+			}
+			if proto := containerPort.Proto(); proto != "tcp" {
+				portConfig.Protocol = proto
+			}
+			s.Ports = append(s.Ports, portConfig)
+		}
+	}
+
+	switch policy := hostConfig.RestartPolicy; {
+	case policy.IsAlways(), policy.IsUnlessStopped():
+		s.Deploy.RestartPolicy = &composetypes.RestartPolicy{Condition: string(swarm.RestartPolicyConditionAny)}
+	case policy.IsOnFailure():
+		s.Deploy.RestartPolicy = &composetypes.RestartPolicy{Condition: string(swarm.RestartPolicyConditionOnFailure)}
+		if policy.MaximumRetryCount > 0 {
+			attempts := uint64(policy.MaximumRetryCount)
+			s.Deploy.RestartPolicy.MaxAttempts = &attempts
+		}
+	}
+	if hostConfig.NanoCPUs != 0 || hostConfig.Memory != 0 {
+		s.Deploy.Resources.Limits = fromResources(&swarm.Resources{NanoCPUs: hostConfig.NanoCPUs, MemoryBytes: hostConfig.Memory})
+	}
+	if hostConfig.MemoryReservation != 0 {
+		s.Deploy.Resources.Reservations = &composetypes.Resource{MemoryBytes: composetypes.UnitBytes(hostConfig.MemoryReservation)}
+	}
+	if hostConfig.CPUShares != 0 || hostConfig.CpusetCpus != "" || hostConfig.CPUQuota != 0 || hostConfig.MemorySwap > 0 {
+		e.warnf(s.Name, "cpu shares, cpuset, cpu quota and memory swap cannot be expressed in a Compose file")
+	}
+
+	s.CapAdd = hostConfig.CapAdd
+	s.CapDrop = hostConfig.CapDrop
+	s.Privileged = hostConfig.Privileged
+	s.ReadOnly = hostConfig.ReadonlyRootfs
+	s.Init = hostConfig.Init
+	s.DNS = hostConfig.DNS
+	s.DNSSearch = hostConfig.DNSSearch
+	s.SecurityOpt = hostConfig.SecurityOpt
+	s.ExtraHosts = hostConfig.ExtraHosts
+	if len(hostConfig.Sysctls) > 0 {
+		s.Sysctls = hostConfig.Sysctls
+	}
+	if len(hostConfig.DNSOptions) > 0 {
+		e.warnf(s.Name, "dns options cannot be expressed in a Compose file")
+	}
+	for _, device := range hostConfig.Devices {
+		s.Devices = append(s.Devices, device.PathOnHost+":"+device.PathInContainer+":"+device.CgroupPermissions)
+	}
+	for target := range hostConfig.Tmpfs {
+		s.Tmpfs = append(s.Tmpfs, target)
+	}
+	sort.Strings(s.Tmpfs)
+	for _, ulimit := range hostConfig.Ulimits {
+		if s.Ulimits == nil {
+			s.Ulimits = map[string]*composetypes.UlimitsConfig{}
+		}
+		s.Ulimits[ulimit.Name] = &composetypes.UlimitsConfig{Soft: int(ulimit.Soft), Hard: int(ulimit.Hard)}
+	}
+	if hostConfig.ShmSize != 0 && hostConfig.ShmSize != defaultShmSize {
+		s.ShmSize = strconv.FormatInt(hostConfig.ShmSize, 10)
+	}
+	if hostConfig.PidMode.IsHost() {
+		s.Pid = "host"
+	}
+	if hostConfig.IpcMode.IsHost() {
+		s.Ipc = "host"
+	}
+	if hostConfig.UsernsMode.IsHost() {
+		s.UserNSMode = "host"
+	}
+	if !hostConfig.Isolation.IsDefault() {
+		s.Isolation = string(hostConfig.Isolation)
+	}
+	if hostConfig.CgroupParent != "" {
+		s.CgroupParent = hostConfig.CgroupParent
+	}
+	if log := hostConfig.LogConfig; log.Type != "" && (log.Type != "json-file" || len(log.Config) > 0) {
+		s.Logging = &composetypes.LoggingConfig{Driver: log.Type, Options: log.Config}
+	}
+	if len(hostConfig.Links) > 0 || len(hostConfig.VolumesFrom) > 0 {
+		e.warnf(s.Name, "links and volumes_from cannot be expressed in a Compose file")
+	}
+}
+
+// fromMountPoint converts a mount of a container, and adds its volume to the
+// Compose file
+func (e *Export) fromMountPoint(s *composetypes.ServiceConfig, m types.MountPoint) (composetypes.ServiceVolumeConfig, bool) {
+	volume := composetypes.ServiceVolumeConfig{
+		Type:     string(m.Type),
+		Source:   m.Source,
+		Target:   m.Destination,
+		ReadOnly: !m.RW,
+	}
+	switch m.Type {
+	case mount.TypeBind, mount.TypeNamedPipe:
+		if m.Propagation != "" && m.Propagation != mount.PropagationRPrivate {
+			volume.Bind = &composetypes.ServiceVolumeBind{Propagation: string(m.Propagation)}
+		}
+	case mount.TypeVolume:
+		if anonymousVolumeName.MatchString(m.Name) {
+			volume.Source = ""
+			break
+		}
+		volume.Source = externalName(m.Name)
+		e.addVolume(volume.Source, composetypes.VolumeConfig{Name: m.Name, External: composetypes.External{External: true}})
+	case mount.TypeTmpfs:
+		volume.Source = ""
+	default:
+		e.warnf(s.Name, "mount of type %s cannot be expressed in a Compose file", m.Type)
+		return volume, false
+	}
+	return volume, true
+}
+
+func (e *Export) addContainerNetworks(s *composetypes.ServiceConfig, c types.ContainerJSON) {
+	mode := container.NetworkMode("")
+	if c.HostConfig != nil {
+		mode = c.HostConfig.NetworkMode
+	}
+	switch {
+	case mode.IsHost(), mode.IsNone():
+		s.NetworkMode = string(mode)
+		return
+	case mode.IsContainer():
+		e.warnf(s.Name, "network mode %s cannot be expressed in a Compose file", mode)
+		return
+	}
+
+	names := make([]string, 0, len(c.NetworkSettings.Networks))
+	for name := range c.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "bridge" {
+			// the default bridge network has no equivalent in a Compose
+			// file, which connects the services to a default network
+			continue
+		}
+		endpoint := c.NetworkSettings.Networks[name]
+		key := externalName(name)
+		e.addNetwork(key, composetypes.NetworkConfig{Name: name, External: composetypes.External{External: true}})
+		var network *composetypes.ServiceNetworkConfig
+		if endpoint != nil {
+			var aliases []string
+			for _, alias := range endpoint.Aliases {
+				if !strings.HasPrefix(c.ID, alias) && alias != s.Name {
+					aliases = append(aliases, alias)
+				}
+			}
+			if len(aliases) > 0 {
+				network = &composetypes.ServiceNetworkConfig{Aliases: aliases}
+			}
+			if endpoint.IPAMConfig != nil && (endpoint.IPAMConfig.IPv4Address != "" || endpoint.IPAMConfig.IPv6Address != "") {
+				if network == nil {
+					network = &composetypes.ServiceNetworkConfig{}
+				}
+				network.Ipv4Address = endpoint.IPAMConfig.IPv4Address
+				network.Ipv6Address = endpoint.IPAMConfig.IPv6Address
+			}
+		}
+		if s.Networks == nil {
+			s.Networks = map[string]*composetypes.ServiceNetworkConfig{}
+		}
+		s.Networks[key] = network
+	}
+}
+
+// fromFileTarget converts the target of a secret or config, omitting the
+// defaults set by convertFileObject
+func fromFileTarget(source, name, uid, gid string, mode os.FileMode) composetypes.FileReferenceConfig {
+	ref := composetypes.FileReferenceConfig{Source: source}
+	if name != source {
+		ref.Target = name
+	}
+	if uid != "0" {
+		ref.UID = uid
+	}
+	if gid != "0" {
+		ref.GID = gid
+	}
+	if mode != 0444 {
+		ref.Mode = uint32Ptr(uint32(mode))
+	}
+	return ref
+}
+
+// fromSwarmHosts converts hosts from the SwarmKit notation, "IP-address
+// hostname(s)", to <host>:<ip> mappings
+func fromSwarmHosts(hosts []string) composetypes.HostsList {
+	var extraHosts composetypes.HostsList
+	for _, host := range hosts {
+		fields := strings.Fields(host)
+		for _, hostname := range fields[1:] {
+			extraHosts = append(extraHosts, hostname+":"+fields[0])
+		}
+	}
+	return extraHosts
+}
+
+func fromHealthConfig(healthcheck *container.HealthConfig) *composetypes.HealthCheckConfig {
+	if healthcheck == nil {
+		return nil
+	}
+	if len(healthcheck.Test) == 1 && healthcheck.Test[0] == "NONE" {
+		return &composetypes.HealthCheckConfig{Disable: true}
+	}
+	config := &composetypes.HealthCheckConfig{
+		Test:        healthcheck.Test,
+		Timeout:     fromDuration(healthcheck.Timeout),
+		Interval:    fromDuration(healthcheck.Interval),
+		StartPeriod: fromDuration(healthcheck.StartPeriod),
+	}
+	if healthcheck.Retries != 0 {
+		retries := uint64(healthcheck.Retries)
+		config.Retries = &retries
+	}
+	return config
+}
+
+// fromEnv converts environment variables, omitting the ones set to the same
+// value by the image
+func fromEnv(env []string, imageEnv []string) composetypes.MappingWithEquals {
+	defaults := map[string]bool{}
+	for _, v := range imageEnv {
+		defaults[v] = true
+	}
+	var mapping composetypes.MappingWithEquals
+	for _, v := range env {
+		if defaults[v] {
+			continue
+		}
+		if mapping == nil {
+			mapping = composetypes.MappingWithEquals{}
+		}
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) == 1 {
+			mapping[parts[0]] = nil
+			continue
+		}
+		value := parts[1]
+		mapping[parts[0]] = &value
+	}
+	return mapping
+}
+
+func fromUpdateConfig(config *swarm.UpdateConfig) *composetypes.UpdateConfig {
+	if config == nil {
+		return nil
+	}
+	parallelism := config.Parallelism
+	return &composetypes.UpdateConfig{
+		Parallelism:     &parallelism,
+		Delay:           composetypes.Duration(config.Delay),
+		FailureAction:   config.FailureAction,
+		Monitor:         composetypes.Duration(config.Monitor),
+		MaxFailureRatio: config.MaxFailureRatio,
+		Order:           config.Order,
+	}
+}
+
+func fromResources(resources *swarm.Resources) *composetypes.Resource {
+	if resources == nil || resources.NanoCPUs == 0 && resources.MemoryBytes == 0 && len(resources.GenericResources) == 0 {
+		return nil
+	}
+	resource := &composetypes.Resource{MemoryBytes: composetypes.UnitBytes(resources.MemoryBytes)}
+	if resources.NanoCPUs != 0 {
+		resource.NanoCPUs = strconv.FormatFloat(float64(resources.NanoCPUs)/1e9, 'f', -1, 64)
+	}
+	for _, generic := range resources.GenericResources {
+		if generic.DiscreteResourceSpec != nil {
+			resource.GenericResources = append(resource.GenericResources, composetypes.GenericResource{
+				DiscreteResourceSpec: &composetypes.DiscreteGenericResource{
+					Kind:  generic.DiscreteResourceSpec.Kind,
+					Value: generic.DiscreteResourceSpec.Value,
+				},
+			})
+		}
+	}
+	return resource
+}
+
+func fromPortConfig(port swarm.PortConfig) composetypes.ServicePortConfig {
+	config := composetypes.ServicePortConfig{
+		Mode:      string(port.PublishMode),
+		Target:    port.TargetPort,
+		Published: port.PublishedPort,
+		Protocol:  string(port.Protocol),
+	}
+	if port.PublishMode == swarm.PortConfigPublishModeIngress {
+		config.Mode = ""
+	}
+	if port.Protocol == swarm.PortConfigProtocolTCP {
+		config.Protocol = ""
+	}
+	return config
+}
+
+func fromDuration(d time.Duration) *composetypes.Duration {
+	if d == 0 {
+		return nil
+	}
+	duration := composetypes.Duration(d)
+	return &duration
+}
+
+func fromDurationPtr(d *time.Duration) *composetypes.Duration {
+	if d == nil {
+		return nil
+	}
+	duration := composetypes.Duration(*d)
+	return &duration
+}
+
+// withoutLabels returns a copy of labels without the given keys, or nil if
+// no label is left
+func withoutLabels(labels map[string]string, keys ...string) composetypes.Labels {
+	var result composetypes.Labels
+	for key, value := range labels {
+		if containsString(keys, key) {
+			continue
+		}
+		if result == nil {
+			result = composetypes.Labels{}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// withoutImageLabels returns the labels that are not set to the same value by
+// the image
+func withoutImageLabels(labels, imageLabels map[string]string) composetypes.Labels {
+	var result composetypes.Labels
+	for key, value := range labels {
+		if imageValue, ok := imageLabels[key]; ok && imageValue == value {
+			continue
+		}
+		if result == nil {
+			result = composetypes.Labels{}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,267 @@
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/compose/loader"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/go-connections/nat"
+	yaml "gopkg.in/yaml.v2"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func loadConfig(t *testing.T, source string) *composetypes.Config {
+	t.Helper()
+	dict, err := loader.ParseYAML([]byte(source))
+	assert.NilError(t, err)
+	config, err := loader.Load(composetypes.ConfigDetails{
+		WorkingDir:  ".",
+		ConfigFiles: []composetypes.ConfigFile{{Filename: "docker-compose.yml", Config: dict}},
+	})
+	assert.NilError(t, err)
+	return config
+}
+
+const exportServiceSource = `version: "3.8"
+services:
+  web:
+    image: nginx:1.17
+    command: ["nginx", "-g", "daemon off;"]
+    environment:
+      LEVEL: info
+    labels:
+      com.example.role: front
+    ports:
+      - target: 53
+        published: 53
+        protocol: udp
+        mode: host
+      - target: 8080
+        published: 80
+    networks:
+      back:
+        aliases: [www]
+    volumes:
+      - type: volume
+        source: data
+        target: /data
+      - type: bind
+        source: /var/run/docker.sock
+        target: /var/run/docker.sock
+        read_only: true
+    secrets:
+      - source: password
+        target: db_password
+    extra_hosts:
+      - "somehost:162.242.195.82"
+    healthcheck:
+      test: ["CMD", "curl", "-f", "http://localhost"]
+      interval: 10s
+    stop_grace_period: 20s
+    deploy:
+      replicas: 2
+      labels:
+        com.example.tier: web
+      update_config:
+        parallelism: 1
+        delay: 5s
+      resources:
+        limits:
+          cpus: "0.5"
+          memory: 64M
+      restart_policy:
+        condition: on-failure
+      placement:
+        constraints: [node.role == worker]
+networks:
+  back:
+    attachable: true
+volumes:
+  data:
+secrets:
+  password:
+    external: true
+`
+
+func TestExportServiceRoundTrip(t *testing.T) {
+	config := loadConfig(t, exportServiceSource)
+	namespace := NewNamespace("test")
+	service := config.Services[0]
+	secrets := []*swarm.SecretReference{{
+		SecretName: "password",
+		File:       &swarm.SecretReferenceFileTarget{Name: "db_password", UID: "0", GID: "0", Mode: 0444},
+	}}
+	spec, err := Service("1.40", namespace, service, config.Networks, config.Volumes, secrets, nil)
+	assert.NilError(t, err)
+	networks := map[string]types.NetworkResource{
+		"test_back": {
+			ID:         "test_back",
+			Name:       "test_back",
+			Driver:     "overlay",
+			Attachable: true,
+			Labels:     map[string]string{LabelNamespace: "test"},
+		},
+	}
+
+	export := NewExport()
+	export.AddService(namespace, swarm.Service{Spec: spec}, networks)
+	assert.Check(t, is.Len(export.Warnings, 0))
+	assert.Assert(t, is.Len(export.Config.Services, 1))
+
+	// converting the service added the stack labels to the loaded config
+	expected := loadConfig(t, exportServiceSource)
+	service = expected.Services[0]
+	assert.Check(t, is.DeepEqual(service, export.Config.Services[0]))
+	assert.Check(t, is.DeepEqual(expected.Networks, export.Config.Networks))
+	assert.Check(t, is.DeepEqual(expected.Volumes, export.Config.Volumes))
+	assert.Check(t, is.DeepEqual(expected.Secrets, export.Config.Secrets))
+
+	// the exported Compose file must be valid
+	out, err := yaml.Marshal(export.Config)
+	assert.NilError(t, err)
+	reloaded := loadConfig(t, string(out))
+	assert.Check(t, is.DeepEqual(service, reloaded.Services[0]))
+}
+
+func TestExportServiceStackObjects(t *testing.T) {
+	namespace := NewNamespace("test")
+	service := swarm.Service{Spec: swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "test_web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image: "nginx",
+				Configs: []*swarm.ConfigReference{{
+					ConfigName: "test_site-0123456789",
+					File:       &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx/site.conf", UID: "0", GID: "0", Mode: 0444},
+				}},
+				DNSConfig: &swarm.DNSConfig{Nameservers: []string{"8.8.8.8"}, Options: []string{"ndots:2"}},
+				Groups:    []string{"docker"},
+			},
+			Networks: []swarm.NetworkAttachmentConfig{{Target: "default-id", Aliases: []string{"web"}}},
+		},
+		Mode: swarm.ServiceMode{Global: &swarm.GlobalService{}},
+	}}
+	networks := map[string]types.NetworkResource{
+		"default-id": {ID: "default-id", Name: "test_default", Driver: "overlay", Labels: map[string]string{LabelNamespace: "test"}},
+	}
+
+	export := NewExport()
+	export.AddService(namespace, service, networks)
+	assert.Check(t, is.DeepEqual([]string{
+		`service "web": dns options cannot be expressed in a Compose file`,
+		`service "web": additional groups cannot be expressed in a Compose file`,
+	}, export.Warnings))
+
+	expected := composetypes.ServiceConfig{
+		Name:    "web",
+		Image:   "nginx",
+		DNS:     []string{"8.8.8.8"},
+		Configs: []composetypes.ServiceConfigObjConfig{{Source: "site", Target: "/etc/nginx/site.conf"}},
+		Deploy:  composetypes.DeployConfig{Mode: "global"},
+	}
+	assert.Check(t, is.DeepEqual(expected, export.Config.Services[0]))
+	assert.Check(t, is.DeepEqual(map[string]composetypes.ConfigObjConfig{
+		"site": {Name: "test_site-0123456789", External: composetypes.External{External: true}},
+	}, export.Config.Configs))
+	assert.Check(t, is.Len(export.Config.Networks, 0))
+}
+
+func TestExportContainer(t *testing.T) {
+	stopTimeout := 20
+	c := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    "0123456789abcdef",
+			Name:  "/web",
+			Image: "sha256:image",
+			HostConfig: &container.HostConfig{
+				NetworkMode:   "front",
+				PortBindings:  nat.PortMap{"80/tcp": {{HostPort: "8080"}}},
+				RestartPolicy: container.RestartPolicy{Name: "unless-stopped"},
+				LogConfig:     container.LogConfig{Type: "json-file"},
+				ShmSize:       defaultShmSize,
+				Resources:     container.Resources{Memory: 64 * 1024 * 1024, CPUShares: 512},
+			},
+		},
+		Config: &container.Config{
+			Image:        "nginx:1.17",
+			Hostname:     "0123456789ab",
+			Env:          []string{"PATH=/usr/bin", "LEVEL=debug"},
+			Cmd:          []string{"nginx", "-g", "daemon off;"},
+			Labels:       map[string]string{"maintainer": "nginx", "role": "front"},
+			ExposedPorts: nat.PortSet{"80/tcp": {}},
+			StopTimeout:  &stopTimeout,
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: "web-data", Destination: "/data", RW: true},
+			{Type: mount.TypeVolume, Name: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", Destination: "/cache", RW: true},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"front": {Aliases: []string{"0123456789ab", "www"}},
+			},
+		},
+	}
+	image := types.ImageInspect{Config: &container.Config{
+		Env:          []string{"PATH=/usr/bin"},
+		Cmd:          []string{"nginx", "-g", "daemon off;"},
+		Labels:       map[string]string{"maintainer": "nginx"},
+		ExposedPorts: nat.PortSet{"80/tcp": {}},
+	}}
+
+	export := NewExport()
+	export.AddContainer(c, image)
+	assert.Check(t, is.DeepEqual([]string{
+		`service "web": cpu shares, cpuset, cpu quota and memory swap cannot be expressed in a Compose file`,
+	}, export.Warnings))
+
+	level := "debug"
+	stopGracePeriod := composetypes.Duration(20 * time.Second)
+	expected := composetypes.ServiceConfig{
+		Name:            "web",
+		Image:           "nginx:1.17",
+		Environment:     composetypes.MappingWithEquals{"LEVEL": &level},
+		Labels:          composetypes.Labels{"role": "front"},
+		StopGracePeriod: &stopGracePeriod,
+		Ports:           []composetypes.ServicePortConfig{{Target: 80, Published: 8080}},
+		Deploy: composetypes.DeployConfig{
+			RestartPolicy: &composetypes.RestartPolicy{Condition: "any"},
+			Resources: composetypes.Resources{
+				Limits: &composetypes.Resource{MemoryBytes: 64 * 1024 * 1024},
+			},
+		},
+		Volumes: []composetypes.ServiceVolumeConfig{
+			{Type: "volume", Source: "web-data", Target: "/data"},
+			{Type: "volume", Target: "/cache"},
+		},
+		Networks: map[string]*composetypes.ServiceNetworkConfig{
+			"front": {Aliases: []string{"www"}},
+		},
+	}
+	assert.Check(t, is.DeepEqual(expected, export.Config.Services[0]))
+	assert.Check(t, is.DeepEqual(map[string]composetypes.VolumeConfig{
+		"web-data": {Name: "web-data", External: composetypes.External{External: true}},
+	}, export.Config.Volumes))
+	assert.Check(t, is.DeepEqual(map[string]composetypes.NetworkConfig{
+		"front": {Name: "front", External: composetypes.External{External: true}},
+	}, export.Config.Networks))
+}
+
+func TestExportContainerWithoutHostConfig(t *testing.T) {
+	c := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{ID: "0123456789abcdef", Name: "/web", Image: "sha256:image"},
+		Config: &container.Config{
+			Image:        "nginx:1.17",
+			ExposedPorts: nat.PortSet{"8080/tcp": {}},
+		},
+	}
+	export := NewExport()
+	export.AddContainer(c, types.ImageInspect{Config: &container.Config{}})
+	assert.Check(t, is.Equal("nginx:1.17", export.Config.Services[0].Image))
+}
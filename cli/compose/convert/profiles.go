@@ -0,0 +1,205 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/pkg/errors"
+)
+
+// AllProfiles is the profile that enables the services of all profiles
+const AllProfiles = "*"
+
+// ApplyProfiles returns a copy of config with only the services that have no
+// profile, or at least one of the given profiles. Networks, secrets and
+// configs that are only used by the excluded services are removed. It fails if
+// a service that is kept depends on an excluded service, or uses a network,
+// secret or config that is not defined once the excluded services are
+// removed.
+func ApplyProfiles(config *composetypes.Config, profiles []string) (*composetypes.Config, error) {
+	enabled := map[string]bool{}
+	for _, p := range profiles {
+		enabled[p] = true
+	}
+
+	result := *config
+	result.Services = nil
+	excluded := map[string]composetypes.ServiceConfig{}
+	for _, service := range config.Services {
+		if isServiceEnabled(service, enabled) {
+			result.Services = append(result.Services, service)
+		} else {
+			excluded[service.Name] = service
+		}
+	}
+	if len(excluded) == 0 {
+		return config, nil
+	}
+
+	for _, service := range result.Services {
+		for _, dependency := range serviceDependencies(service) {
+			if disabled, ok := excluded[dependency]; ok {
+				return nil, errors.Errorf("service %q depends on service %q, which is not enabled: enable one of its profiles (%s) with --profile",
+					service.Name, dependency, strings.Join(disabled.Profiles, ", "))
+			}
+		}
+	}
+
+	used := getServicesResources(result.Services)
+	excludedServices := make([]composetypes.ServiceConfig, 0, len(excluded))
+	for _, service := range excluded {
+		excludedServices = append(excludedServices, service)
+	}
+	unused := getServicesResources(excludedServices)
+
+	result.Networks = map[string]composetypes.NetworkConfig{}
+	for name, network := range config.Networks {
+		if !unused.networks[name] || used.networks[name] {
+			result.Networks[name] = network
+		}
+	}
+	result.Secrets = map[string]composetypes.SecretConfig{}
+	for name, secret := range config.Secrets {
+		if !unused.secrets[name] || used.secrets[name] {
+			result.Secrets[name] = secret
+		}
+	}
+	result.Configs = map[string]composetypes.ConfigObjConfig{}
+	for name, cfg := range config.Configs {
+		if !unused.configs[name] || used.configs[name] {
+			result.Configs[name] = cfg
+		}
+	}
+
+	for _, service := range result.Services {
+		if err := checkServiceResources(service, &result, excludedServices); err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+// checkServiceResources fails if service uses a network, secret or config that
+// is not defined in config, naming the excluded services that use it too.
+func checkServiceResources(service composetypes.ServiceConfig, config *composetypes.Config, excluded []composetypes.ServiceConfig) error {
+	resources := getServicesResources([]composetypes.ServiceConfig{service})
+	for _, name := range sortedNames(resources.networks) {
+		if _, ok := config.Networks[name]; !ok && name != defaultNetwork {
+			return undefinedResourceError(service, "network", name, excluded, func(r serviceResources) bool { return r.networks[name] })
+		}
+	}
+	for _, name := range sortedNames(resources.secrets) {
+		if _, ok := config.Secrets[name]; !ok {
+			return undefinedResourceError(service, "secret", name, excluded, func(r serviceResources) bool { return r.secrets[name] })
+		}
+	}
+	for _, name := range sortedNames(resources.configs) {
+		if _, ok := config.Configs[name]; !ok {
+			return undefinedResourceError(service, "config", name, excluded, func(r serviceResources) bool { return r.configs[name] })
+		}
+	}
+	return nil
+}
+
+func undefinedResourceError(service composetypes.ServiceConfig, kind, name string, excluded []composetypes.ServiceConfig, uses func(serviceResources) bool) error {
+	var users []string
+	for _, other := range excluded {
+		if uses(getServicesResources([]composetypes.ServiceConfig{other})) {
+			users = append(users, fmt.Sprintf("%s (%s)", other.Name, strings.Join(other.Profiles, ", ")))
+		}
+	}
+	if len(users) == 0 {
+		return errors.Errorf("service %q uses %s %q, which is not defined", service.Name, kind, name)
+	}
+	sort.Strings(users)
+	return errors.Errorf("service %q uses %s %q, which is not defined; services that are not enabled also use it: %s",
+		service.Name, kind, name, strings.Join(users, ", "))
+}
+
+func sortedNames(names map[string]bool) []string {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Profiles returns the profiles used by the services of config, sorted
+func Profiles(config *composetypes.Config) []string {
+	seen := map[string]bool{}
+	var profiles []string
+	for _, service := range config.Services {
+		for _, p := range service.Profiles {
+			if !seen[p] {
+				seen[p] = true
+				profiles = append(profiles, p)
+			}
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+func isServiceEnabled(service composetypes.ServiceConfig, enabled map[string]bool) bool {
+	if len(service.Profiles) == 0 || enabled[AllProfiles] {
+		return true
+	}
+	for _, p := range service.Profiles {
+		if enabled[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceDependencies returns the names of the services service depends on
+func serviceDependencies(service composetypes.ServiceConfig) []string {
+	dependencies := append([]string{}, service.DependsOn...)
+	for _, link := range service.Links {
+		name := strings.SplitN(link, ":", 2)[0]
+		dependencies = append(dependencies, name)
+	}
+	for _, mode := range []string{service.NetworkMode, service.Ipc, service.Pid} {
+		if strings.HasPrefix(mode, "service:") {
+			dependencies = append(dependencies, strings.TrimPrefix(mode, "service:"))
+		}
+	}
+	return dependencies
+}
+
+type serviceResources struct {
+	networks map[string]bool
+	secrets  map[string]bool
+	configs  map[string]bool
+}
+
+// getServicesResources returns the networks, secrets and configs used by
+// services
+func getServicesResources(services []composetypes.ServiceConfig) serviceResources {
+	resources := serviceResources{
+		networks: map[string]bool{},
+		secrets:  map[string]bool{},
+		configs:  map[string]bool{},
+	}
+	for _, service := range services {
+		if len(service.Networks) == 0 {
+			resources.networks[defaultNetwork] = true
+		}
+		for network := range service.Networks {
+			resources.networks[network] = true
+		}
+		for _, secret := range service.Secrets {
+			resources.secrets[secret.Source] = true
+		}
+		for _, cfg := range service.Configs {
+			resources.configs[cfg.Source] = true
+		}
+		if service.CredentialSpec.Config != "" {
+			resources.configs[service.CredentialSpec.Config] = true
+		}
+	}
+	return resources
+}
@@ -0,0 +1,124 @@
+package convert
+
+import (
+	"testing"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func profilesConfig() *composetypes.Config {
+	return &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{
+				Name:    "web",
+				Secrets: []composetypes.ServiceSecretConfig{{Source: "shared"}},
+			},
+			{
+				Name:     "debug",
+				Profiles: []string{"debug"},
+				Networks: map[string]*composetypes.ServiceNetworkConfig{"tools": nil},
+				Secrets:  []composetypes.ServiceSecretConfig{{Source: "shared"}, {Source: "debug_token"}},
+				Configs:  []composetypes.ServiceConfigObjConfig{{Source: "debug_config"}},
+			},
+			{
+				Name:     "tools",
+				Profiles: []string{"tools", "debug"},
+			},
+		},
+		Networks: map[string]composetypes.NetworkConfig{"default": {}, "tools": {}},
+		Secrets:  map[string]composetypes.SecretConfig{"shared": {}, "debug_token": {}, "unused": {}},
+		Configs:  map[string]composetypes.ConfigObjConfig{"debug_config": {}},
+	}
+}
+
+func serviceNames(config *composetypes.Config) []string {
+	var names []string
+	for _, service := range config.Services {
+		names = append(names, service.Name)
+	}
+	return names
+}
+
+func TestApplyProfilesWithoutProfile(t *testing.T) {
+	config, err := ApplyProfiles(profilesConfig(), nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"web"}, serviceNames(config)))
+	assert.Check(t, is.DeepEqual(map[string]composetypes.NetworkConfig{"default": {}}, config.Networks))
+	assert.Check(t, is.DeepEqual(map[string]composetypes.SecretConfig{"shared": {}, "unused": {}}, config.Secrets))
+	assert.Check(t, is.Len(config.Configs, 0))
+}
+
+func TestApplyProfilesWithProfile(t *testing.T) {
+	config, err := ApplyProfiles(profilesConfig(), []string{"tools"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"web", "tools"}, serviceNames(config)))
+
+	config, err = ApplyProfiles(profilesConfig(), []string{"debug"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"web", "debug", "tools"}, serviceNames(config)))
+	assert.Check(t, is.Len(config.Networks, 2))
+	assert.Check(t, is.Len(config.Secrets, 3))
+	assert.Check(t, is.Len(config.Configs, 1))
+}
+
+func TestApplyProfilesAllProfiles(t *testing.T) {
+	config, err := ApplyProfiles(profilesConfig(), []string{AllProfiles})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"web", "debug", "tools"}, serviceNames(config)))
+}
+
+func TestApplyProfilesDependencyOnExcludedService(t *testing.T) {
+	testCases := []composetypes.ServiceConfig{
+		{Name: "web", DependsOn: []string{"debug"}},
+		{Name: "web", Links: []string{"debug:db"}},
+		{Name: "web", NetworkMode: "service:debug"},
+	}
+	for _, web := range testCases {
+		config := profilesConfig()
+		config.Services[0] = web
+		_, err := ApplyProfiles(config, []string{"tools"})
+		assert.Check(t, is.Error(err, `service "web" depends on service "debug", which is not enabled: enable one of its profiles (debug) with --profile`))
+	}
+}
+
+func TestApplyProfilesUndefinedResource(t *testing.T) {
+	testCases := []struct {
+		web      composetypes.ServiceConfig
+		expected string
+	}{
+		{
+			web:      composetypes.ServiceConfig{Name: "web", Networks: map[string]*composetypes.ServiceNetworkConfig{"backend": nil}},
+			expected: `service "web" uses network "backend", which is not defined`,
+		},
+		{
+			web:      composetypes.ServiceConfig{Name: "web", Secrets: []composetypes.ServiceSecretConfig{{Source: "missing"}}},
+			expected: `service "web" uses secret "missing", which is not defined`,
+		},
+		{
+			web:      composetypes.ServiceConfig{Name: "web", Configs: []composetypes.ServiceConfigObjConfig{{Source: "missing"}}},
+			expected: `service "web" uses config "missing", which is not defined`,
+		},
+		{
+			web:      composetypes.ServiceConfig{Name: "web", CredentialSpec: composetypes.CredentialSpecConfig{Config: "missing"}},
+			expected: `service "web" uses config "missing", which is not defined`,
+		},
+	}
+	for _, tc := range testCases {
+		config := profilesConfig()
+		config.Services[0] = tc.web
+		_, err := ApplyProfiles(config, nil)
+		assert.Check(t, is.Error(err, tc.expected))
+	}
+
+	config := profilesConfig()
+	config.Services[0].Secrets = append(config.Services[0].Secrets, composetypes.ServiceSecretConfig{Source: "debug_token"})
+	delete(config.Secrets, "debug_token")
+	_, err := ApplyProfiles(config, []string{"tools"})
+	assert.Check(t, is.Error(err, `service "web" uses secret "debug_token", which is not defined; services that are not enabled also use it: debug (debug)`))
+}
+
+func TestProfiles(t *testing.T) {
+	assert.Check(t, is.DeepEqual([]string{"debug", "tools"}, Profiles(profilesConfig())))
+}
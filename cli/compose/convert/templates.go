@@ -0,0 +1,84 @@
+package convert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"text/template"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/templates"
+	"github.com/pkg/errors"
+)
+
+// TemplateData is the data the configs and secrets that are templates are
+// rendered with
+type TemplateData struct {
+	// Env holds the variables the Compose files are interpolated with
+	Env map[string]string
+	// Stack is the stack the object is deployed in
+	Stack TemplateStack
+	// Name is the name of the object in the Compose file
+	Name string
+}
+
+// TemplateStack is the stack metadata available to templates
+type TemplateStack struct {
+	Name     string
+	Services []string
+}
+
+// RenderTemplates returns a copy of config in which the configs and secrets
+// that are templates are rendered with env and the metadata of the stack.
+func RenderTemplates(namespace Namespace, config *composetypes.Config, env map[string]string) (*composetypes.Config, error) {
+	result := *config
+	stack := TemplateStack{Name: namespace.Name()}
+	for _, service := range config.Services {
+		stack.Services = append(stack.Services, service.Name)
+	}
+
+	result.Secrets = make(map[string]composetypes.SecretConfig, len(config.Secrets))
+	for name, secret := range config.Secrets {
+		obj, err := renderFileObject(name, composetypes.FileObjectConfig(secret), TemplateData{Env: env, Stack: stack, Name: name})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render secret %s", name)
+		}
+		result.Secrets[name] = composetypes.SecretConfig(obj)
+	}
+	result.Configs = make(map[string]composetypes.ConfigObjConfig, len(config.Configs))
+	for name, cfg := range config.Configs {
+		obj, err := renderFileObject(name, composetypes.FileObjectConfig(cfg), TemplateData{Env: env, Stack: stack, Name: name})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render config %s", name)
+		}
+		result.Configs[name] = composetypes.ConfigObjConfig(obj)
+	}
+	return &result, nil
+}
+
+func renderFileObject(name string, obj composetypes.FileObjectConfig, data TemplateData) (composetypes.FileObjectConfig, error) {
+	if !obj.Template || obj.External.External {
+		return obj, nil
+	}
+	source, err := ioutil.ReadFile(obj.File)
+	if err != nil {
+		return obj, err
+	}
+	tmpl, err := templates.New(name).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return obj, err
+	}
+	content, err := execute(tmpl, data)
+	if err != nil {
+		return obj, err
+	}
+	obj.Content = content
+	return obj, nil
+}
+
+func execute(tmpl *template.Template, data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
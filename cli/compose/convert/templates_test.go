@@ -0,0 +1,73 @@
+package convert
+
+import (
+	"testing"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestRenderTemplates(t *testing.T) {
+	namespace := Namespace{name: "foo"}
+
+	configFile := fs.NewFile(t, "convert-templates", fs.WithContent(
+		`{{.Name}} of {{.Stack.Name}} ({{join .Stack.Services ","}}) at {{.Env.LEVEL}}`))
+	defer configFile.Remove()
+	staticFile := fs.NewFile(t, "convert-templates", fs.WithContent("{{.Name}}"))
+	defer staticFile.Remove()
+
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{{Name: "web"}, {Name: "db"}},
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"app":    {File: configFile.Path(), Template: true},
+			"named":  {File: configFile.Path(), Template: true, Name: "app-config"},
+			"static": {File: staticFile.Path()},
+		},
+		Secrets: map[string]composetypes.SecretConfig{
+			"token": {File: configFile.Path(), Template: true},
+		},
+	}
+
+	rendered, err := RenderTemplates(namespace, config, map[string]string{"LEVEL": "debug"})
+	assert.NilError(t, err)
+
+	app := rendered.Configs["app"]
+	assert.Check(t, is.Equal("app of foo (web,db) at debug", app.Content))
+	assert.Check(t, is.Equal("named of foo (web,db) at debug", rendered.Configs["named"].Content))
+	assert.Check(t, is.DeepEqual(config.Configs["static"], rendered.Configs["static"]))
+	assert.Check(t, is.Equal("token of foo (web,db) at debug", rendered.Secrets["token"].Content))
+	// the original config is left untouched
+	assert.Check(t, is.Equal("", config.Configs["app"].Content))
+
+	specs, err := Configs(namespace, map[string]composetypes.ConfigObjConfig{"app": app})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(specs, 1))
+	assert.Check(t, is.Equal("foo_app", specs[0].Name))
+	assert.Check(t, is.DeepEqual([]byte("app of foo (web,db) at debug"), specs[0].Data))
+}
+
+func TestRenderTemplatesErrors(t *testing.T) {
+	namespace := Namespace{name: "foo"}
+
+	configFile := fs.NewFile(t, "convert-templates", fs.WithContent("level={{.Env.LEVEL}}"))
+	defer configFile.Remove()
+	invalidFile := fs.NewFile(t, "convert-templates", fs.WithContent("{{.Env.LEVEL"))
+	defer invalidFile.Remove()
+
+	_, err := RenderTemplates(namespace, &composetypes.Config{
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"app": {File: configFile.Path(), Template: true},
+		},
+	}, map[string]string{})
+	assert.Check(t, is.ErrorContains(err, "failed to render config app"))
+	assert.Check(t, is.ErrorContains(err, `map has no entry for key "LEVEL"`))
+
+	_, err = RenderTemplates(namespace, &composetypes.Config{
+		Secrets: map[string]composetypes.SecretConfig{
+			"token": {File: invalidFile.Path(), Template: true},
+		},
+	}, map[string]string{})
+	assert.Check(t, is.ErrorContains(err, "failed to render secret token"))
+}
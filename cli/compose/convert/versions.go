@@ -0,0 +1,73 @@
+package convert
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/pkg/errors"
+)
+
+// contentHashLength is the number of hexadecimal digits of the content hash
+// appended to the name of the configs and secrets
+const contentHashLength = 10
+
+// maxNameLength is the maximum length of the name of a swarm object
+const maxNameLength = 64
+
+// VersionFileObjects returns a copy of config in which the name of each config
+// and secret created from a file ends with a hash of its content. Configs and
+// secrets are immutable, so a new version of the object is created when the
+// content changes, and the services using it are updated to use the new
+// version. External objects, objects with an explicit name that are not
+// rendered from a template, and secrets created by a driver, are not
+// versioned.
+func VersionFileObjects(namespace Namespace, config *composetypes.Config) (*composetypes.Config, error) {
+	result := *config
+	result.Secrets = make(map[string]composetypes.SecretConfig, len(config.Secrets))
+	for name, secret := range config.Secrets {
+		obj, err := versionFileObject(namespace, name, composetypes.FileObjectConfig(secret))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read secret %s", name)
+		}
+		result.Secrets[name] = composetypes.SecretConfig(obj)
+	}
+	result.Configs = make(map[string]composetypes.ConfigObjConfig, len(config.Configs))
+	for name, cfg := range config.Configs {
+		obj, err := versionFileObject(namespace, name, composetypes.FileObjectConfig(cfg))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read config %s", name)
+		}
+		result.Configs[name] = composetypes.ConfigObjConfig(obj)
+	}
+	return &result, nil
+}
+
+func versionFileObject(namespace Namespace, name string, obj composetypes.FileObjectConfig) (composetypes.FileObjectConfig, error) {
+	if obj.External.External || obj.Driver != "" || (obj.Name != "" && !obj.Template) {
+		return obj, nil
+	}
+	content := []byte(obj.Content)
+	if !obj.Template {
+		var err error
+		if content, err = ioutil.ReadFile(obj.File); err != nil {
+			return obj, err
+		}
+	}
+	if obj.Name == "" {
+		obj.Name = namespace.Scope(name)
+	}
+	versioned := obj.Name + "-" + contentHash(content)
+	if len(versioned) > maxNameLength {
+		return obj, errors.Errorf("versioned name %s is longer than %d characters, use a shorter name", versioned, maxNameLength)
+	}
+	obj.Name = versioned
+	return obj, nil
+}
+
+// contentHash returns the truncated hexadecimal sha256 hash of content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:contentHashLength]
+}
@@ -0,0 +1,121 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestVersionFileObjects(t *testing.T) {
+	namespace := Namespace{name: "foo"}
+
+	file := fs.NewFile(t, "convert-versions", fs.WithContent("content"))
+	defer file.Remove()
+	hash := contentHash([]byte("content"))
+
+	config := &composetypes.Config{
+		Secrets: map[string]composetypes.SecretConfig{
+			"file":     {File: file.Path()},
+			"named":    {File: file.Path(), Name: "named-secret"},
+			"external": {Name: "external", External: composetypes.External{External: true}},
+			"driver":   {Driver: "vault"},
+		},
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"file":     {File: file.Path()},
+			"rendered": {File: file.Path(), Template: true, Content: "rendered"},
+			"named":    {File: file.Path(), Template: true, Content: "rendered", Name: "named-config"},
+			"static":   {File: file.Path(), Name: "static-config"},
+		},
+	}
+
+	versioned, err := VersionFileObjects(namespace, config)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("foo_file-"+hash, versioned.Secrets["file"].Name))
+	assert.Check(t, is.Equal("named-secret", versioned.Secrets["named"].Name))
+	assert.Check(t, is.Equal("external", versioned.Secrets["external"].Name))
+	assert.Check(t, is.Equal("", versioned.Secrets["driver"].Name))
+	assert.Check(t, is.Equal("foo_file-"+hash, versioned.Configs["file"].Name))
+	assert.Check(t, is.Equal("foo_rendered-"+contentHash([]byte("rendered")), versioned.Configs["rendered"].Name))
+	assert.Check(t, is.Equal("named-config-"+contentHash([]byte("rendered")), versioned.Configs["named"].Name))
+	assert.Check(t, is.Equal("static-config", versioned.Configs["static"].Name))
+	// the original config is left untouched
+	assert.Check(t, is.Equal("", config.Secrets["file"].Name))
+
+	specs, err := Secrets(namespace, map[string]composetypes.SecretConfig{"file": versioned.Secrets["file"]})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(specs, 1))
+	assert.Check(t, is.Equal("foo_file-"+hash, specs[0].Name))
+	assert.Check(t, is.DeepEqual([]byte("content"), specs[0].Data))
+}
+
+func TestVersionFileObjectsContentChange(t *testing.T) {
+	namespace := Namespace{name: "foo"}
+
+	file := fs.NewFile(t, "convert-versions", fs.WithContent("v1"))
+	defer file.Remove()
+	config := &composetypes.Config{
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"app": {File: file.Path()},
+		},
+	}
+
+	first, err := VersionFileObjects(namespace, config)
+	assert.NilError(t, err)
+	second, err := VersionFileObjects(namespace, config)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(first.Configs["app"].Name, second.Configs["app"].Name))
+
+	fs.Apply(t, file, fs.WithContent("v2"))
+	changed, err := VersionFileObjects(namespace, config)
+	assert.NilError(t, err)
+	assert.Check(t, first.Configs["app"].Name != changed.Configs["app"].Name)
+}
+
+func TestVersionFileObjectsRenderedContentChange(t *testing.T) {
+	namespace := Namespace{name: "foo"}
+
+	configFile := fs.NewFile(t, "convert-versions", fs.WithContent("level={{.Env.LEVEL}}"))
+	defer configFile.Remove()
+	config := &composetypes.Config{
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"app": {File: configFile.Path(), Template: true},
+		},
+	}
+	renderAndVersion := func(level string) string {
+		rendered, err := RenderTemplates(namespace, config, map[string]string{"LEVEL": level})
+		assert.NilError(t, err)
+		versioned, err := VersionFileObjects(namespace, rendered)
+		assert.NilError(t, err)
+		return versioned.Configs["app"].Name
+	}
+
+	first := renderAndVersion("info")
+	assert.Check(t, is.Equal("foo_app-"+contentHash([]byte("level=info")), first))
+	assert.Check(t, is.Equal(first, renderAndVersion("info")))
+	assert.Check(t, first != renderAndVersion("debug"))
+}
+
+func TestVersionFileObjectsMissingFile(t *testing.T) {
+	_, err := VersionFileObjects(Namespace{name: "foo"}, &composetypes.Config{
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"app": {File: "/no/such/file"},
+		},
+	})
+	assert.Check(t, is.ErrorContains(err, "failed to read config app"))
+}
+
+func TestVersionFileObjectsNameTooLong(t *testing.T) {
+	file := fs.NewFile(t, "convert-versions", fs.WithContent("content"))
+	defer file.Remove()
+
+	_, err := VersionFileObjects(Namespace{name: strings.Repeat("s", 50)}, &composetypes.Config{
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"app": {File: file.Path()},
+		},
+	})
+	assert.Check(t, is.ErrorContains(err, "is longer than 64 characters"))
+}
@@ -1,7 +1,10 @@
 package interpolation
 
 import (
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli/compose/template"
@@ -16,6 +19,9 @@ type Options struct {
 	TypeCastMapping map[Path]Cast
 	// Substitution function to use
 	Substitute func(string, template.Mapping) (string, error)
+	// Strict makes Interpolate fail if variables that are referenced without
+	// a default value are not set, reporting all of them
+	Strict bool
 }
 
 // LookupValue is a function which maps from variable names to values.
@@ -41,8 +47,14 @@ func Interpolate(config map[string]interface{}, opts Options) (map[string]interf
 
 	out := map[string]interface{}{}
 
+	if opts.Strict {
+		if unset := unsetVariables(config, "", nil, opts.LookupValue); len(unset) > 0 {
+			return out, &UnsetVariablesError{Variables: unset}
+		}
+	}
+
 	for key, value := range config {
-		interpolatedValue, err := recursiveInterpolate(value, NewPath(key), opts)
+		interpolatedValue, err := recursiveInterpolate(value, NewPath(key), []string{key}, opts)
 		if err != nil {
 			return out, err
 		}
@@ -52,25 +64,25 @@ func Interpolate(config map[string]interface{}, opts Options) (map[string]interf
 	return out, nil
 }
 
-func recursiveInterpolate(value interface{}, path Path, opts Options) (interface{}, error) {
+func recursiveInterpolate(value interface{}, path Path, keys []string, opts Options) (interface{}, error) {
 	switch value := value.(type) {
 
 	case string:
 		newValue, err := opts.Substitute(value, template.Mapping(opts.LookupValue))
 		if err != nil || newValue == value {
-			return value, newPathError(path, err)
+			return value, newPathError(path, keys, err)
 		}
 		caster, ok := opts.getCasterForPath(path)
 		if !ok {
 			return newValue, nil
 		}
 		casted, err := caster(newValue)
-		return casted, newPathError(path, errors.Wrap(err, "failed to cast to expected type"))
+		return casted, newPathError(path, keys, errors.Wrap(err, "failed to cast to expected type"))
 
 	case map[string]interface{}:
 		out := map[string]interface{}{}
 		for key, elem := range value {
-			interpolatedElem, err := recursiveInterpolate(elem, path.Next(key), opts)
+			interpolatedElem, err := recursiveInterpolate(elem, path.Next(key), appendKey(keys, key), opts)
 			if err != nil {
 				return nil, err
 			}
@@ -81,7 +93,7 @@ func recursiveInterpolate(value interface{}, path Path, opts Options) (interface
 	case []interface{}:
 		out := make([]interface{}, len(value))
 		for i, elem := range value {
-			interpolatedElem, err := recursiveInterpolate(elem, path.Next(PathMatchList), opts)
+			interpolatedElem, err := recursiveInterpolate(elem, path.Next(PathMatchList), appendKey(keys, strconv.Itoa(i)), opts)
 			if err != nil {
 				return nil, err
 			}
@@ -95,19 +107,109 @@ func recursiveInterpolate(value interface{}, path Path, opts Options) (interface
 	}
 }
 
-func newPathError(path Path, err error) error {
+// UnsetVariable is a variable referenced without a default value that is not
+// set, and where it is referenced
+type UnsetVariable struct {
+	// Filename is the file the variable is referenced in, if known
+	Filename string
+	// Line is the line the variable is referenced on, if known
+	Line int
+	// Path is the path of the value referencing the variable, such as
+	// "services.web.environment[0]"
+	Path string
+	// Keys are the keys and the item indexes of Path, such as "services",
+	// "web", "environment" and "0"
+	Keys []string
+	Name string
+}
+
+// UnsetVariablesError is returned by Interpolate in strict mode, and lists
+// all the variables that are not set
+type UnsetVariablesError struct {
+	Variables []UnsetVariable
+}
+
+func (e *UnsetVariablesError) Error() string {
+	lines := []string{"required variables are not set:"}
+	for _, v := range e.Variables {
+		location := v.Path
+		switch {
+		case v.Filename != "" && v.Line > 0:
+			location = fmt.Sprintf("%s:%d: %s", v.Filename, v.Line, v.Path)
+		case v.Filename != "":
+			location = v.Filename + ": " + v.Path
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", location, v.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unsetVariables walks value and returns the variables it references without
+// a default value that are not set, in a stable order
+func unsetVariables(value interface{}, path string, keys []string, lookupValue LookupValue) []UnsetVariable {
+	var unset []UnsetVariable
+	switch value := value.(type) {
+	case string:
+		for _, name := range template.UnsetVariables(value, template.Mapping(lookupValue)) {
+			unset = append(unset, UnsetVariable{Path: path, Keys: keys, Name: name})
+		}
+	case map[string]interface{}:
+		names := make([]string, 0, len(value))
+		for key := range value {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+		for _, key := range names {
+			next := key
+			if path != "" {
+				next = path + pathSeparator + key
+			}
+			unset = append(unset, unsetVariables(value[key], next, appendKey(keys, key), lookupValue)...)
+		}
+	case []interface{}:
+		for i, elem := range value {
+			unset = append(unset, unsetVariables(elem, fmt.Sprintf("%s[%d]", path, i), appendKey(keys, strconv.Itoa(i)), lookupValue)...)
+		}
+	}
+	return unset
+}
+
+// PathError is an error interpolating the value at Path
+type PathError struct {
+	Path Path
+	// Keys are the keys and the item indexes of the value, where Path only
+	// has a PathMatchList token for the items
+	Keys []string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the underlying error
+func (e *PathError) Cause() error {
+	return e.Err
+}
+
+func newPathError(path Path, keys []string, err error) error {
 	switch err := err.(type) {
 	case nil:
 		return nil
 	case *template.InvalidTemplateError:
-		return errors.Errorf(
+		return &PathError{Path: path, Keys: keys, Err: errors.Errorf(
 			"invalid interpolation format for %s: %#v. You may need to escape any $ with another $.",
-			path, err.Template)
+			path, err.Template)}
 	default:
-		return errors.Wrapf(err, "error while interpolating %s", path)
+		return &PathError{Path: path, Keys: keys, Err: errors.Wrapf(err, "error while interpolating %s", path)}
 	}
 }
 
+// appendKey returns a copy of keys, followed by key
+func appendKey(keys []string, key string) []string {
+	return append(keys[:len(keys):len(keys)], key)
+}
+
 const pathSeparator = "."
 
 // PathMatchAll is a token used as part of a Path to match any key at that level
@@ -61,6 +61,22 @@ func TestInvalidInterpolation(t *testing.T) {
 	assert.Error(t, err, `invalid interpolation format for servicea.image: "${". You may need to escape any $ with another $.`)
 }
 
+func TestInvalidInterpolationPathError(t *testing.T) {
+	services := map[string]interface{}{
+		"servicea": map[string]interface{}{
+			"labels": map[string]interface{}{
+				"com.example.description": "ok",
+			},
+			"environment": []interface{}{"FOO=bar", "BAR=${"},
+		},
+	}
+	_, err := Interpolate(services, Options{LookupValue: defaultMapping})
+	pathErr, ok := err.(*PathError)
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(NewPath("servicea", "environment", PathMatchList), pathErr.Path))
+	assert.Check(t, is.DeepEqual([]string{"servicea", "environment", "1"}, pathErr.Keys))
+}
+
 func TestInterpolateWithDefaults(t *testing.T) {
 	defer env.Patch(t, "FOO", "BARZ")()
 
@@ -145,3 +161,33 @@ func TestPathMatches(t *testing.T) {
 		assert.Check(t, is.Equal(testcase.expected, testcase.path.matches(testcase.pattern)))
 	}
 }
+
+func TestInterpolateStrict(t *testing.T) {
+	services := map[string]interface{}{
+		"servicea": map[string]interface{}{
+			"image":       "example:${TAG}",
+			"environment": []interface{}{"USER=$USER", "HOME=${HOME_DIR:-/home/$MISSING}", "OPT=${FOO:+$UNSET_ALT}"},
+			"logging": map[string]interface{}{
+				"driver": "${FOO:-$NOT_USED}",
+			},
+		},
+	}
+	_, err := Interpolate(services, Options{LookupValue: defaultMapping, Strict: true})
+	assert.Check(t, is.DeepEqual(&UnsetVariablesError{Variables: []UnsetVariable{
+		{Path: "servicea.environment[1]", Keys: []string{"servicea", "environment", "1"}, Name: "MISSING"},
+		{Path: "servicea.environment[2]", Keys: []string{"servicea", "environment", "2"}, Name: "UNSET_ALT"},
+		{Path: "servicea.image", Keys: []string{"servicea", "image"}, Name: "TAG"},
+	}}, err))
+	assert.Check(t, is.Error(err, `required variables are not set:
+  servicea.environment[1]: MISSING
+  servicea.environment[2]: UNSET_ALT
+  servicea.image: TAG`))
+
+	services = map[string]interface{}{
+		"servicea": map[string]interface{}{
+			"image": "example:${TAG:-latest}",
+		},
+	}
+	_, err = Interpolate(services, Options{LookupValue: defaultMapping, Strict: true})
+	assert.NilError(t, err)
+}
@@ -0,0 +1,174 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	interp "github.com/docker/cli/cli/compose/interpolation"
+	"github.com/docker/cli/cli/compose/schema"
+	"github.com/docker/cli/cli/compose/types"
+)
+
+// Severities of the issues found by Check
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is an error or a warning found in a Compose file
+type Issue struct {
+	Filename string
+	// Position is the position of the value the issue relates to, if known
+	Position types.Position
+	// Path is the path of the value the issue relates to, such as
+	// "services.web.ports.0"
+	Path     string
+	Severity string
+	Message  string
+}
+
+func (i Issue) String() string {
+	location := i.Filename
+	if i.Position.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", i.Filename, i.Position.Line, i.Position.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, i.Severity, i.Message)
+}
+
+// Check loads the Compose files of configDetails like Load, but rather than
+// failing on the first error, it returns all the errors and warnings it finds,
+// ordered by file and position. Checks that need a valid file, such as loading
+// its sections or merging it, are skipped for files with errors.
+func Check(configDetails types.ConfigDetails, options ...func(*Options)) []Issue {
+	_, loadIssues := load(configDetails, options)
+
+	var issues []Issue
+	for _, issue := range loadIssues {
+		issues = append(issues, issue.toIssue(configDetails.ConfigFiles))
+	}
+	return issues
+}
+
+// issue is an error or a warning found loading Compose files
+type issue struct {
+	severity string
+	// filename is the file the issue is in, if known
+	filename string
+	// path is the path of the value the issue relates to, if any
+	path types.Path
+	err  error
+}
+
+// newIssue returns an error issue for an error loading the Compose file
+// filename, or merging the files
+func newIssue(filename string, err error) issue {
+	i := issue{severity: SeverityError, filename: filename, err: err}
+	switch e := err.(type) {
+	case *pathError:
+		i.path = e.path
+		if e.filename != "" {
+			i.filename = e.filename
+		}
+	case *schema.Error:
+		i.path = e.Path
+	case *interp.PathError:
+		i.path = types.Path(e.Keys)
+	}
+	return i
+}
+
+// positions returns the positions of the value the issue relates to, if known
+func (i issue) positions(files []types.ConfigFile) *types.Positions {
+	if len(i.path) == 0 {
+		return nil
+	}
+	for _, file := range files {
+		if file.Filename == i.filename {
+			return file.Positions.Lookup(i.path)
+		}
+	}
+	return nil
+}
+
+func (i issue) toIssue(files []types.ConfigFile) Issue {
+	result := Issue{
+		Filename: i.filename,
+		Path:     i.path.String(),
+		Severity: i.severity,
+		Message:  i.err.Error(),
+	}
+	if e, ok := i.err.(*schema.Error); ok {
+		// report the offending value, rather than its field
+		result.Message = strings.TrimPrefix(e.Path.String()+" "+e.Description, " ")
+	}
+	if positions := i.positions(files); positions != nil {
+		result.Position = positions.Position
+	}
+	return result
+}
+
+// sortIssues sorts the issues of a file by position
+func sortIssues(files []types.ConfigFile, issues []issue) {
+	position := func(i issue) types.Position {
+		if positions := i.positions(files); positions != nil {
+			return positions.Position
+		}
+		return types.Position{}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := position(issues[i]), position(issues[j])
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+func hasErrors(issues []issue) bool {
+	for _, issue := range issues {
+		if issue.severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// unsetVariableError is a variable referenced without a default value that is
+// not set, which Load reports with the unset variables of all the files
+type unsetVariableError struct {
+	variable interp.UnsetVariable
+}
+
+func (e *unsetVariableError) Error() string {
+	return fmt.Sprintf("required variable %s is not set", e.variable.Name)
+}
+
+// forbiddenPropertyError is a forbidden property set on a service, which Load
+// reports with the other forbidden properties of the file
+type forbiddenPropertyError struct {
+	property    string
+	description string
+}
+
+func (e *forbiddenPropertyError) Error() string {
+	return fmt.Sprintf("unsupported option %q: %s", e.property, e.description)
+}
+
+func sortedServiceNames(services map[string]interface{}) []string {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedProperties(properties map[string]string) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
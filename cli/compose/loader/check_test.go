@@ -0,0 +1,193 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func buildCheckDetails(t *testing.T, env map[string]string, sources map[string]string, filenames ...string) types.ConfigDetails {
+	details := types.ConfigDetails{
+		WorkingDir:  ".",
+		Environment: env,
+	}
+	for _, filename := range filenames {
+		source := []byte(sources[filename])
+		config, err := ParseYAML(source)
+		assert.NilError(t, err)
+		details.ConfigFiles = append(details.ConfigFiles, types.ConfigFile{
+			Filename:  filename,
+			Config:    config,
+			Positions: ParsePositions(filename, source),
+		})
+	}
+	return details
+}
+
+func TestCheck(t *testing.T) {
+	details := buildCheckDetails(t, nil, map[string]string{
+		"docker-compose.yml": `version: "3.8"
+services:
+  web:
+    image: 1
+    restart: always
+    container_name: web
+    ports:
+      - published: 80
+        unknown: 80
+  db:
+    image: postgres
+    volumes:
+      - data:/var/lib/postgresql/data
+`,
+	}, "docker-compose.yml")
+
+	issues := Check(details)
+	assert.Check(t, is.DeepEqual([]Issue{
+		{
+			Filename: "docker-compose.yml",
+			Position: types.Position{Line: 4, Column: 5},
+			Path:     "services.web.image",
+			Severity: SeverityError,
+			Message:  "services.web.image must be a string",
+		},
+		{
+			Filename: "docker-compose.yml",
+			Position: types.Position{Line: 5, Column: 5},
+			Path:     "services.web.restart",
+			Severity: SeverityWarning,
+			Message:  `ignoring unsupported option "restart"`,
+		},
+		{
+			Filename: "docker-compose.yml",
+			Position: types.Position{Line: 6, Column: 5},
+			Path:     "services.web.container_name",
+			Severity: SeverityWarning,
+			Message:  `ignoring deprecated option "container_name": Setting the container name is not supported.`,
+		},
+		{
+			Filename: "docker-compose.yml",
+			Position: types.Position{Line: 9, Column: 9},
+			Path:     "services.web.ports.0.unknown",
+			Severity: SeverityError,
+			Message:  "services.web.ports.0.unknown Additional property unknown is not allowed",
+		},
+	}, issues))
+	assert.Check(t, is.Equal("docker-compose.yml:4:5: error: services.web.image must be a string", issues[0].String()))
+}
+
+func TestCheckLoadErrors(t *testing.T) {
+	details := buildCheckDetails(t, map[string]string{}, map[string]string{
+		"base.yml": `version: "3.8"
+services:
+  web:
+    image: nginx
+`,
+		"override.yml": `version: "3.8"
+services:
+  web:
+    image: nginx
+volumes:
+  data:
+    external: true
+    driver: local
+`,
+	}, "base.yml", "override.yml")
+
+	issues := Check(details)
+	assert.Check(t, is.DeepEqual([]Issue{{
+		Filename: "override.yml",
+		Position: types.Position{Line: 6, Column: 3},
+		Path:     "volumes.data",
+		Severity: SeverityError,
+		Message:  `conflicting parameters "external" and "driver" specified for volume "data"`,
+	}}, issues))
+}
+
+func TestCheckUnsetVariables(t *testing.T) {
+	details := buildCheckDetails(t, map[string]string{}, map[string]string{
+		"docker-compose.yml": `version: "3.8"
+services:
+  web:
+    image: nginx:${TAG}
+    environment:
+      - LEVEL=${LEVEL:-info}
+`,
+	}, "docker-compose.yml")
+
+	assert.Check(t, is.Len(Check(details), 0))
+
+	issues := Check(details, func(options *Options) {
+		options.Interpolate.Strict = true
+	})
+	assert.Check(t, is.DeepEqual([]Issue{{
+		Filename: "docker-compose.yml",
+		Position: types.Position{Line: 4, Column: 5},
+		Path:     "services.web.image",
+		Severity: SeverityError,
+		Message:  "required variable TAG is not set",
+	}}, issues))
+}
+
+func TestCheckDottedKeys(t *testing.T) {
+	details := buildCheckDetails(t, nil, map[string]string{
+		"docker-compose.yml": `version: "3.8"
+services:
+  web:
+    image: nginx
+    labels:
+      com.example: web
+      com.example.description: {}
+`,
+	}, "docker-compose.yml")
+
+	issues := Check(details)
+	assert.Assert(t, is.Len(issues, 1))
+	assert.Check(t, is.Equal("services.web.labels.com.example.description", issues[0].Path))
+	assert.Check(t, is.Equal(types.Position{Line: 7, Column: 7}, issues[0].Position))
+}
+
+func TestCheckMergedAnchor(t *testing.T) {
+	details := buildCheckDetails(t, nil, map[string]string{
+		"docker-compose.yml": `version: "3.8"
+x-defaults: &defaults
+  image: 1
+services:
+  web:
+    <<: *defaults
+`,
+	}, "docker-compose.yml")
+
+	issues := Check(details)
+	assert.Assert(t, is.Len(issues, 1))
+	assert.Check(t, is.Equal("services.web.image", issues[0].Path))
+	// the value is set by the anchor
+	assert.Check(t, is.Equal(types.Position{Line: 3, Column: 3}, issues[0].Position))
+}
+
+func TestLoadFailsWithFirstCheckIssue(t *testing.T) {
+	details := buildCheckDetails(t, nil, map[string]string{
+		"docker-compose.yml": `version: "3.8"
+services:
+  web:
+    image: nginx
+    restart: always
+    ports:
+      - target: "80"
+  db:
+    image: 1
+`,
+	}, "docker-compose.yml")
+
+	issues := Check(details)
+	assert.Assert(t, is.Len(issues, 3))
+	assert.Check(t, is.Equal(SeverityWarning, issues[0].Severity))
+
+	_, err := Load(details)
+	positionErr, ok := err.(*PositionError)
+	assert.Assert(t, ok, "unexpected error: %v", err)
+	assert.Check(t, is.Equal(issues[1].Position, positionErr.Position))
+	assert.Check(t, is.Error(err, "docker-compose.yml:7:9: services.web.ports.0.target must be a integer"))
+}
@@ -58,6 +58,19 @@ func Load(configDetails types.ConfigDetails, options ...func(*Options)) (*types.
 		return nil, errors.Errorf("No files specified")
 	}
 
+	config, issues := load(configDetails, options)
+	if err := loadError(configDetails.ConfigFiles, issues); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// load loads the Compose files of configDetails like Load, but rather than
+// failing on the first error, it returns all the errors and warnings it finds,
+// ordered by file and position. Steps that need a valid file, such as loading
+// its sections, are skipped for the files with errors, and the files are only
+// merged if none has errors.
+func load(configDetails types.ConfigDetails, options []func(*Options)) (*types.Config, []issue) {
 	opts := &Options{
 		Interpolate: &interp.Options{
 			Substitute:      template.Substitute,
@@ -70,58 +83,157 @@ func Load(configDetails types.ConfigDetails, options ...func(*Options)) (*types.
 		op(opts)
 	}
 
-	configs := []*types.Config{}
-	var err error
-
+	var (
+		issues  []issue
+		configs []*types.Config
+	)
 	for _, file := range configDetails.ConfigFiles {
-		configDict := file.Config
-		version := schema.Version(configDict)
-		if configDetails.Version == "" {
-			configDetails.Version = version
-		}
-		if configDetails.Version != version {
-			return nil, errors.Errorf("version mismatched between two composefiles : %v and %v", configDetails.Version, version)
+		configDict, fileIssues := loadFile(file, &configDetails, opts)
+		sortIssues(configDetails.ConfigFiles, fileIssues)
+		issues = append(issues, fileIssues...)
+		if hasErrors(fileIssues) {
+			continue
 		}
 
-		if err := validateForbidden(configDict); err != nil {
-			return nil, err
+		cfg, err := loadSections(configDict, configDetails)
+		if err != nil {
+			issues = append(issues, newIssue(file.Filename, err))
+			continue
 		}
+		cfg.Filename = file.Filename
+		cfg.Positions = file.Positions
 
-		if !opts.SkipInterpolation {
-			configDict, err = interpolateConfig(configDict, *opts.Interpolate)
-			if err != nil {
-				return nil, err
+		configs = append(configs, cfg)
+	}
+	if hasErrors(issues) || len(configs) == 0 {
+		return nil, issues
+	}
+
+	config, err := merge(configs)
+	if err != nil {
+		return nil, append(issues, newIssue("", err))
+	}
+	return config, issues
+}
+
+// loadFile returns the interpolated config of a single Compose file, and the
+// issues found in it before loading its sections
+func loadFile(file types.ConfigFile, configDetails *types.ConfigDetails, opts *Options) (map[string]interface{}, []issue) {
+	var issues []issue
+	add := func(severity string, path types.Path, err error) {
+		issues = append(issues, issue{severity: severity, filename: file.Filename, path: path, err: err})
+	}
+
+	version := schema.Version(file.Config)
+	if configDetails.Version == "" {
+		configDetails.Version = version
+	}
+	if configDetails.Version != version {
+		add(SeverityError, types.Path{"version"}, errors.Errorf("version mismatched between two composefiles : %v and %v", configDetails.Version, version))
+		return nil, issues
+	}
+
+	services := getServices(file.Config)
+	for _, name := range sortedServiceNames(services) {
+		serviceDict, ok := services[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := func(property string) types.Path {
+			return types.Path{"services", name, property}
+		}
+		for _, property := range sortedProperties(types.ForbiddenProperties) {
+			if _, isSet := serviceDict[property]; isSet {
+				add(SeverityError, path(property), &forbiddenPropertyError{property: property, description: types.ForbiddenProperties[property]})
 			}
 		}
-
-		if !opts.SkipValidation {
-			if err := schema.Validate(configDict, configDetails.Version); err != nil {
-				return nil, err
+		for _, property := range types.UnsupportedProperties {
+			if _, isSet := serviceDict[property]; isSet {
+				add(SeverityWarning, path(property), errors.Errorf("ignoring unsupported option %q", property))
+			}
+		}
+		for _, property := range sortedProperties(types.DeprecatedProperties) {
+			if _, isSet := serviceDict[property]; isSet {
+				add(SeverityWarning, path(property), errors.Errorf("ignoring deprecated option %q: %s", property, types.DeprecatedProperties[property]))
 			}
 		}
+	}
 
-		cfg, err := loadSections(configDict, configDetails)
+	configDict := file.Config
+	if !opts.SkipInterpolation {
+		var err error
+		configDict, err = interpolateConfig(file.Config, *opts.Interpolate)
+		if unsetErr, ok := err.(*interp.UnsetVariablesError); ok {
+			for _, v := range unsetErr.Variables {
+				add(SeverityError, types.Path(v.Keys), &unsetVariableError{variable: v})
+			}
+			// go on with the file as it is interpolated without --strict
+			interpolateOpts := *opts.Interpolate
+			interpolateOpts.Strict = false
+			configDict, err = interpolateConfig(file.Config, interpolateOpts)
+		}
 		if err != nil {
-			return nil, err
+			issues = append(issues, newIssue(file.Filename, err))
+			return nil, issues
 		}
-		cfg.Filename = file.Filename
-
-		configs = append(configs, cfg)
 	}
 
-	return merge(configs)
+	if !opts.SkipValidation {
+		errs, err := schema.ValidateAll(configDict, configDetails.Version)
+		if err != nil {
+			add(SeverityError, types.Path{"version"}, err)
+		}
+		for _, e := range errs {
+			add(SeverityError, e.Path, e)
+		}
+	}
+	return configDict, issues
 }
 
-func validateForbidden(configDict map[string]interface{}) error {
-	servicesDict, ok := configDict["services"].(map[string]interface{})
-	if !ok {
+// loadError returns the error Load fails with for the issues found loading the
+// Compose files, if any: the unset variables of all the files, the forbidden
+// properties of a file, or the first other error, at its position if known
+func loadError(files []types.ConfigFile, issues []issue) error {
+	var first *issue
+	for i := range issues {
+		if issues[i].severity == SeverityError {
+			first = &issues[i]
+			break
+		}
+	}
+	if first == nil {
 		return nil
 	}
-	forbidden := getProperties(servicesDict, types.ForbiddenProperties)
-	if len(forbidden) > 0 {
-		return &ForbiddenPropertiesError{Properties: forbidden}
+
+	switch first.err.(type) {
+	case *unsetVariableError:
+		// report the unset variables of all the files at once
+		var unset []interp.UnsetVariable
+		for _, issue := range issues {
+			if e, ok := issue.err.(*unsetVariableError); ok {
+				v := e.variable
+				v.Filename = issue.filename
+				if positions := issue.positions(files); positions != nil {
+					v.Line = positions.Line
+				}
+				unset = append(unset, v)
+			}
+		}
+		return &interp.UnsetVariablesError{Variables: unset}
+	case *forbiddenPropertyError:
+		properties := map[string]string{}
+		for _, issue := range issues {
+			if e, ok := issue.err.(*forbiddenPropertyError); ok && issue.filename == first.filename {
+				properties[e.property] = e.description
+			}
+		}
+		return &ForbiddenPropertiesError{Properties: properties}
+	}
+
+	if positions := first.positions(files); positions != nil {
+		return &PositionError{Filename: first.filename, Position: positions.Position, Err: first.err}
 	}
-	return nil
+	return first.err
 }
 
 func loadSections(config map[string]interface{}, configDetails types.ConfigDetails) (*types.Config, error) {
@@ -172,6 +284,9 @@ func loadSections(config map[string]interface{}, configDetails types.ConfigDetai
 	}
 	for _, loader := range loaders {
 		if err := loader.fnc(getSection(config, loader.key)); err != nil {
+			if _, ok := err.(*pathError); !ok {
+				err = &pathError{path: types.Path{loader.key}, err: err}
+			}
 			return nil, err
 		}
 	}
@@ -383,7 +498,7 @@ func LoadServices(servicesDict map[string]interface{}, workingDir string, lookup
 	for name, serviceDef := range servicesDict {
 		serviceConfig, err := LoadService(name, serviceDef.(map[string]interface{}), workingDir, lookupEnv)
 		if err != nil {
-			return nil, err
+			return nil, &pathError{path: types.Path{"services", name}, err: err}
 		}
 		services = append(services, *serviceConfig)
 	}
@@ -535,7 +650,7 @@ func LoadNetworks(source map[string]interface{}, version string) (map[string]typ
 		switch {
 		case network.External.Name != "":
 			if network.Name != "" {
-				return nil, errors.Errorf("network %s: network.external.name and network.name conflict; only use network.name", name)
+				return nil, &pathError{path: types.Path{"networks", name}, err: errors.Errorf("network %s: network.external.name and network.name conflict; only use network.name", name)}
 			}
 			if versions.GreaterThanOrEqualTo(version, "3.5") {
 				logrus.Warnf("network %s: network.external.name is deprecated in favor of network.name", name)
@@ -571,14 +686,14 @@ func LoadVolumes(source map[string]interface{}, version string) (map[string]type
 		}
 		switch {
 		case volume.Driver != "":
-			return nil, externalVolumeError(name, "driver")
+			return nil, &pathError{path: types.Path{"volumes", name}, err: externalVolumeError(name, "driver")}
 		case len(volume.DriverOpts) > 0:
-			return nil, externalVolumeError(name, "driver_opts")
+			return nil, &pathError{path: types.Path{"volumes", name}, err: externalVolumeError(name, "driver_opts")}
 		case len(volume.Labels) > 0:
-			return nil, externalVolumeError(name, "labels")
+			return nil, &pathError{path: types.Path{"volumes", name}, err: externalVolumeError(name, "labels")}
 		case volume.External.Name != "":
 			if volume.Name != "" {
-				return nil, errors.Errorf("volume %s: volume.external.name and volume.name conflict; only use volume.name", name)
+				return nil, &pathError{path: types.Path{"volumes", name}, err: errors.Errorf("volume %s: volume.external.name and volume.name conflict; only use volume.name", name)}
 			}
 			if versions.GreaterThanOrEqualTo(version, "3.4") {
 				logrus.Warnf("volume %s: volume.external.name is deprecated in favor of volume.name", name)
@@ -604,7 +719,7 @@ func LoadSecrets(source map[string]interface{}, details types.ConfigDetails) (ma
 	for name, secret := range secrets {
 		obj, err := loadFileObjectConfig(name, "secret", types.FileObjectConfig(secret), details)
 		if err != nil {
-			return nil, err
+			return nil, &pathError{path: types.Path{"secrets", name}, err: err}
 		}
 		secretConfig := types.SecretConfig(obj)
 		secretConfig.Extras = loadExtras(name, source)
@@ -623,7 +738,7 @@ func LoadConfigObjs(source map[string]interface{}, details types.ConfigDetails)
 	for name, config := range configs {
 		obj, err := loadFileObjectConfig(name, "config", types.FileObjectConfig(config), details)
 		if err != nil {
-			return nil, err
+			return nil, &pathError{path: types.Path{"configs", name}, err: err}
 		}
 		configConfig := types.ConfigObjConfig(obj)
 		configConfig.Extras = loadExtras(name, source)
@@ -652,6 +767,8 @@ func loadFileObjectConfig(name string, objType string, obj types.FileObjectConfi
 			}
 		}
 		// if not "external: true"
+	case obj.Template && obj.File == "":
+		return obj, errors.Errorf("%[1]s %[2]s: %[1]s.template requires %[1]s.file", objType, name)
 	case obj.Driver != "":
 		if obj.File != "" {
 			return obj, errors.Errorf("%[1]s %[2]s: %[1]s.driver and %[1]s.file conflict; only use %[1]s.driver", objType, name)
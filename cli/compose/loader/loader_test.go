@@ -580,6 +580,39 @@ volumes:
 	assert.Check(t, is.Equal(home, config.Volumes["test"].Driver))
 }
 
+func TestLoadWithStrictInterpolation(t *testing.T) {
+	first, err := ParseYAML([]byte(`
+version: "3"
+services:
+  web:
+    image: nginx:${TAG}
+    environment:
+      - HOME=$HOME
+      - LEVEL=${LEVEL:-info}
+`))
+	assert.NilError(t, err)
+	second, err := ParseYAML([]byte(`
+version: "3"
+services:
+  web:
+    labels:
+      - owner=${OWNER}
+`))
+	assert.NilError(t, err)
+
+	details := buildConfigDetails(first, map[string]string{"HOME": "/home/foo"})
+	details.ConfigFiles = append(details.ConfigFiles, types.ConfigFile{Filename: "override.yml", Config: second})
+	_, err = Load(details, func(options *Options) {
+		options.Interpolate.Strict = true
+	})
+	assert.Check(t, is.Error(err, `required variables are not set:
+  filename.yml: services.web.image: TAG
+  override.yml: services.web.labels[0]: OWNER`))
+
+	_, err = Load(details)
+	assert.NilError(t, err)
+}
+
 func TestLoadWithInterpolationCastFull(t *testing.T) {
 	dict, err := ParseYAML([]byte(`
 version: "3.4"
@@ -1664,3 +1697,41 @@ secrets:
 	}
 	assert.DeepEqual(t, config, expected, cmpopts.EquateEmpty())
 }
+
+func TestLoadErrorPositions(t *testing.T) {
+	source := []byte(`version: "3.8"
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+      - target: "443"
+`)
+	config, err := ParseYAML(source)
+	assert.NilError(t, err)
+	details := types.ConfigDetails{
+		ConfigFiles: []types.ConfigFile{
+			{Filename: "docker-compose.yml", Config: config, Positions: ParsePositions("docker-compose.yml", source)},
+		},
+	}
+
+	_, err = Load(details)
+	assert.Check(t, is.Error(err, "docker-compose.yml:7:9: services.web.ports.1.target must be a integer"))
+	positionErr, ok := err.(*PositionError)
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(types.Position{Line: 7, Column: 9}, positionErr.Position))
+}
+
+func TestLoadTemplateWithoutFile(t *testing.T) {
+	_, err := loadYAML(`
+version: "3.8"
+services:
+  web:
+    image: nginx
+configs:
+  app:
+    template: true
+    template_driver: golang
+`)
+	assert.ErrorContains(t, err, "config app: config.template requires config.file")
+}
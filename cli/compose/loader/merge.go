@@ -26,28 +26,68 @@ func merge(configs []*types.Config) (*types.Config, error) {
 		var err error
 		base.Services, err = mergeServices(base.Services, override.Services)
 		if err != nil {
-			return base, errors.Wrapf(err, "cannot merge services from %s", override.Filename)
+			return base, wrapMergeError(err, "services", override.Filename)
 		}
 		base.Volumes, err = mergeVolumes(base.Volumes, override.Volumes)
 		if err != nil {
-			return base, errors.Wrapf(err, "cannot merge volumes from %s", override.Filename)
+			return base, wrapMergeError(err, "volumes", override.Filename)
 		}
 		base.Networks, err = mergeNetworks(base.Networks, override.Networks)
 		if err != nil {
-			return base, errors.Wrapf(err, "cannot merge networks from %s", override.Filename)
+			return base, wrapMergeError(err, "networks", override.Filename)
 		}
 		base.Secrets, err = mergeSecrets(base.Secrets, override.Secrets)
 		if err != nil {
-			return base, errors.Wrapf(err, "cannot merge secrets from %s", override.Filename)
+			return base, wrapMergeError(err, "secrets", override.Filename)
 		}
 		base.Configs, err = mergeConfigs(base.Configs, override.Configs)
 		if err != nil {
-			return base, errors.Wrapf(err, "cannot merge configs from %s", override.Filename)
+			return base, wrapMergeError(err, "configs", override.Filename)
 		}
+		base.Positions = mergePositions(base.Positions, override.Positions)
 	}
 	return base, nil
 }
 
+// mergePositions returns the positions of the values of two merged configs,
+// where the values set in override are located in override. The items of a
+// sequence set in both configs are appended or merged by key, so they are
+// only located at the sequence.
+func mergePositions(base, override *types.Positions) *types.Positions {
+	switch {
+	case override == nil:
+		return base
+	case base == nil:
+		return override
+	}
+	merged := *override
+	if base.Sequence || override.Sequence {
+		merged.Children = nil
+		return &merged
+	}
+	if base.Children == nil || override.Children == nil {
+		return &merged
+	}
+	merged.Children = make(map[string]*types.Positions, len(base.Children))
+	for key, child := range base.Children {
+		merged.Children[key] = child
+	}
+	for key, child := range override.Children {
+		merged.Children[key] = mergePositions(base.Children[key], child)
+	}
+	return &merged
+}
+
+// wrapMergeError wraps an error merging a section of the Compose file
+// filename, keeping the path of the value that could not be merged
+func wrapMergeError(err error, section, filename string) error {
+	path := types.Path{section}
+	if e, ok := err.(*pathError); ok {
+		path = e.path
+	}
+	return &pathError{path: path, filename: filename, err: errors.Wrapf(err, "cannot merge %s from %s", section, filename)}
+}
+
 func mergeServices(base, override []types.ServiceConfig) ([]types.ServiceConfig, error) {
 	baseServices := mapByName(base)
 	overrideServices := mapByName(override)
@@ -62,7 +102,7 @@ func mergeServices(base, override []types.ServiceConfig) ([]types.ServiceConfig,
 	for name, overrideService := range overrideServices {
 		if baseService, ok := baseServices[name]; ok {
 			if err := mergo.Merge(&baseService, &overrideService, mergo.WithAppendSlice, mergo.WithOverride, mergo.WithTransformers(specials)); err != nil {
-				return base, errors.Wrapf(err, "cannot merge service %s", name)
+				return base, &pathError{path: types.Path{"services", name}, err: errors.Wrapf(err, "cannot merge service %s", name)}
 			}
 			baseServices[name] = baseService
 			continue
@@ -0,0 +1,117 @@
+package loader
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/docker/cli/cli/compose/types"
+	yamlnode "gopkg.in/yaml.v3"
+)
+
+// ParsePositions returns the positions of the values of a Compose file, from
+// its YAML node tree. As the positions are only used to report errors, it
+// returns nil if the file cannot be parsed, which ParseYAML reports.
+func ParsePositions(filename string, source []byte) *types.Positions {
+	var document yamlnode.Node
+	if err := yamlnode.Unmarshal(source, &document); err != nil || len(document.Content) == 0 {
+		return nil
+	}
+	root := document.Content[0]
+	return nodePositions(filename, root, nodePosition(root), map[*yamlnode.Node]bool{})
+}
+
+// nodePositions returns the positions of the values of a node located at pos.
+// The values of an alias are located where its anchor sets them. expanding
+// holds the nodes being walked, to stop at an alias to one of them.
+func nodePositions(filename string, node *yamlnode.Node, pos types.Position, expanding map[*yamlnode.Node]bool) *types.Positions {
+	positions := &types.Positions{Filename: filename, Position: pos}
+	if node.Kind == yamlnode.AliasNode {
+		node = node.Alias
+	}
+	if expanding[node] {
+		return positions
+	}
+	expanding[node] = true
+	defer delete(expanding, node)
+
+	switch node.Kind {
+	case yamlnode.SequenceNode:
+		positions.Sequence = true
+		positions.Children = make(map[string]*types.Positions, len(node.Content))
+		for i, item := range node.Content {
+			positions.Children[strconv.Itoa(i)] = nodePositions(filename, item, nodePosition(item), expanding)
+		}
+	case yamlnode.MappingNode:
+		positions.Children = map[string]*types.Positions{}
+		addMappingPositions(filename, node, positions.Children, expanding)
+	}
+	return positions
+}
+
+// addMappingPositions adds the positions of the values of a mapping node to
+// children. The values of the mappings merged with a "<<" key are located in
+// these mappings, and override the values set before the key, as ParseYAML
+// decodes them.
+func addMappingPositions(filename string, node *yamlnode.Node, children map[string]*types.Positions, expanding map[*yamlnode.Node]bool) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind != yamlnode.ScalarNode {
+			continue
+		}
+		if key.ShortTag() != "!!merge" {
+			children[key.Value] = nodePositions(filename, value, nodePosition(key), expanding)
+			continue
+		}
+
+		merged := []*yamlnode.Node{value}
+		if value.Kind == yamlnode.SequenceNode {
+			// earlier mappings take precedence
+			merged = nil
+			for j := len(value.Content) - 1; j >= 0; j-- {
+				merged = append(merged, value.Content[j])
+			}
+		}
+		for _, mapping := range merged {
+			for name, child := range nodePositions(filename, mapping, nodePosition(mapping), expanding).Children {
+				children[name] = child
+			}
+		}
+	}
+}
+
+func nodePosition(node *yamlnode.Node) types.Position {
+	return types.Position{Line: node.Line, Column: node.Column}
+}
+
+// PositionError is an error in a Compose file, at a known position
+type PositionError struct {
+	Filename string
+	Position types.Position
+	Err      error
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Filename, e.Position.Line, e.Position.Column, e.Err)
+}
+
+// Cause returns the underlying error
+func (e *PositionError) Cause() error {
+	return e.Err
+}
+
+// pathError is an error loading the value at path. If filename is set, the
+// value is in that file rather than in the file being loaded.
+type pathError struct {
+	path     types.Path
+	filename string
+	err      error
+}
+
+func (e *pathError) Error() string {
+	return e.err.Error()
+}
+
+// Cause returns the underlying error
+func (e *pathError) Cause() error {
+	return e.err
+}
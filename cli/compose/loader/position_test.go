@@ -0,0 +1,177 @@
+package loader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+// flattenPositions returns the positions of the values, by their path with
+// the keys separated by "/"
+func flattenPositions(positions *types.Positions) map[string]types.Position {
+	flat := map[string]types.Position{}
+	var walk func(path []string, positions *types.Positions)
+	walk = func(path []string, positions *types.Positions) {
+		flat[strings.Join(path, "/")] = positions.Position
+		for key, child := range positions.Children {
+			walk(append(path[:len(path):len(path)], key), child)
+		}
+	}
+	walk(nil, positions)
+	return flat
+}
+
+func TestParsePositions(t *testing.T) {
+	positions := ParsePositions("docker-compose.yml", []byte(`version: "3.8"
+# comment
+services:
+  web:
+    image: nginx
+    command: |
+      echo hello
+      image: not-a-key
+    ports:
+      - 80:80
+      - target: 443
+        published: 443
+    "quoted key": value
+    labels:
+      com.example.description: web
+    dns: [8.8.8.8, 1.1.1.1]
+x-web.settings:
+  debug: true
+`))
+	assert.Assert(t, positions != nil)
+
+	expected := map[string]types.Position{
+		"":                               {Line: 1, Column: 1},
+		"version":                        {Line: 1, Column: 1},
+		"services":                       {Line: 3, Column: 1},
+		"services/web":                   {Line: 4, Column: 3},
+		"services/web/image":             {Line: 5, Column: 5},
+		"services/web/command":           {Line: 6, Column: 5},
+		"services/web/ports":             {Line: 9, Column: 5},
+		"services/web/ports/0":           {Line: 10, Column: 9},
+		"services/web/ports/1":           {Line: 11, Column: 9},
+		"services/web/ports/1/target":    {Line: 11, Column: 9},
+		"services/web/ports/1/published": {Line: 12, Column: 9},
+		"services/web/quoted key":        {Line: 13, Column: 5},
+		"services/web/labels":            {Line: 14, Column: 5},
+		"services/web/labels/com.example.description": {Line: 15, Column: 7},
+		"services/web/dns":     {Line: 16, Column: 5},
+		"services/web/dns/0":   {Line: 16, Column: 11},
+		"services/web/dns/1":   {Line: 16, Column: 20},
+		"x-web.settings":       {Line: 17, Column: 1},
+		"x-web.settings/debug": {Line: 18, Column: 3},
+	}
+	assert.Check(t, is.DeepEqual(expected, flattenPositions(positions)))
+	assert.Check(t, is.Equal("docker-compose.yml", positions.Lookup(types.Path{"services", "web", "image"}).Filename))
+	assert.Check(t, positions.Lookup(types.Path{"services", "web", "ports"}).Sequence)
+}
+
+func TestParsePositionsAnchors(t *testing.T) {
+	positions := ParsePositions("docker-compose.yml", []byte(`version: "3.8"
+x-defaults: &defaults
+  image: nginx
+  environment: &env
+    LEVEL: info
+services:
+  web:
+    <<: *defaults
+    image: web
+  api:
+    environment: *env
+  worker:
+    <<: [*defaults, {image: worker, user: nobody}]
+`))
+
+	web := positions.Lookup(types.Path{"services", "web"})
+	assert.Check(t, is.DeepEqual(map[string]types.Position{
+		"":                  {Line: 7, Column: 3},
+		"image":             {Line: 9, Column: 5},
+		"environment":       {Line: 4, Column: 3},
+		"environment/LEVEL": {Line: 5, Column: 5},
+	}, flattenPositions(web)))
+
+	api := positions.Lookup(types.Path{"services", "api"})
+	assert.Check(t, is.DeepEqual(map[string]types.Position{
+		"":                  {Line: 10, Column: 3},
+		"environment":       {Line: 11, Column: 5},
+		"environment/LEVEL": {Line: 5, Column: 5},
+	}, flattenPositions(api)))
+
+	// the earlier mappings of a merge take precedence
+	worker := positions.Lookup(types.Path{"services", "worker"})
+	assert.Check(t, is.Equal(types.Position{Line: 3, Column: 3}, worker.Children["image"].Position))
+	assert.Check(t, is.Equal(types.Position{Line: 13, Column: 37}, worker.Children["user"].Position))
+}
+
+func TestParsePositionsRecursiveAlias(t *testing.T) {
+	positions := ParsePositions("docker-compose.yml", []byte(`x-a: &a
+  b: *a
+`))
+	assert.Check(t, is.DeepEqual(map[string]types.Position{
+		"":      {Line: 1, Column: 1},
+		"x-a":   {Line: 1, Column: 1},
+		"x-a/b": {Line: 2, Column: 3},
+	}, flattenPositions(positions)))
+}
+
+func TestParsePositionsInvalid(t *testing.T) {
+	assert.Check(t, is.Nil(ParsePositions("docker-compose.yml", []byte("services: [web"))))
+	assert.Check(t, is.Nil(ParsePositions("docker-compose.yml", nil)))
+}
+
+func TestPositionsLookup(t *testing.T) {
+	positions := ParsePositions("docker-compose.yml", []byte(`services:
+  web:
+    labels:
+      com.example: web
+    ports:
+      - 80:80
+`))
+
+	assert.Check(t, is.Equal(types.Position{Line: 6, Column: 9}, positions.Lookup(types.Path{"services", "web", "ports", "0"}).Position))
+	assert.Check(t, is.Equal(types.Position{Line: 4, Column: 7}, positions.Lookup(types.Path{"services", "web", "labels", "com.example"}).Position))
+	// the closest ancestor of an unknown value
+	assert.Check(t, is.Equal(types.Position{Line: 3, Column: 5}, positions.Lookup(types.Path{"services", "web", "labels", "com", "example"}).Position))
+	assert.Check(t, is.Equal(types.Position{Line: 2, Column: 3}, positions.Lookup(types.Path{"services", "web", "image"}).Position))
+
+	var none *types.Positions
+	assert.Check(t, is.Nil(none.Lookup(types.Path{"services"})))
+}
+
+func TestMergePositions(t *testing.T) {
+	base := ParsePositions("base.yml", []byte(`services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  db:
+    image: postgres
+`))
+	override := ParsePositions("override.yml", []byte(`services:
+  web:
+    ports:
+      - 443:443
+    user: nobody
+`))
+
+	merged := mergePositions(base, override)
+	web := merged.Lookup(types.Path{"services", "web"})
+	assert.Check(t, is.Equal("override.yml", web.Filename))
+	assert.Check(t, is.Equal("base.yml", web.Children["image"].Filename))
+	assert.Check(t, is.Equal("override.yml", web.Children["user"].Filename))
+	assert.Check(t, is.Equal("base.yml", merged.Lookup(types.Path{"services", "db"}).Filename))
+
+	// the items of the merged sequence are only located at the sequence
+	ports := merged.Lookup(types.Path{"services", "web", "ports", "0"})
+	assert.Check(t, is.Equal("override.yml", ports.Filename))
+	assert.Check(t, is.Equal(types.Position{Line: 3, Column: 5}, ports.Position))
+
+	// the positions of the configs are left unchanged
+	assert.Check(t, is.Equal("base.yml", base.Lookup(types.Path{"services", "web"}).Filename))
+}
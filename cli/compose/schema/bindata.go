@@ -510,45 +510,45 @@ bnBpPlHfjORjkTRf1wyAwiYqMXd9/G6313QfoXs6/sbZ66r6e179PwAA//8ZL3SpvkUAAA==
 
 	"/data/config_schema_v3.8.json": {
 		local:   "data/config_schema_v3.8.json",
-		size:    18246,
+		size:    18391,
 		modtime: 1518458244,
 		compressed: `
-H4sIAAAAAAAC/+xcS4/juBG++1cI2r1tPwbIIkjmlmNOyTkNj0BTZZvbFMktUp72DvzfAz1bokiRtuXu
-3qQDBDstFR/15FfFkn+skiT9WdM9FCT9mqR7Y9TXx8fftBT3zdMHibvHHMnW3H/59bF59lN6V41jeTWE
-SrFlu6x5kx3+8vC3h2p4Q2KOCioiufkNqGmeIfxeMoRq8FN6ANRMinR9t6reKZQK0DDQ6dek2lyS9CTd
-g8G02iATu7R+fKpnSJJUAx4YHczQb/Wnx9f5H3uyO3vWwWbr54oYAyj+Pd1b/frbE7n/4x/3//ly//eH
-7H79y8+j15V8EbbN8jlsmWCGSdGvn/aUp/Zfp35hkuc1MeGjtbeEaxjzLMB8l/gc4rkneyee2/UdPI/Z
-OUheFkENdlTvxEyz/DL600ARTNhkG6p3s9hq+WUYbqJGiOGO6p0Ybpa/juFVx7R7j+m3l/vqv6d6ztn5
-mlkG+6uZGMU8lzhdMccvz16gHknmoLg81jt3y6whKECYtBdTkqSbkvHclroU8K9qiqfBwyT5YYf3wTz1
-+9FffqPo33t46d9TKQy8mJqp+aUbEUj6DLhlHGJHEGws3SMyzrTJJGY5o8Y5npMN8KtmoITuIduiLIKz
-bLOGE+2cqIvgkZwbgjuIlqzeF5lmf4zk+pQyYWAHmN71Y9cna+xksrBj2j5d/W+9ckyYUqIykucjJggi
-OVY7YgYK7eYvSUvBfi/hny2JwRLseXOUavmJdyhLlSmClRfOyz6lsiiIWMo1z+EjQvKTQ2Lk7+0aw1f9
-aqNtebhJIqzSES4C4SYccCpLlyXS2Phxrh8lSVqyPJ54dw5xIfPxvkVZbADT04R44qSjv9cr1xtL+4Yw
-AZgJUkDQjhFyEIYRnmkF1GczDqXNqas1wQjxpJEHQoqwY9rg0Um78sS0uHg2lEcOCkSusyZxOj/ipzn0
-WdSi0SkXcydZM011llV7S62BmQaCdH/heFkQJmJsCYTBo5KsiZ4fLiyCOGS9tZ0tBhAHhlIU3dkQhygG
-41+U1HB9TO7P95bxuz6UrG3PkliQarPd2l4vmVreUIBDHiokTnjGmXhe3sThxSDJ9lKbS0BbugfCzZ7u
-gT7PDB9SjUZLbWKMnBVkFyYSbHzqbKTkQMSYSNHgPFpyYtoqzhzhxVA3XVSVg2nlbleR+ux3kjpFJh05
-sgNgLDKW6jXjc8GDECQJpsgj0m8PTYY846P1vzifQnHXyW8/sY/E2MPtVSsFoRUmR9A6ZFFtxpJNgMsr
-7YRYx8b9ixKp8xPYKNUFqxxBOOyDvPFWFgd/O7VzRjTo6zLSQRQ6/BppE66xf50d6xnqnTM+/wxMNcTZ
-nDs3sg4j71umx2qcPYxjRR0hhg6mJJo3Sehe49QrfGgWn+Z4trqjBt0mMZyJUnFpYVctcQ9Q5YYzvYf8
-nDEojaSSxzmGs/4V7wwzSeJFSE8hOzAOO4tjF4xBIHkmBT9GUGpDMFha0UBLZOaYSWUWx5juWtmr1fel
-svGGrFuGz3rK/089RR81NZdha21yJjKpQAR9Qxupsh0SCpkCZNIpilGAzUtsUoPJNJrtBOEhNzOF2l5Y
-UjAm7OwlZwXzO42zoBTEaw1Wc0O0GXgWFbJnMoT5BCEiM9gTPOPoqB1z6zmfVpEYaNwvUM93125k7aQ/
-C3rZ21h70Y/bqUodTOJqGqGziKPdcfH954jQIx3V5OuL4ni7UmTsvHXUj0YE44KxZtqAoMf4hTZscgNz
-bt4Vl3XVVGTnL8W4c5NoX217It6EFSGpVB7VXMlGf6TcnosOw/mTUztyzuSxBROsKIv0a/LFl7HGS+bG
-0N6qAc0Ael/s/S7xuTrZc4Zztnya7xIZd2Cc2cZilWrnei+GpMF+lvk+kFCPBtNkY11GOeu2wgAe3AAr
-jNAQDDLrfqjDrkOIBfpj3qIYVoAszaXwlKA5H+Da3W6DlpruPmbOhAaUtgU99SbUlV2CZhKDR0Dk9T1Y
-FHhBUJxRokMA8YoiP0rON4Q+Z6/3skvc8iqChHPgTBcx6DbNgZPjRZbTXGgRxkuEjNCIK5FWV4IZiZcv
-WZCXrFu2Jgn4beOnmINvTRD1OWPjy8Yz7rcMtWnKEFK1f43D/4JX3aXKiYFPk/g0iWGFrs4N9FLm4CwC
-LNN9qMrY+4q0gEKGO0euLflPGlZ0BRN8F5AfRQAO6h0IQEazkTV4jpwp7Y1uUa637AZ7SM6aFHOhNqdm
-HzGR58pQV8WdCogXyuio0PqdiVx+Px9mLSBtxQkFC5pdK2htkDBhzu5VsMWiELaAICjMuuW0ZjRTN1qu
-IK8QSP4OV0Yua+uAaQXYM2EjWVdF8hKzueJrCGegmssEpgMmKeVY7w59+/Xs12+VW1IEA/3Krm7LkA3N
-20/63FbDgiE+PRBeRtyeXNRv4qs6RAw+OT/OCum0I1sgtYvp/4pqQGqpMqmWvwEJNxmtw/V3pkixVGyO
-bslKnanGR4i65UZ4Ctw3jrrLHbldb6ZHq099Keuul9U6WsVex1hu/3VVzb62dJXfiDGE7qMqdWcWTN6g
-8Dkp9DtDWkv1GdHOiGh/dvv/eLbafrca/Daypgp/anqFhUZ8I/IB9L+EWv/n3LLKVzkxkM2w8wa2PEEe
-TltuqT5teWlb/iBWYLU0DaxherU2p6DovuvV8Cat34ZN5viFDl8W6t2U7yLYWrTVzTznCwaRh19m0P7c
-9xE3gskLNJO6dWoVqFZ966j9AwP+0NONn/zcQMWnOE6ufn+M24eanwpYj+RjkTTfLg2i9jqqeOH6EQK7
-ean7MQBPP+U4w19V/z+t/hsAAP//Fd/bF0ZHAAA=
+H4sIAAAAAAAC/+xcW2/juhF+968geM7bcZIFelC0+9bHPrXPDbwCLY1lnlAkD0l54134vxeyLtaFN9ny
+JtsmwGITa3iZ28eZ4cjfVwjhX3W6h4LgzwjvjZGfn57+0II/1J8+CpU/ZYrszMOn35/qz37B6xVCmGbV
+kFTwHc2T+kly+Mvj3x6r4TWJOUqoiMT2D0hN/ZmCP0uqoBr8jA+gNBUcb9ar6plUQoIyFDT+jL6vEEKo
+I2k/6E2rjaI8xyuEEDqdZ0AIa1AHmvZm6Lb6y9Nl/qeObD2etbdZhBDCkhgDiv97ujeEEMJfnsnDt388
+/OfTw98fk4fNb78OHlfyVbCrl89gRzk1VPBufdxRnprfTt3CJMvOxIQN1t4RpmHIMwfzVaiXEM8d2Rvx
+3Kxv4XnIzkGwsghqsKV6I2bq5ZfRn4ZUgQmbbE31ZhZbLb8MwzVqhBhuqd6I4Xr52xhetUzb94i/vD5U
+/5/Oc3rnq2fp7e/MxADzbOK0YY5bnp1AHZLMQDJxPO/cLrOaoABucCcmhPC2pCwbS11w+Fc1xXPvQ4S+
+j+H9tB4+H/zlNgqE/Ly0P5UtGng1Z6b8S9ciEOkLqB1lEDuCqFx7RMaoNolQSUZTYx3PyBbYTTOkJN1D
+slOiCM6yS2pOtHWiFsEjOTdE5RAtWb0vEk2/DeT6jCk3kIPC627s5jQaO5ks7Jhjn0YIoc3KMiFOiUxI
+lg2YIEqRY7UjaqDQdv4QLjn9s4R/NiRGlTCeN1NCLj9xrkQpE0lU5YV+2eNUFAXhS7nmHD4iJD85JAb+
+3qzRf9St1vvQyU2YH2SDiwDchAEHIaxFqdJY/JjrRwjhkmbxxPkc4kJkw33zstiCwqcJ8Wnl+3uzsj0Z
+ad8QykElnBQQtGMFGXBDCUu0hNRlMxal+dTVmGCEeHDkgYAV5FQbdbTSrhyYFodnfXlkIIFnOhH8OsTH
+GXRZ1KLolHHfSVZPU51l1d7waGCigah0f+V4URDKY2wJuFFHKWiNnu8OFoEfks7aZosB+IEqwYv2bIiL
+KHrjX6XQcDsmd+d7w/i6g5LN2LOEKki12XZtp5dMLa8vwD4PVSROWMIof1nexOHVKJLshTbXBG14D4SZ
+fbqH9MUzvE81GC20iTFyWpA8TMTp8NTZCsGA8CGRTIPzaMGIaao4PsKrQ128qCp704o8r0hd9jtJnSKT
+jkzRA6jYyFjIS8ZnCw9CIUkwRe7/4C+PdYbs8dHzb4xNQ3HbyT/+ZHwkxh5uq0sUQtIqJlegdciimowl
+mQQuF9oJsY7F/asSqfkJbJTqglWOADeu7c2xshjTv6idUaJB35aR9lDo8HukTdjG/tU71jHUOWd8/hmY
+qh9nM2bdyCYced8zPZY0c2PFGSH6DiaFMj8kobvg1CV8qBc/rZ2DLlsPD7pPYuhBqbi0sK2W2AfIcsuo
+3kM2Z4wSRqSCxTmGtf4V7wyeJPGqSE8qeqAMcsiCYYxUooqqr8QjrIBkieDsGFxIgTZEBSszGtJSUXNM
+hDSLh6j2UtvFabpK23BDo0sK9FGO+b8px+ijTs11obk2GeWJkMCDvqGNkEmuSAqJBEWFVRQDfM5KVWcW
+k2k0zTlhITczhdxdWZEwJuzsJaMFdTuNtR4VDPfqUM8e4Tm9C8UhvifB8OcXEYnFnqgZJ8/ZMXeO420V
+GUIN2w3O862bjWys9LMit/E2Ns7gye5UpQ7mgGcarpOIyMByb/5zIPRAR2fyzVU43qwUiZ33Rv3oiGBY
+b9ZUG+DpMX6hLZ1c4MwRf6z7NlQkd1dyrOPifbW23x/DChepkA7V3MhGd6Tcn4s2hnM8R2iCnJ40uKCc
+FmWBP6NPDqIZkrlzZjCczJcPuLC3KvRUJ3tGlc+WT/4mk2EDB5rXBTOq9PpaN/qkwXYYfxuJ38BwRjXZ
+MrB7xqA2bEAdCLsuQlNgFAVtjV17ZAb0+7yEMbQAUZprw1OizPwAd9wshy4dOe11js+EepRjC3ruTKit
+2gTNJCYeAZ6dr9GighcFktGU6FCAeMMdgRKMbUn6klyudZe4JJZEEcaAUV2ENt+ojJHjVZaDEEJ4Rygr
+FSQkjbhRaXTFqRHq+iUL8pq0y55JAn6LEEJYqAxcawIvi4kLt57xsKNKm7oMIWTz1xD+F7wpL2VGDHyY
+xIdJ9KCozg30UuZgLQKgRZoXZRl73YELKES48QTdeGMw6Xepuk6J6/7yvQjAQp0DB0XTZGANjiNnSnun
+S5jbLbuOPQSjdYq5UJdUvY8Y5LkR6ircqQLxQhodBa1fKc/E1/lh1gLSloykMArNbhW0NopQbma3OozF
+IhXsQAFPweuW05oRcteN0GIFeVkVT97gxslmbW1gWgXsCR9HsraK5DVmc8PLFFag8mUC0wHrlVfvFn27
+9ezWb5VbVndI0K1sa9YM2ZDffvBLUw0LQjw+EFZG3J5c1a7iqjpEDD5Z3+0K6bQlWyC1i2kfi+pfaqiq
+G8zFb0DCPUqbcP2dSlIshc1REkHDpuJ3hrrlljsK3HdG3eWO3La106HV566Ute5ktYlWsdMxlts/5Zf9
+e8tvxBiS7qMqdTMLJj+g8Dkp9FshraH6QLQZiPaz2//7s9Xmtdfgq5VnqvCbqjdYaMQrJu9A/0uo9X/O
+Lat8lRETxuqWMPHw/QOMfhKiWI2+ofow+qWN/mczl1GTVM9sppd1Pk1GN16uEELd3Vy3jTGZ5StDXHmt
+c1Ouq+XRoo0S/ZwvCEuPv3nyB98LG3cKvBdoT7XrdFTyaqU7/cYDN0a14yfff4AQJvw40hJC34cNSfV3
+F2xOazdJ/TJV7xzYRJVDbN+KMG6Har+dwNGhOawZrKp/p9V/BwBcaZ6J10cAAA==
 `,
 	},
 
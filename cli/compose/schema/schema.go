@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/cli/cli/compose/types"
 	"github.com/pkg/errors"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -14,6 +15,9 @@ import (
 const (
 	defaultVersion = "1.0"
 	versionField   = "version"
+	// keySeparator separates the keys of the context of an error, as the
+	// keys of labels and extension fields may contain dots
+	keySeparator = "\x00"
 )
 
 type portsFormatChecker struct{}
@@ -76,9 +80,103 @@ func Validate(config map[string]interface{}, version string) error {
 	return nil
 }
 
+// ValidateAll validates the configuration like Validate, but returns all the
+// validation errors, rather than only the most specific one
+func ValidateAll(config map[string]interface{}, version string) ([]*Error, error) {
+	schemaData, err := _escFSByte(false, fmt.Sprintf("/data/config_schema_v%s.json", version))
+	if err != nil {
+		return nil, errors.Errorf("unsupported Compose file version: %s", version)
+	}
+
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewStringLoader(string(schemaData)),
+		gojsonschema.NewGoLoader(config),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return getErrorsByField(result.Errors()), nil
+}
+
+// Error is a validation error for a field of a Compose file
+type Error struct {
+	// Field is the path of the field, such as "services.web.ports.0"
+	Field string
+	// Path is the path of the offending value, which is the path of the
+	// property for an additional property that is not allowed
+	Path        types.Path
+	Description string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Description)
+}
+
 func toError(result *gojsonschema.Result) error {
-	err := getMostSpecificError(result.Errors())
-	return err
+	return newError(getMostSpecificError(result.Errors()))
+}
+
+func newError(err validationError) *Error {
+	return &Error{
+		Field:       errorField(err.parent),
+		Path:        errorPath(err.parent),
+		Description: getDescription(err),
+	}
+}
+
+// errorField returns the field an error relates to, which is the mapping of
+// the property for an additional property that is not allowed
+func errorField(err gojsonschema.ResultError) string {
+	if err.Type() == "additional_property_not_allowed" {
+		if field := strings.TrimPrefix(err.Context().String(), "(root)"); field != "" {
+			return strings.TrimPrefix(field, ".")
+		}
+	}
+	return err.Field()
+}
+
+// errorPath returns the path of the value an error relates to
+func errorPath(err gojsonschema.ResultError) types.Path {
+	// the first key of the context is its root
+	path := types.Path(strings.Split(err.Context().String(keySeparator), keySeparator)[1:])
+	if property, ok := err.Details()["property"].(string); ok && err.Type() == "additional_property_not_allowed" {
+		path = append(path, property)
+	}
+	return path
+}
+
+// getErrorsByField returns the most specific error for each value with
+// errors, ignoring the errors of values that contain a value with a more
+// specific error, such as a failed "oneOf" for a mapping of which a property
+// is invalid
+func getErrorsByField(resultErrors []gojsonschema.ResultError) []*Error {
+	var paths []string
+	byPath := map[string][]gojsonschema.ResultError{}
+	for _, err := range resultErrors {
+		path := strings.Join(errorPath(err), keySeparator)
+		if _, ok := byPath[path]; !ok {
+			paths = append(paths, path)
+		}
+		byPath[path] = append(byPath[path], err)
+	}
+
+	var result []*Error
+	for _, path := range paths {
+		if hasDescendant(path, paths) {
+			continue
+		}
+		result = append(result, newError(getMostSpecificError(byPath[path])))
+	}
+	return result
+}
+
+func hasDescendant(path string, paths []string) bool {
+	for _, p := range paths {
+		if path == "" && p != "" || strings.HasPrefix(p, path+keySeparator) {
+			return true
+		}
+	}
+	return false
 }
 
 const (
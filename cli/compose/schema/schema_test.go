@@ -2,9 +2,12 @@ package schema
 
 import (
 	"fmt"
+	"sort"
 	"testing"
 
+	"github.com/docker/cli/cli/compose/types"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 type dict map[string]interface{}
@@ -278,3 +281,67 @@ func TestValidateRollbackConfigWithUpdateConfigFull(t *testing.T) {
 
 	assert.NilError(t, Validate(config, "3.7"))
 }
+
+func TestValidateAll(t *testing.T) {
+	config := dict{
+		"version": "3.7",
+		"services": dict{
+			"foo": dict{
+				"image":   1,
+				"unknown": true,
+			},
+			"bar": dict{
+				"image": "busybox",
+				"ports": []interface{}{"80", dict{"target": "80"}},
+			},
+		},
+	}
+
+	errs, err := ValidateAll(config, "3.7")
+	assert.NilError(t, err)
+	var paths, messages []string
+	for _, e := range errs {
+		paths = append(paths, e.Path.String())
+		messages = append(messages, e.Description)
+	}
+	sort.Strings(paths)
+	assert.Check(t, is.DeepEqual([]string{
+		"services.bar.ports.1.target",
+		"services.foo.image",
+		"services.foo.unknown",
+	}, paths))
+	assert.Check(t, is.Contains(messages, "must be a string"))
+	assert.Check(t, is.Contains(messages, "Additional property unknown is not allowed"))
+}
+
+func TestValidateAllDottedKeys(t *testing.T) {
+	config := dict{
+		"version": "3.7",
+		"services": dict{
+			"foo": dict{
+				"image": "busybox",
+				"labels": dict{
+					"com.example.description": dict{},
+				},
+			},
+		},
+	}
+
+	errs, err := ValidateAll(config, "3.7")
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(errs, 1))
+	assert.Check(t, is.DeepEqual(types.Path{"services", "foo", "labels", "com.example.description"}, errs[0].Path))
+}
+
+func TestValidateAllValid(t *testing.T) {
+	config := dict{
+		"version": "3.7",
+		"services": dict{
+			"foo": dict{"image": "busybox"},
+		},
+	}
+
+	errs, err := ValidateAll(config, "3.7")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(errs, 0))
+}
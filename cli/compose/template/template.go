@@ -7,7 +7,7 @@ import (
 )
 
 var delimiter = "\\$"
-var substitution = "[_a-z][_a-z0-9]*(?::?[-?][^}]*)?"
+var substitution = "[_a-z][_a-z0-9]*(?::?[-?+][^}]*)?"
 
 var patternString = fmt.Sprintf(
 	"%s(?i:(?P<escaped>%s)|(?P<named>%s)|{(?P<braced>%s)}|(?P<invalid>))",
@@ -22,8 +22,14 @@ var DefaultSubstituteFuncs = []SubstituteFunc{
 	hardDefault,
 	requiredNonEmpty,
 	required,
+	softAlternate,
+	hardAlternate,
 }
 
+// operators are the operators that may follow the name of a variable in a
+// braced substitution, longest first
+var operators = []string{":-", ":?", ":+", "-", "?", "+"}
+
 // InvalidTemplateError is returned when a variable template is not in a valid
 // format
 type InvalidTemplateError struct {
@@ -48,44 +54,62 @@ type SubstituteFunc func(string, Mapping) (string, bool, error)
 // SubstituteWith subsitute variables in the string with their values.
 // It accepts additional substitute function.
 func SubstituteWith(template string, mapping Mapping, pattern *regexp.Regexp, subsFuncs ...SubstituteFunc) (string, error) {
-	var err error
-	result := pattern.ReplaceAllStringFunc(template, func(substring string) string {
-		matches := pattern.FindStringSubmatch(substring)
-		groups := matchGroups(matches, pattern)
-		if escaped := groups["escaped"]; escaped != "" {
-			return escaped
-		}
-
-		substitution := groups["named"]
-		if substitution == "" {
-			substitution = groups["braced"]
-		}
-
-		if substitution == "" {
-			err = &InvalidTemplateError{Template: template}
-			return ""
+	var (
+		result strings.Builder
+		last   int
+		err    error
+	)
+	// a substitution that fails is replaced with an empty string, and the
+	// partially substituted template is returned with the first error
+	for _, m := range findAll(template, pattern) {
+		result.WriteString(template[last:m.start])
+		last = m.end
+		if m.escaped != "" {
+			result.WriteString(m.escaped)
+			continue
 		}
-
-		for _, f := range subsFuncs {
-			var (
-				value   string
-				applied bool
-			)
-			value, applied, err = f(substitution, mapping)
-			if err != nil {
-				return ""
+		if m.substitution == "" {
+			if err == nil {
+				err = &InvalidTemplateError{Template: template}
 			}
-			if !applied {
-				continue
+			continue
+		}
+		value, subErr := substitute(m.substitution, mapping, pattern, subsFuncs)
+		if subErr != nil {
+			if err == nil {
+				err = subErr
 			}
-			return value
+			continue
 		}
+		result.WriteString(value)
+	}
+	result.WriteString(template[last:])
+	return result.String(), err
+}
 
-		value, _ := mapping(substitution)
-		return value
-	})
-
-	return result, err
+// substitute applies the first of subsFuncs that applies to substitution. A
+// default or alternate value is itself substituted with pattern and
+// subsFuncs, if it is used.
+func substitute(substitution string, mapping Mapping, pattern *regexp.Regexp, subsFuncs []SubstituteFunc) (string, error) {
+	name, op, arg := splitSubstitution(substitution)
+	if value, ok := mapping(name); usesArgument(op, value, ok) {
+		nested, err := SubstituteWith(arg, mapping, pattern, subsFuncs...)
+		if err != nil {
+			return "", err
+		}
+		substitution = name + op + nested
+	}
+	for _, f := range subsFuncs {
+		value, applied, err := f(substitution, mapping)
+		if err != nil {
+			return "", err
+		}
+		if applied {
+			return value, nil
+		}
+	}
+	value, _ := mapping(substitution)
+	return value, nil
 }
 
 // Substitute variables in the string with their values
@@ -102,6 +126,43 @@ func ExtractVariables(configDict map[string]interface{}, pattern *regexp.Regexp)
 	return recurseExtract(configDict, pattern)
 }
 
+// UnsetVariables returns the names of the variables in template that are
+// referenced without a default or alternate value, and are not set. Variables
+// in a default or alternate value are only returned if that value is used.
+func UnsetVariables(template string, mapping Mapping) []string {
+	var names []string
+	for _, m := range findAll(template, defaultPattern) {
+		if m.substitution == "" {
+			continue
+		}
+		name, op, arg := splitSubstitution(m.substitution)
+		value, ok := mapping(name)
+		switch {
+		case op == "" && !ok:
+			names = append(names, name)
+		case usesArgument(op, value, ok):
+			names = append(names, UnsetVariables(arg, mapping)...)
+		}
+	}
+	return names
+}
+
+// usesArgument returns whether the default or alternate value following op
+// is used, given the value of the variable and whether it is set.
+func usesArgument(op, value string, ok bool) bool {
+	switch op {
+	case ":-":
+		return !ok || value == ""
+	case "-":
+		return !ok
+	case ":+":
+		return ok && value != ""
+	case "+":
+		return ok
+	}
+	return false
+}
+
 func recurseExtract(value interface{}, pattern *regexp.Regexp) map[string]string {
 	m := map[string]string{}
 
@@ -143,44 +204,33 @@ func extractVariable(value interface{}, pattern *regexp.Regexp) ([]extractedValu
 	if !ok {
 		return []extractedValue{}, false
 	}
-	matches := pattern.FindAllStringSubmatch(sValue, -1)
-	if len(matches) == 0 {
-		return []extractedValue{}, false
-	}
 	values := []extractedValue{}
-	for _, match := range matches {
-		groups := matchGroups(match, pattern)
-		if escaped := groups["escaped"]; escaped != "" {
+	for _, m := range findAll(sValue, pattern) {
+		if m.substitution == "" {
 			continue
 		}
-		val := groups["named"]
-		if val == "" {
-			val = groups["braced"]
-		}
-		name := val
+		name, op, arg := splitSubstitution(m.substitution)
 		var defaultValue string
-		switch {
-		case strings.Contains(val, ":?"):
-			name, _ = partition(val, ":?")
-		case strings.Contains(val, "?"):
-			name, _ = partition(val, "?")
-		case strings.Contains(val, ":-"):
-			name, defaultValue = partition(val, ":-")
-		case strings.Contains(val, "-"):
-			name, defaultValue = partition(val, "-")
+		switch op {
+		case ":-", "-":
+			defaultValue = arg
 		}
 		values = append(values, extractedValue{name: name, value: defaultValue})
+		// variables used in a default or alternate value
+		if op != ":?" && op != "?" {
+			nested, _ := extractVariable(arg, pattern)
+			values = append(values, nested...)
+		}
 	}
 	return values, len(values) > 0
 }
 
 // Soft default (fall back if unset or empty)
 func softDefault(substitution string, mapping Mapping) (string, bool, error) {
-	sep := ":-"
-	if !strings.Contains(substitution, sep) {
+	name, defaultValue, ok := operation(substitution, ":-")
+	if !ok {
 		return "", false, nil
 	}
-	name, defaultValue := partition(substitution, sep)
 	value, ok := mapping(name)
 	if !ok || value == "" {
 		return defaultValue, true, nil
@@ -190,11 +240,10 @@ func softDefault(substitution string, mapping Mapping) (string, bool, error) {
 
 // Hard default (fall back if-and-only-if empty)
 func hardDefault(substitution string, mapping Mapping) (string, bool, error) {
-	sep := "-"
-	if !strings.Contains(substitution, sep) {
+	name, defaultValue, ok := operation(substitution, "-")
+	if !ok {
 		return "", false, nil
 	}
-	name, defaultValue := partition(substitution, sep)
 	value, ok := mapping(name)
 	if !ok {
 		return defaultValue, true, nil
@@ -202,6 +251,28 @@ func hardDefault(substitution string, mapping Mapping) (string, bool, error) {
 	return value, true, nil
 }
 
+// Soft alternate (use the alternate value if set and not empty)
+func softAlternate(substitution string, mapping Mapping) (string, bool, error) {
+	return withAlternate(substitution, mapping, ":+", func(v string) bool { return v != "" })
+}
+
+// Hard alternate (use the alternate value if set, even if empty)
+func hardAlternate(substitution string, mapping Mapping) (string, bool, error) {
+	return withAlternate(substitution, mapping, "+", func(_ string) bool { return true })
+}
+
+func withAlternate(substitution string, mapping Mapping, sep string, valid func(string) bool) (string, bool, error) {
+	name, alternateValue, ok := operation(substitution, sep)
+	if !ok {
+		return "", false, nil
+	}
+	value, ok := mapping(name)
+	if !ok || !valid(value) {
+		return "", true, nil
+	}
+	return alternateValue, true, nil
+}
+
 func requiredNonEmpty(substitution string, mapping Mapping) (string, bool, error) {
 	return withRequired(substitution, mapping, ":?", func(v string) bool { return v != "" })
 }
@@ -211,10 +282,10 @@ func required(substitution string, mapping Mapping) (string, bool, error) {
 }
 
 func withRequired(substitution string, mapping Mapping, sep string, valid func(string) bool) (string, bool, error) {
-	if !strings.Contains(substitution, sep) {
+	name, errorMessage, ok := operation(substitution, sep)
+	if !ok {
 		return "", false, nil
 	}
-	name, errorMessage := partition(substitution, sep)
 	value, ok := mapping(name)
 	if !ok || !valid(value) {
 		return "", true, &InvalidTemplateError{
@@ -224,6 +295,122 @@ func withRequired(substitution string, mapping Mapping, sep string, valid func(s
 	return value, true, nil
 }
 
+// match is a substitution found in a template
+type match struct {
+	start, end   int
+	escaped      string
+	substitution string
+}
+
+// findAll returns the substitutions in template matched by pattern. Braced
+// substitutions extend to their matching closing brace, so that they may
+// contain other substitutions, as in "${A:-${B}}". The delimiter of the
+// substitutions is the text the pattern matches before the opening brace of
+// the braced group. The substitution of an invalid or unterminated template
+// is left empty.
+func findAll(template string, pattern *regexp.Regexp) []match {
+	braced := subexpIndex(pattern, "braced")
+	var matches []match
+	for offset := 0; offset < len(template); {
+		loc := pattern.FindStringSubmatchIndex(template[offset:])
+		if loc == nil {
+			break
+		}
+		groups := matchGroups(submatches(template[offset:], loc), pattern)
+		m := match{
+			start:        offset + loc[0],
+			end:          offset + loc[1],
+			escaped:      groups["escaped"],
+			substitution: groups["named"],
+		}
+		if groups["braced"] != "" {
+			m.substitution = ""
+			open := offset + loc[2*braced]
+			delimiter := template[m.start : open-1]
+			if closing := closingBrace(template, open, delimiter); closing >= 0 {
+				m.end = closing + 1
+				m.substitution = template[open:closing]
+			}
+		}
+		matches = append(matches, m)
+		if m.end == offset {
+			break
+		}
+		offset = m.end
+	}
+	return matches
+}
+
+// closingBrace returns the index of the brace closing the braced substitution
+// that starts at start, skipping escaped delimiters and nested substitutions
+// with the same delimiter, or -1 if it is not closed.
+func closingBrace(template string, start int, delimiter string) int {
+	escaped, opening := delimiter+delimiter, delimiter+"{"
+	depth := 0
+	for i := start; i < len(template); i++ {
+		switch {
+		case strings.HasPrefix(template[i:], escaped):
+			i += len(escaped) - 1
+		case strings.HasPrefix(template[i:], opening):
+			depth++
+			i += len(opening) - 1
+		case template[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// subexpIndex returns the index of the named group of pattern, or -1
+func subexpIndex(pattern *regexp.Regexp, name string) int {
+	for i, subexpName := range pattern.SubexpNames() {
+		if subexpName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func submatches(s string, loc []int) []string {
+	matches := make([]string, len(loc)/2)
+	for i := range matches {
+		if loc[2*i] >= 0 {
+			matches[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return matches
+}
+
+// splitSubstitution splits a substitution into the name of the variable, the
+// operator that follows it, if any, and the argument of the operator.
+func splitSubstitution(substitution string) (string, string, string) {
+	i := strings.IndexFunc(substitution, func(r rune) bool {
+		return r != '_' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && !('0' <= r && r <= '9')
+	})
+	if i < 0 {
+		return substitution, "", ""
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(substitution[i:], op) {
+			return substitution[:i], op, substitution[i+len(op):]
+		}
+	}
+	return substitution, "", ""
+}
+
+// operation returns the name of the variable in substitution and the argument
+// of sep, if sep is the operator that follows the name.
+func operation(substitution, sep string) (string, string, bool) {
+	name, op, arg := splitSubstitution(substitution)
+	if op != sep {
+		return "", "", false
+	}
+	return name, arg, true
+}
+
 func matchGroups(matches []string, pattern *regexp.Regexp) map[string]string {
 	groups := make(map[string]string)
 	for i, name := range pattern.SubexpNames()[1:] {
@@ -231,15 +418,3 @@ func matchGroups(matches []string, pattern *regexp.Regexp) map[string]string {
 	}
 	return groups
 }
-
-// Split the string at the first occurrence of sep, and return the part before the separator,
-// and the part after the separator.
-//
-// If the separator is not found, return the string itself, followed by an empty string.
-func partition(s, sep string) (string, string) {
-	if strings.Contains(s, sep) {
-		parts := strings.SplitN(s, sep, 2)
-		return parts[0], parts[1]
-	}
-	return s, ""
-}
@@ -3,8 +3,10 @@ package template
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"testing"
 
+	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 )
@@ -175,8 +177,10 @@ func TestSubstituteWithCustomFunc(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Check(t, is.Equal("ok ", result))
 
-	_, err = SubstituteWith("ok ${NOTHERE}", defaultMapping, defaultPattern, errIsMissing)
+	result, err = SubstituteWith("ok ${NOTHERE} ${FOO}", defaultMapping, defaultPattern, errIsMissing)
 	assert.Check(t, is.ErrorContains(err, "required variable"))
+	// the partially substituted template is returned with the error
+	assert.Check(t, is.Equal("ok  first", result))
 }
 
 func TestExtractVariables(t *testing.T) {
@@ -251,6 +255,25 @@ func TestExtractVariables(t *testing.T) {
 				"bar": "foo",
 			},
 		},
+		{
+			name: "alternate-variable",
+			dict: map[string]interface{}{
+				"foo": "${bar:+foo}",
+			},
+			expected: map[string]string{
+				"bar": "",
+			},
+		},
+		{
+			name: "nested-default-variable",
+			dict: map[string]interface{}{
+				"foo": "${bar:-${baz:-foo}}",
+			},
+			expected: map[string]string{
+				"bar": "${baz:-foo}",
+				"baz": "foo",
+			},
+		},
 		{
 			name: "multiple-values",
 			dict: map[string]interface{}{
@@ -281,3 +304,127 @@ func TestExtractVariables(t *testing.T) {
 		})
 	}
 }
+
+func TestAlternate(t *testing.T) {
+	testCases := []struct {
+		template string
+		expected string
+	}{
+		{template: "ok ${FOO:+alt}", expected: "ok alt"},
+		{template: "ok ${FOO+alt}", expected: "ok alt"},
+		{template: "ok ${BAR:+alt}", expected: "ok "},
+		{template: "ok ${BAR+alt}", expected: "ok alt"},
+		{template: "ok ${missing:+alt}", expected: "ok "},
+		{template: "ok ${missing+alt}", expected: "ok "},
+		{template: "ok ${FOO:+${FOO}-${FOO}}", expected: "ok first-first"},
+	}
+
+	for _, tc := range testCases {
+		result, err := Substitute(tc.template, defaultMapping)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(tc.expected, result), tc.template)
+	}
+}
+
+func TestNestedSubstitution(t *testing.T) {
+	testCases := []struct {
+		template string
+		expected string
+	}{
+		{template: "ok ${missing:-${FOO}}", expected: "ok first"},
+		{template: "ok ${missing:-${BAR:-c}}", expected: "ok c"},
+		{template: "ok ${missing:-${other:-${FOO}}}!", expected: "ok first!"},
+		{template: "ok ${missing:-$${FOO}}", expected: "ok ${FOO}"},
+		{template: "ok ${FOO:+${BAR:-default}}", expected: "ok default"},
+		{template: "${missing:-a}${missing:-${FOO}}", expected: "afirst"},
+		{template: "ok ${FOO:-${missing:?not evaluated}}", expected: "ok first"},
+	}
+
+	for _, tc := range testCases {
+		result, err := Substitute(tc.template, defaultMapping)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(tc.expected, result), tc.template)
+	}
+
+	_, err := Substitute("ok ${missing:-${FOO}", defaultMapping)
+	assert.ErrorContains(t, err, "Invalid template")
+
+	_, err = Substitute("ok ${missing:-${other:?is required}}", defaultMapping)
+	assert.ErrorContains(t, err, "required variable other is missing a value: is required")
+}
+
+func TestUnsetVariables(t *testing.T) {
+	testCases := []struct {
+		template string
+		expected []string
+	}{
+		{template: "${FOO} $BAR $$missing", expected: nil},
+		{template: "$missing ${other} ${missing}", expected: []string{"missing", "other", "missing"}},
+		{template: "${missing:-default} ${missing-default} ${missing:+alt} ${missing?err}", expected: nil},
+		{template: "${missing:-${nested}} ${FOO:-${not_used}}", expected: []string{"nested"}},
+		{template: "${FOO:+${alt}} ${BAR+${alt2}} ${BAR:+${not_used}}", expected: []string{"alt", "alt2"}},
+	}
+
+	for _, tc := range testCases {
+		assert.Check(t, is.DeepEqual(tc.expected, UnsetVariables(tc.template, defaultMapping)), tc.template)
+	}
+}
+
+func TestNestedSubstitutionWithCustomPatternAndFunc(t *testing.T) {
+	pattern := regexp.MustCompile(fmt.Sprintf(
+		"%s(?i:(?P<escaped>%s)|(?P<named>%s)|{(?P<braced>%s)}|(?P<invalid>))",
+		"\\$", "\\$", "[_a-z][_a-z0-9.]*(?::?[-?+][^}]*)?", "[_a-z][_a-z0-9.]*(?::?[-?+][^}]*)?",
+	))
+	mapping := func(name string) (string, bool) {
+		if name == "dotted.name" {
+			return "dotted", true
+		}
+		return defaultMapping(name)
+	}
+	errIsMissing := func(substitution string, mapping Mapping) (string, bool, error) {
+		if _, op, _ := splitSubstitution(substitution); op != "" {
+			return "", false, nil
+		}
+		value, found := mapping(substitution)
+		if !found {
+			return "", true, errors.Errorf("required variable %s is missing a value", substitution)
+		}
+		return value, true, nil
+	}
+	funcs := append([]SubstituteFunc{errIsMissing}, DefaultSubstituteFuncs...)
+
+	result, err := SubstituteWith("ok ${missing:-${dotted.name}} $${FOO}", mapping, pattern, funcs...)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ok dotted ${FOO}", result))
+
+	result, err = SubstituteWith("ok ${FOO:+$${BAR}}", mapping, pattern, funcs...)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ok ${BAR}", result))
+
+	result, err = SubstituteWith("ok ${FOO:-${not_used}}", mapping, pattern, funcs...)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ok first", result))
+
+	_, err = SubstituteWith("ok ${missing:-${other}}", mapping, pattern, funcs...)
+	assert.Check(t, is.Error(err, "required variable other is missing a value"))
+}
+
+func TestNestedSubstitutionWithCustomDelimiter(t *testing.T) {
+	pattern := regexp.MustCompile(fmt.Sprintf(
+		"%s(?i:(?P<escaped>%s)|(?P<named>%s)|{(?P<braced>%s)}|(?P<invalid>))",
+		"%", "%", substitution, substitution,
+	))
+
+	result, err := SubstituteWith("ok %{missing:-%{FOO}} %%{BAR} ${FOO}", defaultMapping, pattern, DefaultSubstituteFuncs...)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ok first %{BAR} ${FOO}", result))
+
+	result, err = SubstituteWith("ok %{FOO:+%%{BAR}}", defaultMapping, pattern, DefaultSubstituteFuncs...)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ok %{BAR}", result))
+
+	// the default delimiter does not open a nested substitution
+	result, err = SubstituteWith("ok %{missing:-${FOO}}", defaultMapping, pattern, DefaultSubstituteFuncs...)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ok ${FOO}", result))
+}
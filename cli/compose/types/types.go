@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -52,6 +53,53 @@ var ForbiddenProperties = map[string]string{
 type ConfigFile struct {
 	Filename string
 	Config   map[string]interface{}
+	// Positions are the positions of the values in the file, if known
+	Positions *Positions
+}
+
+// Path is the path of a value in a Compose file: the keys of the mappings and
+// the indexes of the sequence items that lead to it
+type Path []string
+
+func (p Path) String() string {
+	return strings.Join(p, ".")
+}
+
+// Position is a line and column in a Compose file, starting at 1
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Positions are the position of a value in a Compose file, and the positions
+// of the values it contains. The position of the value of a mapping is the
+// position of its key.
+type Positions struct {
+	// Filename is the file the value is set in
+	Filename string
+	Position
+	// Sequence is whether the value is a sequence, of which Children are
+	// the items
+	Sequence bool
+	// Children are the positions of the values of a mapping, by key, or of
+	// the items of a sequence, by index
+	Children map[string]*Positions
+}
+
+// Lookup returns the positions of the value at path, or of its closest
+// ancestor with a known position, or nil if p is nil
+func (p *Positions) Lookup(path Path) *Positions {
+	if p == nil {
+		return nil
+	}
+	for _, key := range path {
+		child, ok := p.Children[key]
+		if !ok {
+			break
+		}
+		p = child
+	}
+	return p
 }
 
 // ConfigDetails are the details about a group of ConfigFiles
@@ -96,14 +144,15 @@ func (cd ConfigDetails) LookupEnv(key string) (string, bool) {
 
 // Config is a full compose file configuration
 type Config struct {
-	Filename string                     `yaml:"-" json:"-"`
-	Version  string                     `json:"version"`
-	Services Services                   `json:"services"`
-	Networks map[string]NetworkConfig   `yaml:",omitempty" json:"networks,omitempty"`
-	Volumes  map[string]VolumeConfig    `yaml:",omitempty" json:"volumes,omitempty"`
-	Secrets  map[string]SecretConfig    `yaml:",omitempty" json:"secrets,omitempty"`
-	Configs  map[string]ConfigObjConfig `yaml:",omitempty" json:"configs,omitempty"`
-	Extras   map[string]interface{}     `yaml:",inline", json:"-"`
+	Filename  string                     `yaml:"-" json:"-"`
+	Positions *Positions                 `yaml:"-" json:"-"`
+	Version   string                     `json:"version"`
+	Services  Services                   `json:"services"`
+	Networks  map[string]NetworkConfig   `yaml:",omitempty" json:"networks,omitempty"`
+	Volumes   map[string]VolumeConfig    `yaml:",omitempty" json:"volumes,omitempty"`
+	Secrets   map[string]SecretConfig    `yaml:",omitempty" json:"secrets,omitempty"`
+	Configs   map[string]ConfigObjConfig `yaml:",omitempty" json:"configs,omitempty"`
+	Extras    map[string]interface{}     `yaml:",inline", json:"-"`
 }
 
 // MarshalJSON makes Config implement json.Marshaler
@@ -191,6 +240,7 @@ type ServiceConfig struct {
 	Pid             string                           `yaml:",omitempty" json:"pid,omitempty"`
 	Ports           []ServicePortConfig              `yaml:",omitempty" json:"ports,omitempty"`
 	Privileged      bool                             `yaml:",omitempty" json:"privileged,omitempty"`
+	Profiles        []string                         `yaml:",omitempty" json:"profiles,omitempty"`
 	ReadOnly        bool                             `mapstructure:"read_only" yaml:"read_only,omitempty" json:"read_only,omitempty"`
 	Restart         string                           `yaml:",omitempty" json:"restart,omitempty"`
 	Secrets         []ServiceSecretConfig            `yaml:",omitempty" json:"secrets,omitempty"`
@@ -515,6 +565,11 @@ type FileObjectConfig struct {
 	Driver         string                 `yaml:",omitempty" json:"driver,omitempty"`
 	DriverOpts     map[string]string      `mapstructure:"driver_opts" yaml:"driver_opts,omitempty" json:"driver_opts,omitempty"`
 	TemplateDriver string                 `mapstructure:"template_driver" yaml:"template_driver,omitempty" json:"template_driver,omitempty"`
+	// Template is whether File is a Go template, rendered by the client
+	// before the object is created
+	Template bool `yaml:",omitempty" json:"template,omitempty"`
+	// Content is the content of the object, once File is rendered
+	Content string `yaml:"-" json:"-"`
 }
 
 // SecretConfig for a secret
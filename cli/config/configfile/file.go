@@ -24,33 +24,34 @@ const (
 
 // ConfigFile ~/.docker/config.json file info
 type ConfigFile struct {
-	AuthConfigs          map[string]types.AuthConfig  `json:"auths"`
-	HTTPHeaders          map[string]string            `json:"HttpHeaders,omitempty"`
-	PsFormat             string                       `json:"psFormat,omitempty"`
-	ImagesFormat         string                       `json:"imagesFormat,omitempty"`
-	NetworksFormat       string                       `json:"networksFormat,omitempty"`
-	PluginsFormat        string                       `json:"pluginsFormat,omitempty"`
-	VolumesFormat        string                       `json:"volumesFormat,omitempty"`
-	StatsFormat          string                       `json:"statsFormat,omitempty"`
-	DetachKeys           string                       `json:"detachKeys,omitempty"`
-	CredentialsStore     string                       `json:"credsStore,omitempty"`
-	CredentialHelpers    map[string]string            `json:"credHelpers,omitempty"`
-	Filename             string                       `json:"-"` // Note: for internal use only
-	ServiceInspectFormat string                       `json:"serviceInspectFormat,omitempty"`
-	ServicesFormat       string                       `json:"servicesFormat,omitempty"`
-	TasksFormat          string                       `json:"tasksFormat,omitempty"`
-	SecretFormat         string                       `json:"secretFormat,omitempty"`
-	ConfigFormat         string                       `json:"configFormat,omitempty"`
-	NodesFormat          string                       `json:"nodesFormat,omitempty"`
-	PruneFilters         []string                     `json:"pruneFilters,omitempty"`
-	Proxies              map[string]ProxyConfig       `json:"proxies,omitempty"`
-	Experimental         string                       `json:"experimental,omitempty"`
-	StackOrchestrator    string                       `json:"stackOrchestrator,omitempty"`
-	Kubernetes           *KubernetesConfig            `json:"kubernetes,omitempty"`
-	CurrentContext       string                       `json:"currentContext,omitempty"`
-	CLIPluginsExtraDirs  []string                     `json:"cliPluginsExtraDirs,omitempty"`
-	Plugins              map[string]map[string]string `json:"plugins,omitempty"`
-	Aliases              map[string]string            `json:"aliases,omitempty"`
+	AuthConfigs             map[string]types.AuthConfig  `json:"auths"`
+	HTTPHeaders             map[string]string            `json:"HttpHeaders,omitempty"`
+	PsFormat                string                       `json:"psFormat,omitempty"`
+	ImagesFormat            string                       `json:"imagesFormat,omitempty"`
+	NetworksFormat          string                       `json:"networksFormat,omitempty"`
+	PluginsFormat           string                       `json:"pluginsFormat,omitempty"`
+	VolumesFormat           string                       `json:"volumesFormat,omitempty"`
+	StatsFormat             string                       `json:"statsFormat,omitempty"`
+	DetachKeys              string                       `json:"detachKeys,omitempty"`
+	CredentialsStore        string                       `json:"credsStore,omitempty"`
+	CredentialHelpers       map[string]string            `json:"credHelpers,omitempty"`
+	Filename                string                       `json:"-"` // Note: for internal use only
+	ServiceInspectFormat    string                       `json:"serviceInspectFormat,omitempty"`
+	ServicesFormat          string                       `json:"servicesFormat,omitempty"`
+	TasksFormat             string                       `json:"tasksFormat,omitempty"`
+	SecretFormat            string                       `json:"secretFormat,omitempty"`
+	ConfigFormat            string                       `json:"configFormat,omitempty"`
+	NodesFormat             string                       `json:"nodesFormat,omitempty"`
+	PruneFilters            []string                     `json:"pruneFilters,omitempty"`
+	Proxies                 map[string]ProxyConfig       `json:"proxies,omitempty"`
+	Experimental            string                       `json:"experimental,omitempty"`
+	StackOrchestrator       string                       `json:"stackOrchestrator,omitempty"`
+	Kubernetes              *KubernetesConfig            `json:"kubernetes,omitempty"`
+	CurrentContext          string                       `json:"currentContext,omitempty"`
+	CLIPluginsExtraDirs     []string                     `json:"cliPluginsExtraDirs,omitempty"`
+	Plugins                 map[string]map[string]string `json:"plugins,omitempty"`
+	Aliases                 map[string]string            `json:"aliases,omitempty"`
+	BuildContextWarningSize string                       `json:"buildContextWarningSize,omitempty"`
 }
 
 // ProxyConfig contains proxy configuration settings
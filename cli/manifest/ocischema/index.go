@@ -0,0 +1,91 @@
+package ocischema
+
+import (
+	"encoding/json"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// IndexSchemaVersion provides a pre-initialized version structure for OCI
+// image indexes.
+var IndexSchemaVersion = manifest.Versioned{
+	SchemaVersion: 2,
+	MediaType:     ocispec.MediaTypeImageIndex,
+}
+
+// ImageIndex references manifests for various platforms. Unlike a
+// manifestlist.ManifestList, it carries annotations for the index itself.
+//
+// Fetching image indexes is handled by the manifestlist package, which
+// registers the OCI image index media type.
+type ImageIndex struct {
+	manifest.Versioned
+
+	// Manifests references platform specific manifests.
+	Manifests []distribution.Descriptor `json:"manifests"`
+
+	// Annotations contains arbitrary metadata for the image index.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// References returns the distribution descriptors for the referenced image
+// manifests.
+func (i ImageIndex) References() []distribution.Descriptor {
+	return i.Manifests
+}
+
+// DeserializedImageIndex wraps ImageIndex with a copy of the original JSON.
+// It satisfies the distribution.Manifest interface.
+type DeserializedImageIndex struct {
+	ImageIndex
+
+	// canonical is the canonical byte representation of the ImageIndex.
+	canonical []byte
+}
+
+// FromDescriptors takes a slice of descriptors and the annotations of the
+// index, and returns a DeserializedImageIndex which contains the resulting
+// image index and its JSON representation.
+func FromDescriptors(descriptors []distribution.Descriptor, annotations map[string]string) (*DeserializedImageIndex, error) {
+	i := ImageIndex{
+		Versioned:   IndexSchemaVersion,
+		Manifests:   make([]distribution.Descriptor, len(descriptors)),
+		Annotations: annotations,
+	}
+	copy(i.Manifests, descriptors)
+
+	deserialized := DeserializedImageIndex{ImageIndex: i}
+	var err error
+	deserialized.canonical, err = json.MarshalIndent(&i, "", "   ")
+	return &deserialized, err
+}
+
+// UnmarshalJSON populates a new ImageIndex struct from JSON data.
+func (i *DeserializedImageIndex) UnmarshalJSON(b []byte) error {
+	i.canonical = make([]byte, len(b))
+	copy(i.canonical, b)
+
+	var index ImageIndex
+	if err := json.Unmarshal(i.canonical, &index); err != nil {
+		return err
+	}
+	i.ImageIndex = index
+	return nil
+}
+
+// MarshalJSON returns the contents of canonical.
+func (i *DeserializedImageIndex) MarshalJSON() ([]byte, error) {
+	if len(i.canonical) > 0 {
+		return i.canonical, nil
+	}
+	return nil, errors.New("JSON representation not initialized in DeserializedImageIndex")
+}
+
+// Payload returns the raw content of the image index. The contents can be
+// used to calculate the content identifier.
+func (i DeserializedImageIndex) Payload() (string, []byte, error) {
+	return ocispec.MediaTypeImageIndex, i.canonical, nil
+}
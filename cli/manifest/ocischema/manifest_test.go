@@ -0,0 +1,62 @@
+package ocischema
+
+import (
+	"testing"
+
+	"github.com/docker/distribution"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	mfst, err := FromStruct(Manifest{
+		Versioned: SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: ocispec.MediaTypeImageConfig,
+			Digest:    "sha256:7328f6f8b41890597575cbaadc884e7386ae0acc53b747401ebce5cf0d624560",
+			Size:      1520,
+		},
+		Layers: []distribution.Descriptor{{
+			MediaType: ocispec.MediaTypeImageLayerGzip,
+			Digest:    "sha256:88286f41530e93dffd4b964e1db22ce4939fffa4a4c665dab8591fbab03d4926",
+			Size:      1990402,
+		}},
+		Annotations: map[string]string{"com.example.key": "value"},
+	})
+	assert.NilError(t, err)
+
+	mediaType, payload, err := mfst.Payload()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ocispec.MediaTypeImageManifest, mediaType))
+
+	unmarshalled, desc, err := distribution.UnmarshalManifest(ocispec.MediaTypeImageManifest, payload)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ocispec.MediaTypeImageManifest, desc.MediaType))
+	assert.Check(t, is.Len(unmarshalled.References(), 2))
+	assert.Check(t, is.DeepEqual(mfst.Annotations, unmarshalled.(*DeserializedManifest).Annotations))
+}
+
+func TestManifestInvalidMediaType(t *testing.T) {
+	var mfst DeserializedManifest
+	err := mfst.UnmarshalJSON([]byte(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json"}`))
+	assert.ErrorContains(t, err, "mediaType in manifest should be")
+}
+
+func TestImageIndexFromDescriptors(t *testing.T) {
+	index, err := FromDescriptors([]distribution.Descriptor{{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    "sha256:1072e499f3f655a032e88542330cf75b02e7bdf673278f701d7ba61629ee3ebe",
+		Size:      528,
+		Platform:  &ocispec.Platform{OS: "linux", Architecture: "amd64"},
+	}}, map[string]string{"com.example.key": "value"})
+	assert.NilError(t, err)
+
+	mediaType, payload, err := index.Payload()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ocispec.MediaTypeImageIndex, mediaType))
+
+	var unmarshalled DeserializedImageIndex
+	assert.NilError(t, unmarshalled.UnmarshalJSON(payload))
+	assert.Check(t, is.DeepEqual(index.ImageIndex, unmarshalled.ImageIndex))
+}
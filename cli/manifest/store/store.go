@@ -22,8 +22,20 @@ type Store interface {
 	Get(listRef reference.Reference, manifest reference.Reference) (types.ImageManifest, error)
 	GetList(listRef reference.Reference) ([]types.ImageManifest, error)
 	Save(listRef reference.Reference, manifest reference.Reference, image types.ImageManifest) error
+	List() ([]reference.Reference, error)
+	GetListMetadata(listRef reference.Reference) (types.ManifestListMetadata, error)
+	SaveListMetadata(listRef reference.Reference, metadata types.ManifestListMetadata) error
 }
 
+const (
+	// listRefFilename is the file in a manifest list directory that records
+	// the reference of the list, as directory names cannot be mapped back to it.
+	listRefFilename = ".list-ref"
+	// listMetadataFilename is the file in a manifest list directory that
+	// holds the settings of the list itself.
+	listMetadataFilename = ".list-metadata"
+)
+
 // fsStore manages manifest files stored on the local filesystem
 type fsStore struct {
 	root string
@@ -122,6 +134,9 @@ func (s *fsStore) listManifests(transaction string) ([]string, error) {
 
 	filenames := []string{}
 	for _, info := range fileInfos {
+		if strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
 		filenames = append(filenames, info.Name())
 	}
 	return filenames, nil
@@ -142,7 +157,71 @@ func (s *fsStore) Save(listRef reference.Reference, manifest reference.Reference
 
 func (s *fsStore) createManifestListDirectory(transaction string) error {
 	path := filepath.Join(s.root, makeFilesafeName(transaction))
-	return os.MkdirAll(path, 0755)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(path, listRefFilename), []byte(transaction), 0644)
+}
+
+// GetListMetadata returns the settings of a local manifest list. Lists without
+// settings, including lists that do not exist, return the zero value.
+func (s *fsStore) GetListMetadata(listRef reference.Reference) (types.ManifestListMetadata, error) {
+	var metadata types.ManifestListMetadata
+	filename := filepath.Join(s.root, makeFilesafeName(listRef.String()), listMetadataFilename)
+	bytes, err := ioutil.ReadFile(filename)
+	switch {
+	case os.IsNotExist(err):
+		return metadata, nil
+	case err != nil:
+		return metadata, err
+	}
+	err = json.Unmarshal(bytes, &metadata)
+	return metadata, err
+}
+
+// SaveListMetadata saves the settings of a local manifest list
+func (s *fsStore) SaveListMetadata(listRef reference.Reference, metadata types.ManifestListMetadata) error {
+	if err := s.createManifestListDirectory(listRef.String()); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(s.root, makeFilesafeName(listRef.String()), listMetadataFilename)
+	return ioutil.WriteFile(filename, bytes, 0644)
+}
+
+// List returns the references of all manifest lists in local storage
+func (s *fsStore) List() ([]reference.Reference, error) {
+	fileInfos, err := ioutil.ReadDir(s.root)
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	refs := []reference.Reference{}
+	for _, info := range fileInfos {
+		if !info.IsDir() {
+			continue
+		}
+		name := info.Name()
+		if raw, err := ioutil.ReadFile(filepath.Join(s.root, name, listRefFilename)); err == nil {
+			name = string(raw)
+		} else {
+			// Lists created by older versions do not record their reference;
+			// make a best effort to recover it from the directory name.
+			name = unmakeFilesafeName(name)
+		}
+		ref, err := reference.Parse(name)
+		if err != nil {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
 }
 
 func manifestToFilename(root, manifestList, manifest string) string {
@@ -154,6 +233,17 @@ func makeFilesafeName(ref string) string {
 	return strings.Replace(fileName, "/", "_", -1)
 }
 
+// unmakeFilesafeName reverses makeFilesafeName, assuming that the last "-"
+// after the last "_" separates the tag from the name. This is ambiguous for
+// names and tags containing "-" or "_", and only used as a fallback.
+func unmakeFilesafeName(fileName string) string {
+	ref := strings.Replace(fileName, "_", "/", -1)
+	if i := strings.LastIndex(ref, "-"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i] + ":" + ref[i+1:]
+	}
+	return ref
+}
+
 type notFoundError struct {
 	object string
 }
@@ -3,6 +3,7 @@ package store
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/docker/cli/cli/manifest/types"
@@ -134,3 +135,64 @@ func TestStoreGetListDoesNotExist(t *testing.T) {
 	assert.Error(t, err, "No such manifest: list")
 	assert.Check(t, IsNotFound(err))
 }
+
+func TestStoreList(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	refs, err := store.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(refs, 0))
+
+	data := types.ImageManifest{Ref: sref(t, "abcdef")}
+	assert.NilError(t, store.Save(ref("example.com/my_app:v1-rc"), ref("manifest"), data))
+	assert.NilError(t, store.Save(ref("example.com/other:latest"), ref("manifest"), data))
+
+	refs, err = store.List()
+	assert.NilError(t, err)
+	var names []string
+	for _, r := range refs {
+		names = append(names, r.String())
+	}
+	assert.Check(t, is.DeepEqual([]string{"example.com/my_app:v1-rc", "example.com/other:latest"}, names))
+
+	// The recorded reference is not returned as a manifest of the list
+	list, err := store.GetList(ref("example.com/other:latest"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(list, 1))
+}
+
+func TestStoreListWithoutRecordedReference(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	root := store.(*fsStore).root
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "example.com_foo-bar-1.0"), 0755))
+
+	refs, err := store.List()
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(refs, 1))
+	assert.Check(t, is.Equal("example.com/foo-bar:1.0", refs[0].String()))
+}
+
+func TestStoreListMetadata(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	listRef := ref("list")
+	metadata, err := store.GetListMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(types.ManifestListMetadata{}, metadata))
+
+	expected := types.ManifestListMetadata{OCI: true, Annotations: map[string]string{"foo": "bar"}}
+	assert.NilError(t, store.SaveListMetadata(listRef, expected))
+	assert.NilError(t, store.Save(listRef, ref("manifest"), types.ImageManifest{Ref: sref(t, "abcdef")}))
+
+	metadata, err = store.GetListMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(expected, metadata))
+
+	list, err := store.GetList(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(list, 1))
+}
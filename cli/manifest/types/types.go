@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 
+	"github.com/docker/cli/cli/manifest/ocischema"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/schema2"
@@ -20,6 +21,20 @@ type ImageManifest struct {
 	// SchemaV2Manifest is used for inspection
 	// TODO: Deprecate this and store manifest blobs
 	SchemaV2Manifest *schema2.DeserializedManifest `json:",omitempty"`
+
+	// OCIManifest is used for inspection of OCI image manifests
+	OCIManifest *ocischema.DeserializedManifest `json:",omitempty"`
+}
+
+// ManifestListMetadata contains the settings of a local manifest list that
+// apply to the list itself, rather than to the manifests it references.
+type ManifestListMetadata struct {
+	// OCI is set if the list is pushed as an OCI image index instead of
+	// a Docker manifest list.
+	OCI bool `json:",omitempty"`
+
+	// Annotations are set on the OCI image index.
+	Annotations map[string]string `json:",omitempty"`
 }
 
 // OCIPlatform creates an OCI platform from a manifest list platform spec
@@ -53,7 +68,7 @@ func PlatformSpecFromOCI(p *ocispec.Platform) *manifestlist.PlatformSpec {
 // Blobs returns the digests for all the blobs referenced by this manifest
 func (i ImageManifest) Blobs() []digest.Digest {
 	digests := []digest.Digest{}
-	for _, descriptor := range i.SchemaV2Manifest.References() {
+	for _, descriptor := range i.References() {
 		digests = append(digests, descriptor.Digest)
 	}
 	return digests
@@ -65,6 +80,8 @@ func (i ImageManifest) Payload() (string, []byte, error) {
 	switch {
 	case i.SchemaV2Manifest != nil:
 		return i.SchemaV2Manifest.Payload()
+	case i.OCIManifest != nil:
+		return i.OCIManifest.Payload()
 	default:
 		return "", nil, errors.Errorf("%s has no payload", i.Ref)
 	}
@@ -76,6 +93,8 @@ func (i ImageManifest) References() []distribution.Descriptor {
 	switch {
 	case i.SchemaV2Manifest != nil:
 		return i.SchemaV2Manifest.References()
+	case i.OCIManifest != nil:
+		return i.OCIManifest.References()
 	default:
 		return nil
 	}
@@ -91,6 +110,16 @@ func NewImageManifest(ref reference.Named, desc ocispec.Descriptor, manifest *sc
 	}
 }
 
+// NewOCIImageManifest returns a new ImageManifest object for an OCI image
+// manifest. The values for Platform are initialized from those in the image
+func NewOCIImageManifest(ref reference.Named, desc ocispec.Descriptor, manifest *ocischema.DeserializedManifest) ImageManifest {
+	return ImageManifest{
+		Ref:         &SerializableNamed{Named: ref},
+		Descriptor:  desc,
+		OCIManifest: manifest,
+	}
+}
+
 // SerializableNamed is a reference.Named that can be serialized and deserialized
 // from JSON
 type SerializableNamed struct {
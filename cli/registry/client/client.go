@@ -14,6 +14,7 @@ import (
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -23,6 +24,8 @@ import (
 type RegistryClient interface {
 	GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	GetManifestListPayload(ctx context.Context, ref reference.Named) (string, []byte, error)
+	GetManifestDescriptor(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error)
 	MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error
 	PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error)
 	GetTags(ctx context.Context, ref reference.Named) ([]string, error)
@@ -186,6 +189,38 @@ func (c *client) GetManifestList(ctx context.Context, ref reference.Named) ([]ma
 	return result, err
 }
 
+// GetManifestListPayload returns the media type and the content of the
+// manifest list or OCI image index the reference points to, as fetched from
+// the registry
+func (c *client) GetManifestListPayload(ctx context.Context, ref reference.Named) (string, []byte, error) {
+	var (
+		mediaType string
+		payload   []byte
+	)
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		var err error
+		mediaType, payload, err = fetchListPayload(ctx, repo, ref)
+		return len(payload) > 0, err
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return mediaType, payload, err
+}
+
+// GetManifestDescriptor returns the descriptor of the manifest or manifest
+// list the reference points to, without fetching the referenced manifests
+func (c *client) GetManifestDescriptor(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	var result ocispec.Descriptor
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		var err error
+		result, err = fetchDescriptor(ctx, repo, ref)
+		return result.Digest != "", err
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return result, err
+}
+
 func getManifestOptionsFromReference(ref reference.Named) (digest.Digest, []distribution.ManifestServiceOption, error) {
 	if tagged, isTagged := ref.(reference.NamedTagged); isTagged {
 		tag := tagged.Tag()
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/docker/cli/cli/manifest/ocischema"
 	"github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/manifestlist"
@@ -36,6 +37,8 @@ func fetchManifest(ctx context.Context, repo distribution.Repository, ref refere
 			return types.ImageManifest{}, err
 		}
 		return imageManifest, nil
+	case *ocischema.DeserializedManifest:
+		return pullManifestOCI(ctx, ref, repo, *v)
 	case *manifestlist.DeserializedManifestList:
 		return types.ImageManifest{}, errors.Errorf("%s is a manifest list", ref)
 	}
@@ -60,6 +63,30 @@ func fetchList(ctx context.Context, repo distribution.Repository, ref reference.
 	}
 }
 
+func fetchListPayload(ctx context.Context, repo distribution.Repository, ref reference.Named) (string, []byte, error) {
+	manifest, err := getManifest(ctx, repo, ref)
+	if err != nil {
+		return "", nil, err
+	}
+
+	v, ok := manifest.(*manifestlist.DeserializedManifestList)
+	if !ok {
+		return "", nil, errors.Errorf("unsupported manifest format: %v", manifest)
+	}
+	if _, err := validateManifestDigest(ref, v); err != nil {
+		return "", nil, err
+	}
+	return v.Payload()
+}
+
+func fetchDescriptor(ctx context.Context, repo distribution.Repository, ref reference.Named) (ocispec.Descriptor, error) {
+	manifest, err := getManifest(ctx, repo, ref)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return validateManifestDigest(ref, manifest)
+}
+
 func getManifest(ctx context.Context, repo distribution.Repository, ref reference.Named) (distribution.Manifest, error) {
 	manSvc, err := repo.Manifests(ctx)
 	if err != nil {
@@ -95,6 +122,28 @@ func pullManifestSchemaV2(ctx context.Context, ref reference.Named, repo distrib
 	return types.NewImageManifest(ref, manifestDesc, &mfst), nil
 }
 
+func pullManifestOCI(ctx context.Context, ref reference.Named, repo distribution.Repository, mfst ocischema.DeserializedManifest) (types.ImageManifest, error) {
+	manifestDesc, err := validateManifestDigest(ref, mfst)
+	if err != nil {
+		return types.ImageManifest{}, err
+	}
+	configJSON, err := pullManifestSchemaV2ImageConfig(ctx, mfst.Target().Digest, repo)
+	if err != nil {
+		return types.ImageManifest{}, err
+	}
+
+	if manifestDesc.Platform == nil {
+		manifestDesc.Platform = &ocispec.Platform{}
+	}
+
+	// Fill in os and architecture fields from config JSON
+	if err := json.Unmarshal(configJSON, manifestDesc.Platform); err != nil {
+		return types.ImageManifest{}, err
+	}
+
+	return types.NewOCIImageManifest(ref, manifestDesc, &mfst), nil
+}
+
 func pullManifestSchemaV2ImageConfig(ctx context.Context, dgst digest.Digest, repo distribution.Repository) ([]byte, error) {
 	blobs := repo.Blobs(ctx)
 	configJSON, err := blobs.Get(ctx, dgst)
@@ -157,22 +206,27 @@ func pullManifestList(ctx context.Context, ref reference.Named, repo distributio
 		if err != nil {
 			return nil, err
 		}
-		v, ok := manifest.(*schema2.DeserializedManifest)
-		if !ok {
-			return nil, fmt.Errorf("unsupported manifest format: %v", v)
-		}
 
 		manifestRef, err := reference.WithDigest(ref, manifestDescriptor.Digest)
 		if err != nil {
 			return nil, err
 		}
-		imageManifest, err := pullManifestSchemaV2(ctx, manifestRef, repo, *v)
+		var imageManifest types.ImageManifest
+		switch v := manifest.(type) {
+		case *schema2.DeserializedManifest:
+			imageManifest, err = pullManifestSchemaV2(ctx, manifestRef, repo, *v)
+		case *ocischema.DeserializedManifest:
+			imageManifest, err = pullManifestOCI(ctx, manifestRef, repo, *v)
+		default:
+			return nil, fmt.Errorf("unsupported manifest format: %v", v)
+		}
 		if err != nil {
 			return nil, err
 		}
 
 		// Replace platform from config
 		imageManifest.Descriptor.Platform = types.OCIPlatform(&manifestDescriptor.Platform)
+		imageManifest.Descriptor.Annotations = manifestDescriptor.Annotations
 
 		infos = append(infos, imageManifest)
 	}
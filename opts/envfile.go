@@ -20,3 +20,13 @@ import (
 func ParseEnvFile(filename string) ([]string, error) {
 	return parseKeyValueFile(filename, os.LookupEnv)
 }
+
+// ParseDotEnvFile reads a file with environment variables used to interpolate
+// Compose files, such as a ".env" file. The format is the same as for
+// ParseEnvFile, except that values may be enclosed in single or double quotes,
+// which are removed. Double quoted values may contain the escape sequences
+// \\, \", \n, \r, \t and \$. Quoted values may span multiple lines, and be
+// followed by a comment.
+func ParseDotEnvFile(filename string) ([]string, error) {
+	return parseKeyValueFileWithQuotes(filename, os.LookupEnv, true)
+}
@@ -176,3 +176,89 @@ func TestParseEnvVariableWithNoNameFile(t *testing.T) {
 		t.Fatal("if a variable has no name parsing an environment file must fail")
 	}
 }
+
+// Test ParseDotEnvFile for a file with quoted and multi-line values
+func TestParseDotEnvFileQuotedValues(t *testing.T) {
+	content := `plain=value
+double="quoted value"
+single='quoted value'
+escaped="tab\there \"quoted\" \$HOME \\ \x"
+literal='no \n escapes'
+commented="value" # a comment
+multiline="first line
+second line"
+multisingle='first
+second'
+empty=""
+unquoted=va"lue"
+`
+
+	tmpFile := tmpFileWithContent(content, t)
+	defer os.Remove(tmpFile)
+
+	lines, err := ParseDotEnvFile(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLines := []string{
+		"plain=value",
+		"double=quoted value",
+		"single=quoted value",
+		`escaped=tab	here "quoted" $HOME \ \x`,
+		`literal=no \n escapes`,
+		"commented=value",
+		"multiline=first line\nsecond line",
+		"multisingle=first\nsecond",
+		"empty=",
+		`unquoted=va"lue"`,
+	}
+
+	if !reflect.DeepEqual(lines, expectedLines) {
+		t.Fatalf("lines not equal to expectedLines\nlines: %q\nexpected: %q", lines, expectedLines)
+	}
+}
+
+// Test ParseDotEnvFile for badly quoted values
+func TestParseDotEnvFileBadlyQuotedValues(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected string
+	}{
+		{
+			content:  "foo=bar\nunterminated=\"value\nmore\n",
+			expected: "unterminated quoted value for 'unterminated' starting at line 2",
+		},
+		{
+			content:  "trailing='value' more\n",
+			expected: "unexpected characters after quoted value: 'more' at line 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tmpFile := tmpFileWithContent(tc.content, t)
+		defer os.Remove(tmpFile)
+
+		_, err := ParseDotEnvFile(tmpFile)
+		if err == nil {
+			t.Fatalf("ParseDotEnvFile succeeded; expected failure")
+		}
+		if !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf("Expected error containing [%v], got [%v]", tc.expected, err.Error())
+		}
+	}
+}
+
+// Test ParseEnvFile does not unquote values
+func TestParseEnvFileQuotedValues(t *testing.T) {
+	tmpFile := tmpFileWithContent(`foo="bar"`, t)
+	defer os.Remove(tmpFile)
+
+	lines, err := ParseEnvFile(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{`foo="bar"`}; !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Expected %q, got %q", expected, lines)
+	}
+}
@@ -22,6 +22,13 @@ func (e ErrBadKey) Error() string {
 }
 
 func parseKeyValueFile(filename string, emptyFn func(string) (string, bool)) ([]string, error) {
+	return parseKeyValueFileWithQuotes(filename, emptyFn, false)
+}
+
+// parseKeyValueFileWithQuotes parses a file of key=value pairs. If quotes is
+// set, values enclosed in single or double quotes are unquoted; quoted values
+// may span multiple lines.
+func parseKeyValueFileWithQuotes(filename string, emptyFn func(string) (string, bool), quotes bool) ([]string, error) {
 	fh, err := os.Open(filename)
 	if err != nil {
 		return []string{}, err
@@ -58,8 +65,31 @@ func parseKeyValueFile(filename string, emptyFn func(string) (string, bool)) ([]
 			}
 
 			if len(data) > 1 {
+				value := data[1]
+				if quotes && len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+					startLine := currentLine
+					for {
+						var ok bool
+						value, ok, err = unquoteValue(data[1])
+						if err != nil {
+							return []string{}, fmt.Errorf("env file %s: %v at line %d", filename, err, currentLine)
+						}
+						if ok {
+							break
+						}
+						// the quoted value continues on the next line
+						if !scanner.Scan() {
+							return []string{}, fmt.Errorf("env file %s: unterminated quoted value for '%s' starting at line %d", filename, variable, startLine)
+						}
+						currentLine++
+						if !utf8.Valid(scanner.Bytes()) {
+							return []string{}, fmt.Errorf("env file %s contains invalid utf8 bytes at line %d: %v", filename, currentLine, scanner.Bytes())
+						}
+						data[1] += "\n" + scanner.Text()
+					}
+				}
 				// pass the value through, no trimming
-				lines = append(lines, fmt.Sprintf("%s=%s", variable, data[1]))
+				lines = append(lines, fmt.Sprintf("%s=%s", variable, value))
 			} else {
 				var value string
 				var present bool
@@ -75,3 +105,42 @@ func parseKeyValueFile(filename string, emptyFn func(string) (string, bool)) ([]
 	}
 	return lines, scanner.Err()
 }
+
+// unquoteValue unquotes a value enclosed in single or double quotes. In double
+// quoted values, the escape sequences \\, \", \n, \r, \t and \$ are
+// interpreted; single quoted values are taken literally. Only whitespace and
+// a comment may follow the closing quote. It returns false if the closing quote
+// was not found.
+func unquoteValue(quoted string) (string, bool, error) {
+	quote := quoted[0]
+	var value strings.Builder
+	for i := 1; i < len(quoted); i++ {
+		c := quoted[i]
+		switch {
+		case c == quote:
+			rest := strings.TrimLeft(quoted[i+1:], whiteSpaces)
+			if rest != "" && !strings.HasPrefix(rest, "#") {
+				return "", false, fmt.Errorf("unexpected characters after quoted value: '%s'", rest)
+			}
+			return value.String(), true, nil
+		case c == '\\' && quote == '"' && i+1 < len(quoted):
+			i++
+			switch quoted[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '\\', '"', '$':
+				value.WriteByte(quoted[i])
+			default:
+				value.WriteByte(c)
+				value.WriteByte(quoted[i])
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", false, nil
+}